@@ -12,7 +12,9 @@ import (
 
 // API related actions
 const (
-	ActionProvisioningReload = "provisioning:reload"
+	ActionProvisioningRead           = "provisioning:read"
+	ActionProvisioningReload         = "provisioning:reload"
+	ActionProvisioningSecretsEncrypt = "provisioning.secrets:encrypt"
 
 	ActionOrgsRead             = "orgs:read"
 	ActionOrgsPreferencesRead  = "orgs.preferences:read"
@@ -43,18 +45,38 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		return err
 	}
 
+	provisioningReaderRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:provisioning:reader",
+			DisplayName: "Provisioning reader",
+			Description: "Read the status and configuration of provisioning.",
+			Group:       "Provisioning",
+			Permissions: []ac.Permission{
+				{
+					Action: ActionProvisioningRead,
+					Scope:  ScopeProvisionersAll,
+				},
+			},
+		},
+		Grants: []string{ac.RoleGrafanaAdmin},
+	}
+
 	provisioningWriterRole := ac.RoleRegistration{
 		Role: ac.RoleDTO{
 			Name:        "fixed:provisioning:writer",
 			DisplayName: "Provisioning writer",
-			Description: "Reload provisioning.",
+			Description: "Reload provisioning and encrypt the secrets of provisioned dashboards.",
 			Group:       "Provisioning",
-			Permissions: []ac.Permission{
+			Permissions: ac.ConcatPermissions(provisioningReaderRole.Role.Permissions, []ac.Permission{
 				{
 					Action: ActionProvisioningReload,
 					Scope:  ScopeProvisionersAll,
 				},
-			},
+				{
+					Action: ActionProvisioningSecretsEncrypt,
+					Scope:  ScopeProvisionersDashboards,
+				},
+			}),
 		},
 		Grants: []string{ac.RoleGrafanaAdmin},
 	}
@@ -395,7 +417,7 @@ func (hs *HTTPServer) declareFixedRoles() error {
 	}
 
 	return hs.AccessControl.DeclareFixedRoles(
-		provisioningWriterRole, datasourcesReaderRole, datasourcesWriterRole,
+		provisioningReaderRole, provisioningWriterRole, datasourcesReaderRole, datasourcesWriterRole,
 		datasourcesIdReaderRole, orgReaderRole, orgWriterRole,
 		orgMaintainerRole, teamsCreatorRole, teamsWriterRole, datasourcesExplorerRole,
 		annotationsReaderRole, dashboardAnnotationsWriterRole, annotationsWriterRole,
@@ -2,20 +2,224 @@ package api
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func (hs *HTTPServer) AdminProvisioningReloadDashboards(c *models.ReqContext) response.Response {
-	err := hs.ProvisioningService.ProvisionDashboards(c.Req.Context())
+	var err error
+	// force saves every dashboard again, once, even if its file didn't change
+	if c.QueryBool("force") {
+		err = hs.ProvisioningService.ReprovisionDashboards(c.Req.Context())
+	} else {
+		err = hs.ProvisioningService.ProvisionDashboards(c.Req.Context())
+	}
 	if err != nil && !errors.Is(err, context.Canceled) {
 		return response.Error(500, "", err)
 	}
 	return response.Success("Dashboards config reloaded")
 }
 
+func (hs *HTTPServer) AdminProvisioningDashboardsStatus(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, hs.ProvisioningService.GetDashboardProvisionersStatus())
+}
+
+func (hs *HTTPServer) AdminProvisioningDashboardsStatusByName(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	for _, status := range hs.ProvisioningService.GetDashboardProvisionersStatus() {
+		if status.Name == name {
+			return response.JSON(http.StatusOK, status)
+		}
+	}
+	return response.Error(http.StatusNotFound, "Dashboard provisioner not found", nil)
+}
+
+// AdminProvisioningDashboardsConfig returns the effective configuration of every dashboard provider, once the
+// defaults were applied to their config files.
+func (hs *HTTPServer) AdminProvisioningDashboardsConfig(c *models.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, hs.ProvisioningService.GetDashboardProvidersConfig())
+}
+
+// AdminProvisioningDashboardsProvisioned returns the dashboards provisioned by the provider with the given name,
+// along with the checksum and modification time of the file they were provisioned from.
+func (hs *HTTPServer) AdminProvisioningDashboardsProvisioned(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	provisioned, err := hs.dashboardProvisioningService.GetProvisionedDashboards(c.Req.Context(), name)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get provisioned dashboards", err)
+	}
+	return response.JSON(http.StatusOK, provisioned)
+}
+
+// AdminProvisioningDashboardsHealth responds with 503 when the path of any dashboard provisioner has been
+// unreadable for too long, listing the unhealthy provisioners.
+func (hs *HTTPServer) AdminProvisioningDashboardsHealth(c *models.ReqContext) response.Response {
+	unhealthy := []dashboards.ProvisionerStatus{}
+	for _, status := range hs.ProvisioningService.GetDashboardProvisionersStatus() {
+		if !status.Healthy {
+			unhealthy = append(unhealthy, status)
+		}
+	}
+	if len(unhealthy) > 0 {
+		return response.JSON(http.StatusServiceUnavailable, unhealthy)
+	}
+	return response.JSON(http.StatusOK, unhealthy)
+}
+
+// provisioningValidateMaxBodySize is the largest dashboard file accepted by the dashboard validation endpoint.
+const provisioningValidateMaxBodySize = 10 * 1024 * 1024
+
+// AdminProvisioningValidateDashboard reads the dashboard file in the request body the same way the dashboard
+// provisioner with the given name would provision it from the `path` query parameter, without saving anything.
+// It responds with the dashboard as it would be saved, or with the reasons it wouldn't be.
+func (hs *HTTPServer) AdminProvisioningValidateDashboard(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	content, err := ioutil.ReadAll(http.MaxBytesReader(c.Resp, c.Req.Body, provisioningValidateMaxBodySize))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to read request body", err)
+	}
+
+	validation, err := hs.ProvisioningService.ValidateDashboard(name, c.Query("path"), content)
+	if err != nil {
+		switch {
+		case errors.Is(err, dashboards.ErrProvisionerNotFound):
+			return response.Error(http.StatusNotFound, "Dashboard provisioner not found", err)
+		case errors.Is(err, dashboards.ErrFileOutsidePath):
+			return response.Error(http.StatusBadRequest, "Path must be within the path of the provisioner", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to validate dashboard", err)
+	}
+	return response.JSON(http.StatusOK, validation)
+}
+
+// AdminProvisioningPromoteDashboardsCanary switches the dashboard provisioner with the given name from its canary
+// to its whole path.
+func (hs *HTTPServer) AdminProvisioningPromoteDashboardsCanary(c *models.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	if err := hs.ProvisioningService.PromoteDashboardProvisionerCanary(name); err != nil {
+		switch {
+		case errors.Is(err, dashboards.ErrProvisionerNotFound):
+			return response.Error(http.StatusNotFound, "Dashboard provisioner not found", err)
+		case errors.Is(err, dashboards.ErrNoCanary):
+			return response.Error(http.StatusBadRequest, "Dashboard provisioner has no canary to promote", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to promote canary", err)
+	}
+	return response.Success("Dashboard provisioner canary promoted")
+}
+
+// provisioningWebhookMaxBodySize is the largest request body accepted by the dashboard provisioning webhook.
+const provisioningWebhookMaxBodySize = 64 * 1024
+
+// provisioningWebhookMaxAge is how far the timestamp of a dashboard provisioning webhook request may be from the
+// current time, which bounds how long a captured request can be replayed.
+const provisioningWebhookMaxAge = 5 * time.Minute
+
+type provisioningWebhookForm struct {
+	// Provisioner is the name of the dashboard provisioner to run, all of them are run when empty.
+	Provisioner string `json:"provisioner"`
+}
+
+// ProvisioningDashboardsWebhook runs the dashboard provisioners right away, responding with their status once the
+// run finished. Requests are authenticated by the HMAC-SHA256 signature of their timestamp and body computed with
+// the provisioning_webhook_secret setting, in the `X-Grafana-Signature` header, rather than by a signed in user.
+// Requests whose `X-Grafana-Timestamp` header is older than provisioningWebhookMaxAge are rejected.
+func (hs *HTTPServer) ProvisioningDashboardsWebhook(c *models.ReqContext) response.Response {
+	secret := hs.Cfg.ProvisioningWebhookSecret
+	if secret == "" {
+		return response.Error(http.StatusNotFound, "Not found", nil)
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(c.Resp, c.Req.Body, provisioningWebhookMaxBodySize))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to read request body", err)
+	}
+	timestamp := c.Req.Header.Get("X-Grafana-Timestamp")
+	if !validProvisioningWebhookSignature(secret, timestamp, body, c.Req.Header.Get("X-Grafana-Signature")) {
+		return response.Error(http.StatusUnauthorized, "Invalid signature", nil)
+	}
+	if !freshProvisioningWebhookTimestamp(timestamp, time.Now()) {
+		return response.Error(http.StatusUnauthorized, "Expired request", nil)
+	}
+
+	form := provisioningWebhookForm{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &form); err != nil {
+			return response.Error(http.StatusBadRequest, "bad request data", err)
+		}
+	}
+
+	statuses, err := hs.ProvisioningService.RunDashboardProvisioners(c.Req.Context(), form.Provisioner)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrProvisionerNotFound) {
+			return response.Error(http.StatusNotFound, "Dashboard provisioner not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to run dashboard provisioners", err)
+	}
+	return response.JSON(http.StatusOK, statuses)
+}
+
+// validProvisioningWebhookSignature checks that signature is `sha256=` followed by the hex encoded HMAC-SHA256 of
+// the timestamp, a dot and the body, computed with secret.
+func validProvisioningWebhookSignature(secret string, timestamp string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hmac.Equal(decoded, mac.Sum(nil))
+}
+
+// freshProvisioningWebhookTimestamp checks that timestamp, in unix seconds, is within provisioningWebhookMaxAge of
+// now.
+func freshProvisioningWebhookTimestamp(timestamp string, now time.Time) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	return age <= provisioningWebhookMaxAge && age >= -provisioningWebhookMaxAge
+}
+
+type encryptDashboardSecretForm struct {
+	Value string `json:"value" binding:"Required"`
+}
+
+// AdminProvisioningEncryptDashboardSecret encrypts a value with the secrets service, returning the `$secret`
+// object to embed in a provisioned dashboard file in place of the value.
+func (hs *HTTPServer) AdminProvisioningEncryptDashboardSecret(c *models.ReqContext) response.Response {
+	form := encryptDashboardSecretForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	encrypted, err := hs.SecretsService.Encrypt(c.Req.Context(), []byte(form.Value), secrets.WithoutScope())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to encrypt secret", err)
+	}
+	return response.JSON(http.StatusOK, map[string]string{"$secret": base64.StdEncoding.EncodeToString(encrypted)})
+}
+
 func (hs *HTTPServer) AdminProvisioningReloadDatasources(c *models.ReqContext) response.Response {
 	err := hs.ProvisioningService.ProvisionDatasources(c.Req.Context())
 	if err != nil {
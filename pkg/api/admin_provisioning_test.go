@@ -1,14 +1,24 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -195,3 +205,156 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 		})
 	}
 }
+
+func TestAPI_AdminProvisioningDashboardsRead_AccessControl(t *testing.T) {
+	tests := []accessControlTestCase{
+		{
+			desc:         "should work with the read permission",
+			expectedCode: http.StatusOK,
+			url:          "/api/admin/provisioning/dashboards/status",
+			method:       http.MethodGet,
+			permissions:  []accesscontrol.Permission{{Action: ActionProvisioningRead, Scope: ScopeProvisionersDashboards}},
+		},
+		{
+			desc:         "should work with the read permission on every provisioner",
+			expectedCode: http.StatusOK,
+			url:          "/api/admin/provisioning/dashboards/health",
+			method:       http.MethodGet,
+			permissions:  []accesscontrol.Permission{{Action: ActionProvisioningRead, Scope: ScopeProvisionersAll}},
+		},
+		{
+			desc:         "should fail with the reload permission only",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/dashboards/config",
+			method:       http.MethodGet,
+			permissions:  []accesscontrol.Permission{{Action: ActionProvisioningReload, Scope: ScopeProvisionersDashboards}},
+		},
+		{
+			desc:         "should fail without permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/dashboards/status",
+			method:       http.MethodGet,
+		},
+	}
+
+	cfg := setting.NewCfg()
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			sc, hs := setupAccessControlScenarioContext(t, cfg, test.url, test.permissions)
+			hs.ProvisioningService = provisioning.NewProvisioningServiceMock(context.Background())
+
+			sc.resp = httptest.NewRecorder()
+			var err error
+			sc.req, err = http.NewRequest(test.method, test.url, nil)
+			assert.NoError(t, err)
+
+			sc.exec()
+
+			assert.Equal(t, test.expectedCode, sc.resp.Code)
+		})
+	}
+}
+
+func TestAPI_AdminProvisioningEncryptDashboardSecret_AccessControl(t *testing.T) {
+	tests := []accessControlTestCase{
+		{
+			desc:         "should work with the encrypt permission",
+			expectedCode: http.StatusOK,
+			permissions:  []accesscontrol.Permission{{Action: ActionProvisioningSecretsEncrypt, Scope: ScopeProvisionersDashboards}},
+		},
+		{
+			desc:         "should fail with the reload permission only",
+			expectedCode: http.StatusForbidden,
+			permissions:  []accesscontrol.Permission{{Action: ActionProvisioningReload, Scope: ScopeProvisionersDashboards}},
+		},
+		{
+			desc:         "should fail without permission",
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	cfg := setting.NewCfg()
+	const url = "/api/admin/provisioning/dashboards/secrets"
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			sc, hs := setupAccessControlScenarioContext(t, cfg, url, test.permissions)
+			hs.SecretsService = fakes.NewFakeSecretsService()
+
+			sc.resp = httptest.NewRecorder()
+			var err error
+			sc.req, err = http.NewRequest(http.MethodPost, url, bytes.NewBufferString(`{"value": "my-token"}`))
+			assert.NoError(t, err)
+			sc.req.Header.Set("Content-Type", "application/json")
+
+			sc.exec()
+
+			assert.Equal(t, test.expectedCode, sc.resp.Code)
+		})
+	}
+}
+
+func TestAPI_ProvisioningDashboardsWebhook(t *testing.T) {
+	const secret = "webhook-secret"
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	signAt := func(timestamp, body string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "." + body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	sign := func(body string) string {
+		return signAt(now, body)
+	}
+
+	tests := []struct {
+		desc         string
+		secret       string
+		timestamp    string
+		body         string
+		signature    string
+		runErr       error
+		expectedCode int
+		expectedName string
+	}{
+		{desc: "should be disabled without a secret", body: "{}", signature: sign("{}"), expectedCode: http.StatusNotFound},
+		{desc: "should reject missing signatures", secret: secret, body: "{}", expectedCode: http.StatusUnauthorized},
+		{desc: "should reject invalid signatures", secret: secret, body: `{"provisioner":"other"}`, signature: sign("{}"), expectedCode: http.StatusUnauthorized},
+		{desc: "should reject signatures of another timestamp", secret: secret, timestamp: stale, body: "{}", signature: sign("{}"), expectedCode: http.StatusUnauthorized},
+		{desc: "should reject stale requests", secret: secret, timestamp: stale, body: "{}", signature: signAt(stale, "{}"), expectedCode: http.StatusUnauthorized},
+		{desc: "should run all the provisioners", secret: secret, body: "", signature: sign(""), expectedCode: http.StatusOK},
+		{desc: "should run the named provisioner", secret: secret, body: `{"provisioner":"default"}`, signature: sign(`{"provisioner":"default"}`), expectedCode: http.StatusOK, expectedName: "default"},
+		{desc: "should reject invalid bodies", secret: secret, body: "{", signature: sign("{"), expectedCode: http.StatusBadRequest},
+		{desc: "should fail for unknown provisioners", secret: secret, body: `{"provisioner":"unknown"}`, signature: sign(`{"provisioner":"unknown"}`), runErr: dashboards.ErrProvisionerNotFound, expectedCode: http.StatusNotFound, expectedName: "unknown"},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			cfg := setting.NewCfg()
+			cfg.ProvisioningWebhookSecret = test.secret
+			provisioningMock := provisioning.NewProvisioningServiceMock(context.Background())
+			provisioningMock.RunDashboardProvisionersFunc = func(ctx context.Context, name string) ([]dashboards.ProvisionerStatus, error) {
+				return []dashboards.ProvisionerStatus{{Name: "default"}}, test.runErr
+			}
+			hs := &HTTPServer{Cfg: cfg, ProvisioningService: provisioningMock}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/provisioning/dashboards/webhook", bytes.NewBufferString(test.body))
+			timestamp := test.timestamp
+			if timestamp == "" {
+				timestamp = now
+			}
+			req.Header.Set("X-Grafana-Timestamp", timestamp)
+			req.Header.Set("X-Grafana-Signature", test.signature)
+			recorder := httptest.NewRecorder()
+			c := &models.ReqContext{Context: &web.Context{Req: req, Resp: web.NewResponseWriter(http.MethodPost, recorder)}}
+
+			resp := hs.ProvisioningDashboardsWebhook(c)
+			assert.Equal(t, test.expectedCode, resp.Status())
+			if test.expectedCode == http.StatusOK || test.runErr != nil {
+				assert.Equal(t, []interface{}{test.expectedName}, provisioningMock.Calls.RunDashboardProvisioners)
+			} else {
+				assert.Empty(t, provisioningMock.Calls.RunDashboardProvisioners)
+			}
+		})
+	}
+}
@@ -579,6 +579,14 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/encryption/rollback-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminRollbackSecrets))
 
 		adminRoute.Post("/provisioning/dashboards/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningReloadDashboards))
+		adminRoute.Get("/provisioning/dashboards/status", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningRead, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningDashboardsStatus))
+		adminRoute.Get("/provisioning/dashboards/status/:name", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningRead, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningDashboardsStatusByName))
+		adminRoute.Get("/provisioning/dashboards/health", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningRead, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningDashboardsHealth))
+		adminRoute.Get("/provisioning/dashboards/config", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningRead, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningDashboardsConfig))
+		adminRoute.Post("/provisioning/dashboards/validate/:name", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningRead, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningValidateDashboard))
+		adminRoute.Get("/provisioning/dashboards/provisioned/:name", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningRead, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningDashboardsProvisioned))
+		adminRoute.Post("/provisioning/dashboards/canary/:name/promote", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningPromoteDashboardsCanary))
+		adminRoute.Post("/provisioning/dashboards/secrets", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningSecretsEncrypt, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningEncryptDashboardSecret))
 		adminRoute.Post("/provisioning/plugins/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersPlugins)), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/notifications/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersNotifications)), routing.Wrap(hs.AdminProvisioningReloadNotifications))
@@ -624,6 +632,9 @@ func (hs *HTTPServer) registerRoutes() {
 	r.Get("/api/snapshots-delete/:deleteKey", reqSnapshotPublicModeOrSignedIn, routing.Wrap(hs.DeleteDashboardSnapshotByDeleteKey))
 	r.Delete("/api/snapshots/:key", reqEditorRole, routing.Wrap(hs.DeleteDashboardSnapshot))
 
+	// Dashboard provisioning webhook, authenticated by the signature of its requests
+	r.Post("/api/provisioning/dashboards/webhook", routing.Wrap(hs.ProvisioningDashboardsWebhook))
+
 	// Frontend logs
 	sourceMapStore := frontendlogging.NewSourceMapStore(hs.Cfg, hs.pluginStaticRouteResolver, frontendlogging.ReadSourceMapFromFS)
 	r.Post("/log", middleware.RateLimit(hs.Cfg.Sentry.EndpointRPS, hs.Cfg.Sentry.EndpointBurst, time.Now),
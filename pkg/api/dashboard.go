@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/apierrors"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -179,6 +180,23 @@ func (hs *HTTPServer) GetDashboard(c *models.ReqContext) response.Response {
 			meta.Provisioned = true
 		}
 
+		// The provisioning source is exposed even when UI updates are allowed so that the UI can warn
+		// that changes may be overwritten the next time the file changes.
+		meta.ProvisionedBy = provisioningData.Name
+		meta.ProvisionedCheckSum = provisioningData.CheckSum
+		meta.ProvisionedLastError = provisioningData.LastError
+		if provisioningData.Updated > 0 {
+			updated := time.Unix(provisioningData.Updated, 0)
+			meta.ProvisionedUpdated = &updated
+		}
+		for _, status := range hs.ProvisioningService.GetDashboardProvisionersStatus() {
+			if status.Name == provisioningData.Name && !status.LastRun.IsZero() {
+				lastRun := status.LastRun
+				meta.ProvisionedLastRun = &lastRun
+				break
+			}
+		}
+
 		meta.ProvisionedExternalId, err = filepath.Rel(
 			hs.ProvisioningService.GetDashboardProvisionerResolvedPath(provisioningData.Name),
 			provisioningData.ExternalId,
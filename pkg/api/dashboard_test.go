@@ -856,7 +856,7 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 	t.Run("Given provisioned dashboard", func(t *testing.T) {
 		mockSQLStore := mockstore.NewSQLStoreMock()
 		dashboardStore := dashboards.NewFakeDashboardStore(t)
-		dashboardStore.On("GetProvisionedDataByDashboardID", mock.Anything).Return(&models.DashboardProvisioning{ExternalId: "/dashboard1.json"}, nil).Once()
+		dashboardStore.On("GetProvisionedDataByDashboardID", mock.Anything).Return(&models.DashboardProvisioning{Name: "default", ExternalId: "/dashboard1.json", CheckSum: "checksum", Updated: 1000, LastError: "invalid character"}, nil).Once()
 
 		dashboardService := dashboards.NewFakeDashboardService(t)
 
@@ -881,6 +881,11 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 			dash := getDashboardShouldReturn200WithConfig(t, sc, fakeProvisioningService, dashboardStore, dashboardService)
 
 			assert.Equal(t, "../../../dashboard1.json", dash.Meta.ProvisionedExternalId, mockSQLStore)
+			assert.Equal(t, "default", dash.Meta.ProvisionedBy)
+			assert.Equal(t, "checksum", dash.Meta.ProvisionedCheckSum)
+			assert.Equal(t, "invalid character", dash.Meta.ProvisionedLastError)
+			require.NotNil(t, dash.Meta.ProvisionedUpdated)
+			assert.Equal(t, int64(1000), dash.Meta.ProvisionedUpdated.Unix())
 		}, mockSQLStore)
 
 		loggedInUserScenarioWithRole(t, "When allowUiUpdates is true and calling GET on", "GET", "/api/dashboards/uid/dash", "/api/dashboards/uid/:uid", models.ROLE_EDITOR, func(sc *scenarioContext) {
@@ -32,6 +32,11 @@ type DashboardMeta struct {
 	FolderUrl                  string                `json:"folderUrl"`
 	Provisioned                bool                  `json:"provisioned"`
 	ProvisionedExternalId      string                `json:"provisionedExternalId"`
+	ProvisionedBy              string                `json:"provisionedBy,omitempty"`
+	ProvisionedCheckSum        string                `json:"provisionedCheckSum,omitempty"`
+	ProvisionedUpdated         *time.Time            `json:"provisionedUpdated,omitempty"`
+	ProvisionedLastRun         *time.Time            `json:"provisionedLastRun,omitempty"`
+	ProvisionedLastError       string                `json:"provisionedLastError,omitempty"`
 	AnnotationsPermissions     *AnnotationPermission `json:"annotationsPermissions"`
 	PublicDashboardAccessToken string                `json:"publicDashboardAccessToken"`
 	PublicDashboardEnabled     bool                  `json:"publicDashboardEnabled"`
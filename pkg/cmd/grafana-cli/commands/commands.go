@@ -103,6 +103,13 @@ func runPluginCommand(command func(commandLine utils.CommandLine) error) func(co
 	}
 }
 
+func runProvisioningCommand(command func(commandLine utils.CommandLine) error) func(context *cli.Context) error {
+	return func(context *cli.Context) error {
+		cmd := &utils.ContextCommandLine{Context: context}
+		return command(cmd)
+	}
+}
+
 // Command contains command state.
 type Command struct {
 	Client utils.ApiClient
@@ -194,6 +201,14 @@ var adminCommands = []*cli.Command{
 	},
 }
 
+var provisioningCommands = []*cli.Command{
+	{
+		Name:   "validate",
+		Usage:  "validate <dashboards provisioning directory>",
+		Action: runProvisioningCommand(validateProvisioningCommand),
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -205,4 +220,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "provisioning",
+		Usage:       "Grafana provisioning commands",
+		Subcommands: provisioningCommands,
+	},
 }
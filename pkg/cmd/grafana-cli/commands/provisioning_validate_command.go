@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+)
+
+var errMissingProvisioningDirectory = errors.New("missing provisioning directory argument")
+
+func validateProvisioningCommand(c utils.CommandLine) error {
+	configDirectory := c.Args().First()
+	if configDirectory == "" {
+		return errMissingProvisioningDirectory
+	}
+
+	problems := dashboards.ValidateConfigDirectory(configDirectory)
+	for _, problem := range problems {
+		logger.Infof("%s %s\n", color.RedString("✗"), problem)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in %s", len(problems), configDirectory)
+	}
+
+	logger.Infof("%s %s\n", color.GreenString("✔"), "dashboard provisioning configuration is valid")
+	return nil
+}
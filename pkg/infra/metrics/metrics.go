@@ -69,6 +69,12 @@ var (
 	// MApiDashboardInsert is a metric dashboards inserted
 	MApiDashboardInsert prometheus.Counter
 
+	// MProvisioningDashboardChecksumFailures is a metric counter for provisioned dashboard files failing checksum verification
+	MProvisioningDashboardChecksumFailures prometheus.Counter
+
+	// MProvisioningDashboardDrift is a metric gauge for provisioned dashboards differing from their files, labeled by provisioner and change
+	MProvisioningDashboardDrift *prometheus.GaugeVec
+
 	// MAlertingResultState is a metric alert execution result counter
 	MAlertingResultState *prometheus.CounterVec
 
@@ -319,6 +325,18 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MProvisioningDashboardChecksumFailures = metricutil.NewCounterStartingAtZero(prometheus.CounterOpts{
+		Name:      "provisioning_dashboard_checksum_failures_total",
+		Help:      "provisioned dashboard files failing checksum verification",
+		Namespace: ExporterName,
+	})
+
+	MProvisioningDashboardDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "provisioning_dashboard_drift",
+		Help:      "provisioned dashboards differing from their files, found by verify only provisioners",
+		Namespace: ExporterName,
+	}, []string{"provisioner", "change"})
+
 	MAlertingResultState = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name:      "alerting_result_total",
 		Help:      "alert execution result counter",
@@ -627,6 +645,8 @@ func initMetricVars() {
 		MApiDashboardSnapshotExternal,
 		MApiDashboardSnapshotGet,
 		MApiDashboardInsert,
+		MProvisioningDashboardChecksumFailures,
+		MProvisioningDashboardDrift,
 		MAlertingResultState,
 		MAlertingNotificationSent,
 		MAlertingNotificationFailed,
@@ -237,6 +237,20 @@ type DashboardProvisioning struct {
 	ExternalId  string
 	CheckSum    string
 	Updated     int64
+	// LastError is the last error met provisioning the file, cleared once it's provisioned again.
+	LastError string
+}
+
+// ProvisionedDashboard describes a dashboard along with the source it was provisioned from.
+type ProvisionedDashboard struct {
+	DashboardId int64  `json:"dashboardId"`
+	OrgId       int64  `json:"orgId"`
+	Uid         string `json:"uid"`
+	Title       string `json:"title"`
+	ExternalId  string `json:"externalId"`
+	CheckSum    string `json:"checkSum"`
+	Updated     int64  `json:"updated"`
+	LastError   string `json:"lastError,omitempty"`
 }
 
 type DeleteDashboardCommand struct {
@@ -254,10 +268,11 @@ type DeleteOrphanedProvisionedDashboardsCommand struct {
 //
 
 type GetDashboardQuery struct {
-	Slug  string // required if no Id or Uid is specified
-	Id    int64  // optional if slug is set
-	Uid   string // optional if slug is set
-	OrgId int64
+	Slug     string // required if no Id or Uid is specified
+	Id       int64  // optional if slug is set
+	Uid      string // optional if slug is set
+	FolderId int64  // optional, restricts the lookup to the dashboards of the folder
+	OrgId    int64
 
 	Result *Dashboard
 }
@@ -278,6 +293,13 @@ type GetDashboardsQuery struct {
 	Result        []*Dashboard
 }
 
+type GetDashboardsByOrgIdQuery struct {
+	OrgId int64
+	// ExcludeProvisioned leaves out the dashboards provisioned from files.
+	ExcludeProvisioned bool
+	Result             []*Dashboard
+}
+
 type GetDashboardsByPluginIdQuery struct {
 	OrgId    int64
 	PluginId string
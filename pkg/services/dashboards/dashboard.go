@@ -37,18 +37,26 @@ type PluginService interface {
 type DashboardProvisioningService interface {
 	DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *models.DeleteOrphanedProvisionedDashboardsCommand) error
 	DeleteProvisionedDashboard(ctx context.Context, dashboardID int64, orgID int64) error
+	DeleteEmptyFolderForProvisionedDashboards(ctx context.Context, folderID int64, orgID int64) (bool, error)
+	GetDashboardsByOrgID(ctx context.Context, query *models.GetDashboardsByOrgIdQuery) error
+	GetOrphanedProvisionedDashboards(ctx context.Context, readerNames []string) ([]*models.DashboardProvisioning, error)
 	GetProvisionedDashboardData(name string) ([]*models.DashboardProvisioning, error)
 	GetProvisionedDashboardDataByDashboardID(dashboardID int64) (*models.DashboardProvisioning, error)
 	GetProvisionedDashboardDataByDashboardUID(orgID int64, dashboardUID string) (*models.DashboardProvisioning, error)
+	GetProvisionedDashboards(ctx context.Context, name string) ([]*models.ProvisionedDashboard, error)
 	SaveFolderForProvisionedDashboards(context.Context, *SaveDashboardDTO) (*models.Dashboard, error)
 	SaveProvisionedDashboard(ctx context.Context, dto *SaveDashboardDTO, provisioning *models.DashboardProvisioning) (*models.Dashboard, error)
+	SetProvisionedDashboardError(ctx context.Context, id int64, lastError string) error
+	SetProvisionedDashboardExternalID(ctx context.Context, id int64, externalID string) error
 	UnprovisionDashboard(ctx context.Context, dashboardID int64) error
+	UpdateProvisionedDashboardACL(ctx context.Context, dashboardID int64, items []*models.DashboardACL) error
 }
 
 //go:generate mockery --name Store --structname FakeDashboardStore --inpackage --filename store_mock.go
 // Store is a dashboard store.
 type Store interface {
 	DeleteDashboard(ctx context.Context, cmd *models.DeleteDashboardCommand) error
+	DeleteEmptyFolder(ctx context.Context, orgID int64, folderID int64) (bool, error)
 	DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *models.DeleteOrphanedProvisionedDashboardsCommand) error
 	FindDashboards(ctx context.Context, query *models.FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error)
 	GetDashboard(ctx context.Context, query *models.GetDashboardQuery) (*models.Dashboard, error)
@@ -57,16 +65,28 @@ type Store interface {
 	GetDashboards(ctx context.Context, query *models.GetDashboardsQuery) error
 	// GetDashboardsByPluginID retrieves dashboards identified by plugin.
 	GetDashboardsByPluginID(ctx context.Context, query *models.GetDashboardsByPluginIdQuery) error
+	// GetDashboardsByOrgID retrieves the dashboards of an organization, leaving out folders.
+	GetDashboardsByOrgID(ctx context.Context, query *models.GetDashboardsByOrgIdQuery) error
 	GetDashboardTags(ctx context.Context, query *models.GetDashboardTagsQuery) error
+	// GetOrphanedProvisionedDashboards returns the provisioning data of the dashboards provisioned by readers
+	// other than the given ones.
+	GetOrphanedProvisionedDashboards(ctx context.Context, readerNames []string) ([]*models.DashboardProvisioning, error)
 	GetProvisionedDashboardData(name string) ([]*models.DashboardProvisioning, error)
 	GetProvisionedDataByDashboardID(dashboardID int64) (*models.DashboardProvisioning, error)
 	GetProvisionedDataByDashboardUID(orgID int64, dashboardUID string) (*models.DashboardProvisioning, error)
 	HasAdminPermissionInDashboardsOrFolders(ctx context.Context, query *models.HasAdminPermissionInDashboardsOrFoldersQuery) error
 	HasEditPermissionInFolders(ctx context.Context, query *models.HasEditPermissionInFoldersQuery) error
+	// ResetDashboardACL removes the permissions of a dashboard, which then inherits the ones of its folder.
+	ResetDashboardACL(ctx context.Context, dashboardID int64) error
 	// SaveAlerts saves dashboard alerts.
 	SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error
 	SaveDashboard(cmd models.SaveDashboardCommand) (*models.Dashboard, error)
 	SaveProvisionedDashboard(cmd models.SaveDashboardCommand, provisioning *models.DashboardProvisioning) (*models.Dashboard, error)
+	// SetProvisionedDashboardError sets the last error met provisioning the file of the provisioning record with
+	// the given id, an empty lastError clearing it.
+	SetProvisionedDashboardError(ctx context.Context, id int64, lastError string) error
+	// SetProvisionedDashboardExternalID sets the path of the file of the provisioning record with the given id.
+	SetProvisionedDashboardExternalID(ctx context.Context, id int64, externalID string) error
 	UnprovisionDashboard(ctx context.Context, id int64) error
 	UpdateDashboardACL(ctx context.Context, uid int64, items []*models.DashboardACL) error
 	// ValidateDashboardBeforeSave validates a dashboard before save.
@@ -16,6 +16,27 @@ type FakeDashboardProvisioning struct {
 	mock.Mock
 }
 
+// DeleteEmptyFolderForProvisionedDashboards provides a mock function with given fields: ctx, folderID, orgID
+func (_m *FakeDashboardProvisioning) DeleteEmptyFolderForProvisionedDashboards(ctx context.Context, folderID int64, orgID int64) (bool, error) {
+	ret := _m.Called(ctx, folderID, orgID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) bool); ok {
+		r0 = rf(ctx, folderID, orgID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, folderID, orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteOrphanedProvisionedDashboards provides a mock function with given fields: ctx, cmd
 func (_m *FakeDashboardProvisioning) DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *models.DeleteOrphanedProvisionedDashboardsCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -44,6 +65,43 @@ func (_m *FakeDashboardProvisioning) DeleteProvisionedDashboard(ctx context.Cont
 	return r0
 }
 
+// GetDashboardsByOrgID provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardProvisioning) GetDashboardsByOrgID(ctx context.Context, query *models.GetDashboardsByOrgIdQuery) error {
+	ret := _m.Called(ctx, query)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.GetDashboardsByOrgIdQuery) error); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetOrphanedProvisionedDashboards provides a mock function with given fields: ctx, readerNames
+func (_m *FakeDashboardProvisioning) GetOrphanedProvisionedDashboards(ctx context.Context, readerNames []string) ([]*models.DashboardProvisioning, error) {
+	ret := _m.Called(ctx, readerNames)
+
+	var r0 []*models.DashboardProvisioning
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*models.DashboardProvisioning); ok {
+		r0 = rf(ctx, readerNames)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.DashboardProvisioning)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, readerNames)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProvisionedDashboardData provides a mock function with given fields: name
 func (_m *FakeDashboardProvisioning) GetProvisionedDashboardData(name string) ([]*models.DashboardProvisioning, error) {
 	ret := _m.Called(name)
@@ -113,6 +171,29 @@ func (_m *FakeDashboardProvisioning) GetProvisionedDashboardDataByDashboardUID(o
 	return r0, r1
 }
 
+// GetProvisionedDashboards provides a mock function with given fields: ctx, name
+func (_m *FakeDashboardProvisioning) GetProvisionedDashboards(ctx context.Context, name string) ([]*models.ProvisionedDashboard, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 []*models.ProvisionedDashboard
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*models.ProvisionedDashboard); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ProvisionedDashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveFolderForProvisionedDashboards provides a mock function with given fields: _a0, _a1
 func (_m *FakeDashboardProvisioning) SaveFolderForProvisionedDashboards(_a0 context.Context, _a1 *SaveDashboardDTO) (*models.Dashboard, error) {
 	ret := _m.Called(_a0, _a1)
@@ -159,6 +240,34 @@ func (_m *FakeDashboardProvisioning) SaveProvisionedDashboard(ctx context.Contex
 	return r0, r1
 }
 
+// SetProvisionedDashboardError provides a mock function with given fields: ctx, id, lastError
+func (_m *FakeDashboardProvisioning) SetProvisionedDashboardError(ctx context.Context, id int64, lastError string) error {
+	ret := _m.Called(ctx, id, lastError)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetProvisionedDashboardExternalID provides a mock function with given fields: ctx, id, externalID
+func (_m *FakeDashboardProvisioning) SetProvisionedDashboardExternalID(ctx context.Context, id int64, externalID string) error {
+	ret := _m.Called(ctx, id, externalID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, externalID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnprovisionDashboard provides a mock function with given fields: ctx, dashboardID
 func (_m *FakeDashboardProvisioning) UnprovisionDashboard(ctx context.Context, dashboardID int64) error {
 	ret := _m.Called(ctx, dashboardID)
@@ -173,6 +282,20 @@ func (_m *FakeDashboardProvisioning) UnprovisionDashboard(ctx context.Context, d
 	return r0
 }
 
+// UpdateProvisionedDashboardACL provides a mock function with given fields: ctx, dashboardID, items
+func (_m *FakeDashboardProvisioning) UpdateProvisionedDashboardACL(ctx context.Context, dashboardID int64, items []*models.DashboardACL) error {
+	ret := _m.Called(ctx, dashboardID, items)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []*models.DashboardACL) error); ok {
+		r0 = rf(ctx, dashboardID, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewFakeDashboardProvisioning creates a new instance of FakeDashboardProvisioning. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
 func NewFakeDashboardProvisioning(t testing.TB) *FakeDashboardProvisioning {
 	mock := &FakeDashboardProvisioning{}
@@ -222,6 +222,18 @@ func (d *DashboardStore) UpdateDashboardACL(ctx context.Context, dashboardID int
 	})
 }
 
+// ResetDashboardACL removes the permissions of a dashboard, which then inherits the ones of its folder.
+func (d *DashboardStore) ResetDashboardACL(ctx context.Context, dashboardID int64) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if _, err := sess.Exec("DELETE FROM dashboard_acl WHERE dashboard_id=?", dashboardID); err != nil {
+			return fmt.Errorf("deleting from dashboard_acl failed: %w", err)
+		}
+
+		_, err := sess.Exec("UPDATE dashboard SET has_acl=? WHERE id=?", false, dashboardID)
+		return err
+	})
+}
+
 func (d *DashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error {
 	return d.sqlStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
 		existingAlerts, err := GetAlertsByDashboardId2(dashID, sess)
@@ -241,6 +253,22 @@ func (d *DashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []
 	})
 }
 
+// SetProvisionedDashboardError sets the last_error column of the dashboard_provisioning row with the given id.
+func (d *DashboardStore) SetProvisionedDashboardError(ctx context.Context, id int64, lastError string) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.ID(id).Cols("last_error").Update(&models.DashboardProvisioning{LastError: lastError})
+		return err
+	})
+}
+
+// SetProvisionedDashboardExternalID sets the external_id column of the dashboard_provisioning row with the given id.
+func (d *DashboardStore) SetProvisionedDashboardExternalID(ctx context.Context, id int64, externalID string) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.ID(id).Cols("external_id").Update(&models.DashboardProvisioning{ExternalId: externalID})
+		return err
+	})
+}
+
 // UnprovisionDashboard removes row in dashboard_provisioning for the dashboard making it seem as if manually created.
 // The dashboard will still have `created_by = -1` to see it was not created by any particular user.
 func (d *DashboardStore) UnprovisionDashboard(ctx context.Context, id int64) error {
@@ -275,6 +303,19 @@ func (d *DashboardStore) DeleteOrphanedProvisionedDashboards(ctx context.Context
 	})
 }
 
+func (d *DashboardStore) GetOrphanedProvisionedDashboards(ctx context.Context, readerNames []string) ([]*models.DashboardProvisioning, error) {
+	var result []*models.DashboardProvisioning
+	err := d.sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		convertedReaderNames := make([]interface{}, len(readerNames))
+		for index, readerName := range readerNames {
+			convertedReaderNames[index] = readerName
+		}
+
+		return sess.NotIn("name", convertedReaderNames...).Find(&result)
+	})
+	return result, err
+}
+
 func getExistingDashboardByIdOrUidForUpdate(sess *sqlstore.DBSession, dash *models.Dashboard, dialect migrator.Dialect, overwrite bool) (bool, error) {
 	dashWithIdExists := false
 	isParentFolderChanged := false
@@ -539,7 +580,8 @@ func saveProvisionedData(sess *sqlstore.DBSession, provisioning *models.Dashboar
 	provisioning.DashboardId = dashboard.Id
 
 	if exist {
-		_, err = sess.ID(result.Id).Update(provisioning)
+		// saving the dashboard again clears the error met provisioning it before, if any
+		_, err = sess.ID(result.Id).MustCols("last_error").Update(provisioning)
 	} else {
 		_, err = sess.Insert(provisioning)
 	}
@@ -696,12 +738,65 @@ func (d *DashboardStore) GetDashboardsByPluginID(ctx context.Context, query *mod
 	})
 }
 
+// GetDashboardsByOrgID retrieves the dashboards of an organization, leaving out folders, and the dashboards
+// provisioned from files when ExcludeProvisioned is set.
+func (d *DashboardStore) GetDashboardsByOrgID(ctx context.Context, query *models.GetDashboardsByOrgIdQuery) error {
+	return d.sqlStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
+		var dashboards = make([]*models.Dashboard, 0)
+		whereExpr := "org_id=? AND is_folder=" + d.sqlStore.Dialect.BooleanStr(false)
+		if query.ExcludeProvisioned {
+			whereExpr += " AND id NOT IN (SELECT dashboard_id FROM dashboard_provisioning)"
+		}
+
+		err := dbSession.Where(whereExpr, query.OrgId).Asc("id").Find(&dashboards)
+		query.Result = dashboards
+		return err
+	})
+}
+
 func (d *DashboardStore) DeleteDashboard(ctx context.Context, cmd *models.DeleteDashboardCommand) error {
 	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		return d.deleteDashboard(cmd, sess)
 	})
 }
 
+// DeleteEmptyFolder deletes the folder with the given id if it holds neither dashboards, folders, library
+// elements nor alert rules. It reports whether the folder was deleted.
+func (d *DashboardStore) DeleteEmptyFolder(ctx context.Context, orgID int64, folderID int64) (bool, error) {
+	deleted := false
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		folder := models.Dashboard{Id: folderID, OrgId: orgID}
+		has, err := sess.Get(&folder)
+		if err != nil {
+			return err
+		}
+		if !has || !folder.IsFolder {
+			return dashboards.ErrFolderNotFound
+		}
+
+		for _, table := range []string{"dashboard", "library_element"} {
+			count, err := sess.Table(table).Where("org_id = ? AND folder_id = ?", orgID, folderID).Count()
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+		}
+
+		if err := d.deleteDashboard(&models.DeleteDashboardCommand{Id: folderID, OrgId: orgID}, sess); err != nil {
+			if errors.Is(err, dashboards.ErrFolderContainsAlertRules) {
+				return nil
+			}
+			return err
+		}
+
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
 func (d *DashboardStore) deleteDashboard(cmd *models.DeleteDashboardCommand, sess *sqlstore.DBSession) error {
 	dashboard := models.Dashboard{Id: cmd.Id, OrgId: cmd.OrgId}
 	has, err := sess.Get(&dashboard)
@@ -838,7 +933,7 @@ func (d *DashboardStore) GetDashboard(ctx context.Context, query *models.GetDash
 			return dashboards.ErrDashboardIdentifierNotSet
 		}
 
-		dashboard := models.Dashboard{Slug: query.Slug, OrgId: query.OrgId, Id: query.Id, Uid: query.Uid}
+		dashboard := models.Dashboard{Slug: query.Slug, OrgId: query.OrgId, Id: query.Id, Uid: query.Uid, FolderId: query.FolderId}
 		has, err := sess.Get(&dashboard)
 
 		if err != nil {
@@ -82,6 +82,11 @@ func TestIntegrationDashboardProvisioningTest(t *testing.T) {
 			require.Nil(t, err)
 			require.NotNil(t, query.Result)
 
+			orphans, err := dashboardStore.GetOrphanedProvisionedDashboards(context.Background(), []string{"default"})
+			require.Nil(t, err)
+			require.Equal(t, 1, len(orphans))
+			require.Equal(t, anotherDash.Id, orphans[0].DashboardId)
+
 			deleteCmd := &models.DeleteOrphanedProvisionedDashboardsCommand{ReaderNames: []string{"default"}}
 			require.Nil(t, dashboardStore.DeleteOrphanedProvisionedDashboards(context.Background(), deleteCmd))
 
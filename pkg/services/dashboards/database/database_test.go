@@ -501,6 +501,32 @@ func TestIntegrationDashboardDataAccessGivenPluginWithImportedDashboards(t *test
 	require.Equal(t, len(query.Result), 2)
 }
 
+func TestIntegrationGetDashboardsByOrgID(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := sqlstore.InitTestDB(t)
+	dashboardStore := ProvideDashboardStore(sqlStore)
+
+	folder := insertTestDashboard(t, dashboardStore, "folder", 1, 0, true)
+	insertTestDashboard(t, dashboardStore, "ui", 1, folder.Id, false)
+	insertTestDashboard(t, dashboardStore, "other org", 2, 0, false)
+	_, err := dashboardStore.SaveProvisionedDashboard(models.SaveDashboardCommand{
+		OrgId:     1,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "provisioned"}),
+	}, &models.DashboardProvisioning{Name: "default", ExternalId: "/var/grafana.json"})
+	require.NoError(t, err)
+
+	query := models.GetDashboardsByOrgIdQuery{OrgId: 1}
+	require.NoError(t, dashboardStore.GetDashboardsByOrgID(context.Background(), &query))
+	require.Len(t, query.Result, 2)
+
+	query = models.GetDashboardsByOrgIdQuery{OrgId: 1, ExcludeProvisioned: true}
+	require.NoError(t, dashboardStore.GetDashboardsByOrgID(context.Background(), &query))
+	require.Len(t, query.Result, 1)
+	require.Equal(t, "ui", query.Result[0].Title)
+}
+
 func TestIntegrationDashboard_SortingOptions(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -65,6 +66,54 @@ func (dr *DashboardServiceImpl) GetProvisionedDashboardData(name string) ([]*mod
 	return dr.dashboardStore.GetProvisionedDashboardData(name)
 }
 
+// GetProvisionedDashboards returns the dashboards provisioned by the provider with the given name, along with the
+// source they were provisioned from, ordered by source.
+func (dr *DashboardServiceImpl) GetProvisionedDashboards(ctx context.Context, name string) ([]*models.ProvisionedDashboard, error) {
+	data, err := dr.dashboardStore.GetProvisionedDashboardData(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return []*models.ProvisionedDashboard{}, nil
+	}
+
+	ids := make([]int64, 0, len(data))
+	for _, provisioning := range data {
+		ids = append(ids, provisioning.DashboardId)
+	}
+	query := &models.GetDashboardsQuery{DashboardIds: ids}
+	if err := dr.dashboardStore.GetDashboards(ctx, query); err != nil {
+		return nil, err
+	}
+
+	dashboardsByID := make(map[int64]*models.Dashboard, len(query.Result))
+	for _, dash := range query.Result {
+		dashboardsByID[dash.Id] = dash
+	}
+
+	result := make([]*models.ProvisionedDashboard, 0, len(data))
+	for _, provisioning := range data {
+		provisioned := &models.ProvisionedDashboard{
+			DashboardId: provisioning.DashboardId,
+			ExternalId:  provisioning.ExternalId,
+			CheckSum:    provisioning.CheckSum,
+			Updated:     provisioning.Updated,
+			LastError:   provisioning.LastError,
+		}
+		if dash, ok := dashboardsByID[provisioning.DashboardId]; ok {
+			provisioned.OrgId = dash.OrgId
+			provisioned.Uid = dash.Uid
+			provisioned.Title = dash.Title
+		}
+		result = append(result, provisioned)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ExternalId < result[j].ExternalId
+	})
+	return result, nil
+}
+
 func (dr *DashboardServiceImpl) GetProvisionedDashboardDataByDashboardID(dashboardID int64) (*models.DashboardProvisioning, error) {
 	return dr.dashboardStore.GetProvisionedDataByDashboardID(dashboardID)
 }
@@ -181,6 +230,10 @@ func (dr *DashboardServiceImpl) DeleteOrphanedProvisionedDashboards(ctx context.
 	return dr.dashboardStore.DeleteOrphanedProvisionedDashboards(ctx, cmd)
 }
 
+func (dr *DashboardServiceImpl) GetOrphanedProvisionedDashboards(ctx context.Context, readerNames []string) ([]*models.DashboardProvisioning, error) {
+	return dr.dashboardStore.GetOrphanedProvisionedDashboards(ctx, readerNames)
+}
+
 func validateDashboardRefreshInterval(dash *models.Dashboard) error {
 	if setting.MinRefreshInterval == "" {
 		return nil
@@ -415,6 +468,21 @@ func (dr *DashboardServiceImpl) DeleteProvisionedDashboard(ctx context.Context,
 	return dr.deleteDashboard(ctx, dashboardId, orgId, false)
 }
 
+// DeleteEmptyFolderForProvisionedDashboards removes a folder created by a provisioner once it doesn't hold any
+// dashboard, library element or alert rule anymore. It reports whether the folder was removed.
+func (dr *DashboardServiceImpl) DeleteEmptyFolderForProvisionedDashboards(ctx context.Context, folderID int64, orgID int64) (bool, error) {
+	return dr.dashboardStore.DeleteEmptyFolder(ctx, orgID, folderID)
+}
+
+// UpdateProvisionedDashboardACL replaces the permissions of a provisioned dashboard. Without any item, the
+// dashboard is reset to the default permissions inherited from its folder.
+func (dr *DashboardServiceImpl) UpdateProvisionedDashboardACL(ctx context.Context, dashboardID int64, items []*models.DashboardACL) error {
+	if len(items) == 0 {
+		return dr.dashboardStore.ResetDashboardACL(ctx, dashboardID)
+	}
+	return dr.dashboardStore.UpdateDashboardACL(ctx, dashboardID, items)
+}
+
 func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId int64, orgId int64, validateProvisionedDashboard bool) error {
 	if validateProvisionedDashboard {
 		provisionedData, err := dr.GetProvisionedDashboardDataByDashboardID(dashboardId)
@@ -456,6 +524,18 @@ func (dr *DashboardServiceImpl) ImportDashboard(ctx context.Context, dto *dashbo
 	return dash, nil
 }
 
+// SetProvisionedDashboardError records the last error met provisioning the file of a provisioning record, so that
+// it can be shown next to the dashboard until the file is provisioned again.
+func (dr *DashboardServiceImpl) SetProvisionedDashboardError(ctx context.Context, id int64, lastError string) error {
+	return dr.dashboardStore.SetProvisionedDashboardError(ctx, id, lastError)
+}
+
+// SetProvisionedDashboardExternalID moves a provisioning record to the new path of its file, so that a renamed file
+// keeps provisioning the same dashboard without saving it.
+func (dr *DashboardServiceImpl) SetProvisionedDashboardExternalID(ctx context.Context, id int64, externalID string) error {
+	return dr.dashboardStore.SetProvisionedDashboardExternalID(ctx, id, externalID)
+}
+
 // UnprovisionDashboard removes info about dashboard being provisioned. Used after provisioning configs are changed
 // and provisioned dashboards are left behind but not deleted.
 func (dr *DashboardServiceImpl) UnprovisionDashboard(ctx context.Context, dashboardId int64) error {
@@ -466,6 +546,10 @@ func (dr *DashboardServiceImpl) GetDashboardsByPluginID(ctx context.Context, que
 	return dr.dashboardStore.GetDashboardsByPluginID(ctx, query)
 }
 
+func (dr *DashboardServiceImpl) GetDashboardsByOrgID(ctx context.Context, query *models.GetDashboardsByOrgIdQuery) error {
+	return dr.dashboardStore.GetDashboardsByOrgID(ctx, query)
+}
+
 func (dr *DashboardServiceImpl) setDefaultPermissions(ctx context.Context, dto *dashboards.SaveDashboardDTO, dash *models.Dashboard, provisioned bool) error {
 	inFolder := dash.FolderId > 0
 	if !accesscontrol.IsDisabled(dr.cfg) {
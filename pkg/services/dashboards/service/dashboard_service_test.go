@@ -224,6 +224,36 @@ func TestDashboardService(t *testing.T) {
 		})
 	})
 
+	t.Run("Get provisioned dashboards", func(t *testing.T) {
+		fakeStore := dashboards.FakeDashboardStore{}
+		defer fakeStore.AssertExpectations(t)
+
+		service := &DashboardServiceImpl{
+			cfg:                setting.NewCfg(),
+			log:                log.New("test.logger"),
+			dashboardStore:     &fakeStore,
+			dashAlertExtractor: &dummyDashAlertExtractor{},
+		}
+
+		fakeStore.On("GetProvisionedDashboardData", "default").Return([]*models.DashboardProvisioning{
+			{DashboardId: 2, ExternalId: "/dashboards/memory.json", CheckSum: "bbb", Updated: 20},
+			{DashboardId: 1, ExternalId: "/dashboards/cpu.json", CheckSum: "aaa", Updated: 10},
+		}, nil).Once()
+		fakeStore.On("GetDashboards", mock.Anything, mock.AnythingOfType("*models.GetDashboardsQuery")).Return(nil).Once().
+			Run(func(args mock.Arguments) {
+				query := args.Get(1).(*models.GetDashboardsQuery)
+				require.ElementsMatch(t, []int64{1, 2}, query.DashboardIds)
+				query.Result = []*models.Dashboard{{Id: 1, OrgId: 1, Uid: "cpu", Title: "CPU"}}
+			})
+
+		provisioned, err := service.GetProvisionedDashboards(context.Background(), "default")
+		require.NoError(t, err)
+		require.Equal(t, []*models.ProvisionedDashboard{
+			{DashboardId: 1, OrgId: 1, Uid: "cpu", Title: "CPU", ExternalId: "/dashboards/cpu.json", CheckSum: "aaa", Updated: 10},
+			{DashboardId: 2, ExternalId: "/dashboards/memory.json", CheckSum: "bbb", Updated: 20},
+		}, provisioned)
+	})
+
 	t.Run("Delete user by acl", func(t *testing.T) {
 		fakeStore := dashboards.FakeDashboardStore{}
 		defer fakeStore.AssertExpectations(t)
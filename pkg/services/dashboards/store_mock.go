@@ -31,6 +31,27 @@ func (_m *FakeDashboardStore) DeleteDashboard(ctx context.Context, cmd *models.D
 	return r0
 }
 
+// DeleteEmptyFolder provides a mock function with given fields: ctx, orgID, folderID
+func (_m *FakeDashboardStore) DeleteEmptyFolder(ctx context.Context, orgID int64, folderID int64) (bool, error) {
+	ret := _m.Called(ctx, orgID, folderID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) bool); ok {
+		r0 = rf(ctx, orgID, folderID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, orgID, folderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteOrphanedProvisionedDashboards provides a mock function with given fields: ctx, cmd
 func (_m *FakeDashboardStore) DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *models.DeleteOrphanedProvisionedDashboardsCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -147,6 +168,20 @@ func (_m *FakeDashboardStore) GetDashboards(ctx context.Context, query *models.G
 	return r0
 }
 
+// GetDashboardsByOrgID provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetDashboardsByOrgID(ctx context.Context, query *models.GetDashboardsByOrgIdQuery) error {
+	ret := _m.Called(ctx, query)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.GetDashboardsByOrgIdQuery) error); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetDashboardsByPluginID provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardStore) GetDashboardsByPluginID(ctx context.Context, query *models.GetDashboardsByPluginIdQuery) error {
 	ret := _m.Called(ctx, query)
@@ -230,6 +265,29 @@ func (_m *FakeDashboardStore) GetFolderByUID(ctx context.Context, orgID int64, u
 	return r0, r1
 }
 
+// GetOrphanedProvisionedDashboards provides a mock function with given fields: ctx, readerNames
+func (_m *FakeDashboardStore) GetOrphanedProvisionedDashboards(ctx context.Context, readerNames []string) ([]*models.DashboardProvisioning, error) {
+	ret := _m.Called(ctx, readerNames)
+
+	var r0 []*models.DashboardProvisioning
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*models.DashboardProvisioning); ok {
+		r0 = rf(ctx, readerNames)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.DashboardProvisioning)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, readerNames)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProvisionedDashboardData provides a mock function with given fields: name
 func (_m *FakeDashboardStore) GetProvisionedDashboardData(name string) ([]*models.DashboardProvisioning, error) {
 	ret := _m.Called(name)
@@ -327,6 +385,20 @@ func (_m *FakeDashboardStore) HasEditPermissionInFolders(ctx context.Context, qu
 	return r0
 }
 
+// ResetDashboardACL provides a mock function with given fields: ctx, dashboardID
+func (_m *FakeDashboardStore) ResetDashboardACL(ctx context.Context, dashboardID int64) error {
+	ret := _m.Called(ctx, dashboardID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, dashboardID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveAlerts provides a mock function with given fields: ctx, dashID, alerts
 func (_m *FakeDashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error {
 	ret := _m.Called(ctx, dashID, alerts)
@@ -387,6 +459,34 @@ func (_m *FakeDashboardStore) SaveProvisionedDashboard(cmd models.SaveDashboardC
 	return r0, r1
 }
 
+// SetProvisionedDashboardError provides a mock function with given fields: ctx, id, lastError
+func (_m *FakeDashboardStore) SetProvisionedDashboardError(ctx context.Context, id int64, lastError string) error {
+	ret := _m.Called(ctx, id, lastError)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetProvisionedDashboardExternalID provides a mock function with given fields: ctx, id, externalID
+func (_m *FakeDashboardStore) SetProvisionedDashboardExternalID(ctx context.Context, id int64, externalID string) error {
+	ret := _m.Called(ctx, id, externalID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, externalID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnprovisionDashboard provides a mock function with given fields: ctx, id
 func (_m *FakeDashboardStore) UnprovisionDashboard(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -0,0 +1,209 @@
+package dashboards
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// archiveExtractionPath returns the directory the archive of a provider is extracted to, under the data directory
+// of Grafana. It only depends on the provider and the archive, so that the dashboards keep the same paths across
+// restarts. Readers created without a data directory, such as the ones of the offline validation, fall back to the
+// temporary directory.
+func archiveExtractionPath(dataPath, name, archive string) string {
+	if abs, err := filepath.Abs(archive); err == nil {
+		archive = abs
+	}
+	sum := sha256.Sum256([]byte(name + "\x00" + archive))
+	dir := filepath.Join(dataPath, "provisioning", "dashboards")
+	if dataPath == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("grafana-dashboards-%x", sum[:8]))
+}
+
+// extractArchive extracts the archive of the provider into its path whenever the archive changed since the
+// last extraction. The entries are extracted into a private directory next to the path first, so that a broken
+// archive leaves the previously extracted dashboards in place and other users can't tamper with them.
+func (fr *FileReader) extractArchive() error {
+	info, err := os.Stat(fr.Archive)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(fr.Path); err == nil && info.ModTime().Equal(fr.archiveModTime) && info.Size() == fr.archiveSize {
+		return nil
+	}
+
+	parent := filepath.Dir(fr.Path)
+	if err := os.MkdirAll(parent, 0750); err != nil {
+		return err
+	}
+	if existing, err := os.Lstat(fr.Path); err == nil && !existing.IsDir() {
+		return fmt.Errorf("can't extract archive %q, %q is not a directory", fr.Archive, fr.Path)
+	}
+
+	// MkdirTemp creates the directory with a random name and 0700 permissions
+	tmp, err := os.MkdirTemp(parent, filepath.Base(fr.Path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if err := fr.extractArchiveTo(tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return fmt.Errorf("failed to extract archive %q: %w", fr.Archive, err)
+	}
+
+	if err := os.RemoveAll(fr.Path); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, fr.Path); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+
+	fr.archiveModTime = info.ModTime()
+	fr.archiveSize = info.Size()
+	fr.log.Info("extracted dashboards archive", "archive", fr.Archive, "path", fr.Path)
+	return nil
+}
+
+func (fr *FileReader) extractArchiveTo(dir string) error {
+	name := strings.ToLower(fr.Archive)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return fr.extractZip(dir)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return fr.extractTar(dir, true)
+	case strings.HasSuffix(name, ".tar"):
+		return fr.extractTar(dir, false)
+	default:
+		return fmt.Errorf("unsupported archive format, expected .zip, .tar, .tar.gz or .tgz")
+	}
+}
+
+func (fr *FileReader) extractTar(dir string, compressed bool) error {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `Archive` comes from the provisioning configuration file.
+	file, err := os.Open(fr.Archive)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fr.log.Warn("Failed to close file", "path", fr.Archive, "err", err)
+		}
+	}()
+
+	var content io.Reader = file
+	if compressed {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = gzipReader.Close()
+		}()
+		content = gzipReader
+	}
+
+	tarReader := tar.NewReader(content)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if _, err := archiveEntryPath(dir, header.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := fr.writeArchiveEntry(dir, header.Name, header.ModTime, tarReader); err != nil {
+				return err
+			}
+		default:
+			fr.log.Debug("skipping archive entry which is not a regular file", "archive", fr.Archive, "entry", header.Name)
+		}
+	}
+}
+
+func (fr *FileReader) extractZip(dir string) error {
+	zipReader, err := zip.OpenReader(fr.Archive)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := zipReader.Close(); err != nil {
+			fr.log.Warn("Failed to close file", "path", fr.Archive, "err", err)
+		}
+	}()
+
+	for _, entry := range zipReader.File {
+		if !entry.Mode().IsRegular() {
+			fr.log.Debug("skipping archive entry which is not a regular file", "archive", fr.Archive, "entry", entry.Name)
+			continue
+		}
+
+		content, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		err = fr.writeArchiveEntry(dir, entry.Name, entry.Modified, content)
+		_ = content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeArchiveEntry writes an archive entry below dir, keeping its modification time. Entries are cut one byte
+// past the maximum file size, so that oversized dashboards are still reported as too large when read.
+func (fr *FileReader) writeArchiveEntry(dir, name string, modTime time.Time, content io.Reader) error {
+	path, err := archiveEntryPath(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` was checked to be within `dir`.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, io.LimitReader(content, fr.MaxFileSizeBytes+1)); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Chtimes(path, modTime, modTime)
+}
+
+// archiveEntryPath returns where an archive entry is extracted to, rejecting entries which would end up
+// outside of dir.
+func archiveEntryPath(dir, name string) (string, error) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if path != dir && !strings.HasPrefix(path, dir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry %q is outside of the archive", name)
+	}
+	return path, nil
+}
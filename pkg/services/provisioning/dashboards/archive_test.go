@@ -0,0 +1,147 @@
+package dashboards
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestExtractArchive(t *testing.T) {
+	entries := map[string]string{
+		"root.json":              `{"title": "Root"}`,
+		"team/nested.json":       `{"title": "Nested"}`,
+		"team/README.md":         "not a dashboard",
+		"other/second.json":      `{"title": "Second"}`,
+		"other/.git/hidden.json": `{"title": "Hidden"}`,
+	}
+
+	newReader := func(t *testing.T, archive string) *FileReader {
+		t.Helper()
+		cfg := &config{Name: t.Name(), Type: "file", OrgID: 1, dataPath: t.TempDir(), Options: map[string]interface{}{
+			"archive":                   archive,
+			"foldersFromFilesStructure": true,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.RemoveAll(reader.Path)
+		})
+		return reader
+	}
+
+	t.Run("should extract tar.gz archives", func(t *testing.T) {
+		archive := filepath.Join(t.TempDir(), "dashboards.tar.gz")
+		writeTarGz(t, archive, entries)
+
+		reader := newReader(t, archive)
+		require.NoError(t, reader.extractArchive())
+
+		files := map[string]os.FileInfo{}
+		resolvedPath := reader.resolvedPath()
+		require.NoError(t, reader.walkFiles(resolvedPath, files))
+		require.Len(t, files, 3)
+		require.Contains(t, files, filepath.Join(resolvedPath, "team", "nested.json"))
+	})
+
+	t.Run("should extract zip archives", func(t *testing.T) {
+		archive := filepath.Join(t.TempDir(), "dashboards.zip")
+		writeZip(t, archive, entries)
+
+		reader := newReader(t, archive)
+		require.NoError(t, reader.extractArchive())
+
+		raw, err := os.ReadFile(filepath.Join(reader.Path, "other", "second.json"))
+		require.NoError(t, err)
+		require.Equal(t, entries["other/second.json"], string(raw))
+	})
+
+	t.Run("should extract into a private directory under the data path", func(t *testing.T) {
+		archive := filepath.Join(t.TempDir(), "dashboards.zip")
+		writeZip(t, archive, entries)
+
+		reader := newReader(t, archive)
+		require.NoError(t, reader.extractArchive())
+
+		info, err := os.Stat(reader.Path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0700), info.Mode().Perm())
+		require.Contains(t, reader.Path, filepath.Join("provisioning", "dashboards", "grafana-dashboards-"))
+	})
+
+	t.Run("should only extract changed archives", func(t *testing.T) {
+		archive := filepath.Join(t.TempDir(), "dashboards.tar.gz")
+		writeTarGz(t, archive, entries)
+
+		reader := newReader(t, archive)
+		require.NoError(t, reader.extractArchive())
+		require.NoError(t, os.Remove(filepath.Join(reader.Path, "root.json")))
+
+		require.NoError(t, reader.extractArchive())
+		require.NoFileExists(t, filepath.Join(reader.Path, "root.json"))
+
+		writeTarGz(t, archive, map[string]string{"root.json": `{"title": "Root"}`})
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(archive, later, later))
+		require.NoError(t, reader.extractArchive())
+		require.FileExists(t, filepath.Join(reader.Path, "root.json"))
+		require.NoFileExists(t, filepath.Join(reader.Path, "team", "nested.json"))
+	})
+
+	t.Run("should reject entries outside of the archive", func(t *testing.T) {
+		archive := filepath.Join(t.TempDir(), "dashboards.tar.gz")
+		writeTarGz(t, archive, map[string]string{"../escaped.json": `{"title": "Escaped"}`})
+
+		reader := newReader(t, archive)
+		require.Error(t, reader.extractArchive())
+	})
+
+	t.Run("should not be combined with a path", func(t *testing.T) {
+		cfg := &config{Name: "archive", Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"archive": "dashboards.zip",
+			"path":    oneDashboard,
+		}}
+		_, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
+
+func writeTarGz(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range entries {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+			Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg, ModTime: time.Now(),
+		}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, file.Close())
+}
+
+func writeZip(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	zipWriter := zip.NewWriter(file)
+	for name, content := range entries {
+		writer, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	require.NoError(t, file.Close())
+}
@@ -0,0 +1,68 @@
+package dashboards
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// auxiliaryFilePaths returns the absolute paths of the auxiliary files, resolving relative paths against
+// resolvedPath.
+func (fr *FileReader) auxiliaryFilePaths(resolvedPath string) []string {
+	paths := make([]string, 0, len(fr.AuxiliaryFiles))
+	for _, path := range fr.AuxiliaryFiles {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(resolvedPath, path)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// updateAuxiliaryChecksum computes the checksum of the content of the auxiliary files, which is folded into the
+// checksum of every dashboard file so that changing any of them updates every dashboard on the next run. Missing
+// auxiliary files are treated as empty, so that creating or deleting one also counts as a change.
+func (fr *FileReader) updateAuxiliaryChecksum(resolvedPath string) error {
+	if len(fr.AuxiliaryFiles) == 0 {
+		fr.auxiliaryChecksum = ""
+		return nil
+	}
+
+	var content strings.Builder
+	for _, path := range fr.auxiliaryFilePaths(resolvedPath) {
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+		data, err := ioutil.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		content.WriteString(path)
+		content.Write(data)
+	}
+
+	checkSum, err := util.Md5SumString(content.String())
+	if err != nil {
+		return err
+	}
+	fr.auxiliaryChecksum = checkSum
+	return nil
+}
+
+// withAuxiliaryChecksum folds the checksum of the auxiliary files into the checksum of a dashboard file.
+func (fr *FileReader) withAuxiliaryChecksum(checkSum string) (string, error) {
+	if fr.auxiliaryChecksum == "" {
+		return checkSum, nil
+	}
+	return util.Md5SumString(checkSum + fr.auxiliaryChecksum)
+}
+
+// removeAuxiliaryFiles removes the auxiliary files from the files to provision, as they aren't dashboards.
+func (fr *FileReader) removeAuxiliaryFiles(resolvedPath string, filesOnDisk map[string]os.FileInfo) {
+	for _, path := range fr.auxiliaryFilePaths(resolvedPath) {
+		delete(filesOnDisk, path)
+	}
+}
@@ -0,0 +1,51 @@
+package dashboards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestAuxiliaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cpu.json")
+	auxiliaryPath := filepath.Join(dir, "variables.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"title": "CPU"}`), 0600))
+	require.NoError(t, os.WriteFile(auxiliaryPath, []byte(`{"env": "dev"}`), 0600))
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path":           dir,
+		"auxiliaryFiles": []interface{}{"variables.json"},
+	}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.NoError(t, err)
+
+	read := func(t *testing.T) string {
+		t.Helper()
+		require.NoError(t, reader.updateAuxiliaryChecksum(dir))
+		fileInfo, err := os.Stat(path)
+		require.NoError(t, err)
+		jsonFile, err := reader.readDashboardFromFile(path, fileInfo, 0)
+		require.NoError(t, err)
+		return jsonFile.checkSum
+	}
+
+	t.Run("should change the checksum of dashboards when an auxiliary file changes", func(t *testing.T) {
+		before := read(t)
+		require.Equal(t, before, read(t))
+
+		require.NoError(t, os.WriteFile(auxiliaryPath, []byte(`{"env": "prod"}`), 0600))
+		require.NotEqual(t, before, read(t))
+	})
+
+	t.Run("should not provision auxiliary files", func(t *testing.T) {
+		filesOnDisk := map[string]os.FileInfo{}
+		require.NoError(t, reader.walkFiles(dir, filesOnDisk))
+		require.Contains(t, filesOnDisk, path)
+		require.NotContains(t, filesOnDisk, auxiliaryPath)
+	})
+}
@@ -0,0 +1,70 @@
+package dashboards
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+var (
+	// ErrProvisionerNotFound is returned when no dashboard provisioner has the requested name.
+	ErrProvisionerNotFound = errors.New("dashboard provisioner not found")
+	// ErrNoCanary is returned when promoting a dashboard provisioner which isn't restricted to a canary.
+	ErrNoCanary = errors.New("dashboard provisioner has no canary to promote")
+)
+
+// inCanary reports whether the runs are restricted to the files matching CanaryPattern.
+func (fr *FileReader) inCanary() bool {
+	fr.mux.RLock()
+	defer fr.mux.RUnlock()
+	return fr.CanaryPattern != "" && !fr.canaryPromoted
+}
+
+// promoteCanary switches the reader from the canary to the whole path, starting with the next run.
+func (fr *FileReader) promoteCanary() error {
+	fr.mux.Lock()
+	defer fr.mux.Unlock()
+
+	if fr.CanaryPattern == "" || fr.canaryPromoted {
+		return ErrNoCanary
+	}
+	fr.canaryPromoted = true
+	fr.status.Canary = false
+	fr.status.CanaryFiles = nil
+	return nil
+}
+
+// filterCanaryMatches removes the files which don't match the canary pattern, along with the dashboards
+// provisioned from them, so that they are neither saved nor deleted. The remaining files are recorded in the
+// status of the reader.
+func (fr *FileReader) filterCanaryMatches(resolvedPath string, filesOnDisk map[string]os.FileInfo,
+	provisionedDashboardRefs map[string]*models.DashboardProvisioning) {
+	matches := func(p string) bool {
+		relativePath, err := filepath.Rel(resolvedPath, p)
+		return err == nil && matchGlob(fr.CanaryPattern, filepath.ToSlash(relativePath))
+	}
+
+	canaryFiles := []string{}
+	for p := range filesOnDisk {
+		if !matches(p) {
+			delete(filesOnDisk, p)
+			continue
+		}
+		relativePath, _ := filepath.Rel(resolvedPath, p)
+		canaryFiles = append(canaryFiles, filepath.ToSlash(relativePath))
+	}
+	for p := range provisionedDashboardRefs {
+		if !matches(p) {
+			delete(provisionedDashboardRefs, p)
+		}
+	}
+	sort.Strings(canaryFiles)
+
+	fr.mux.Lock()
+	defer fr.mux.Unlock()
+	fr.status.Canary = true
+	fr.status.CanaryFiles = canaryFiles
+}
@@ -0,0 +1,74 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestCanary(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"canary/cpu.json", "team/memory.json"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(t, os.WriteFile(path, []byte(`{"title": "`+name+`"}`), 0600))
+	}
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path":          dir,
+		"canaryPattern": "canary/*.json",
+	}}
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	t.Run("should only provision the canary until it's promoted", func(t *testing.T) {
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Twice()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		status := reader.getStatus()
+		require.True(t, status.Canary)
+		require.Equal(t, []string{"canary/cpu.json"}, status.CanaryFiles)
+
+		require.NoError(t, reader.promoteCanary())
+		require.ErrorIs(t, reader.promoteCanary(), ErrNoCanary)
+
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Twice()
+		require.NoError(t, reader.walkDisk(context.Background()))
+		status = reader.getStatus()
+		require.False(t, status.Canary)
+		require.Empty(t, status.CanaryFiles)
+	})
+
+	t.Run("should keep the dashboards provisioned from files outside of the canary", func(t *testing.T) {
+		refs := map[string]*models.DashboardProvisioning{
+			filepath.Join(dir, "canary", "disk.json"): {},
+			filepath.Join(dir, "team", "disk.json"):   {},
+		}
+		files := map[string]os.FileInfo{}
+		reader.filterCanaryMatches(dir, files, refs)
+		require.Len(t, refs, 1)
+		require.Contains(t, refs, filepath.Join(dir, "canary", "disk.json"))
+	})
+
+	t.Run("should reject invalid patterns", func(t *testing.T) {
+		invalid := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":          dir,
+			"canaryPattern": "[a-",
+		}}
+		_, err := NewDashboardFileReader(invalid, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,44 @@
+package dashboards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestCanonicalChecksum(t *testing.T) {
+	newReader := func(t *testing.T, canonical bool) *FileReader {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":              defaultDashboards,
+			"canonicalChecksum": canonical,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		return reader
+	}
+	checkSum := func(t *testing.T, reader *FileReader, content string) string {
+		_, checkSum, err := reader.parseDashboardContent([]byte(content))
+		require.NoError(t, err)
+		return checkSum
+	}
+
+	compact := `{"title":"CPU","uid":"cpu","panels":[{"id":1,"type":"graph"}]}`
+	reformatted := "{\n  \"uid\": \"cpu\",\n  \"title\": \"CPU\",\n  \"panels\": [\n    {\"type\": \"graph\", \"id\": 1}\n  ]\n}\n"
+
+	t.Run("should ignore the formatting and the order of the keys", func(t *testing.T) {
+		reader := newReader(t, true)
+		require.Equal(t, checkSum(t, reader, compact), checkSum(t, reader, reformatted))
+	})
+
+	t.Run("should still catch content changes", func(t *testing.T) {
+		reader := newReader(t, true)
+		require.NotEqual(t, checkSum(t, reader, compact), checkSum(t, reader, `{"title":"CPU","uid":"cpu","panels":[]}`))
+	})
+
+	t.Run("should checksum the raw content by default", func(t *testing.T) {
+		reader := newReader(t, false)
+		require.NotEqual(t, checkSum(t, reader, compact), checkSum(t, reader, reformatted))
+	})
+}
@@ -0,0 +1,79 @@
+package dashboards
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+)
+
+// checksumSidecarSuffix is appended to the name of a dashboard file to get the name of the file holding its
+// SHA-256 checksum, e.g. `cpu.json.sha256` for `cpu.json`.
+const checksumSidecarSuffix = ".sha256"
+
+var (
+	// ErrChecksumMismatch is returned when a dashboard file doesn't match the checksum in its sidecar, in which
+	// case the file is skipped.
+	ErrChecksumMismatch = errors.New("dashboard file doesn't match its checksum sidecar")
+	// ErrChecksumSidecarMissing is returned when requireChecksumSidecar is set and a dashboard file has no
+	// checksum sidecar, in which case the file is skipped.
+	ErrChecksumSidecarMissing = errors.New("dashboard file has no checksum sidecar")
+)
+
+// verifyChecksumSidecar compares the SHA-256 checksum of the file at path with the one in its sidecar, in the
+// format written by `sha256sum`. Files without a sidecar are only rejected when RequireChecksumSidecar is set.
+func (fr *FileReader) verifyChecksumSidecar(path string) error {
+	err := fr.checkChecksumSidecar(path)
+	if errors.Is(err, ErrChecksumMismatch) || errors.Is(err, ErrChecksumSidecarMissing) {
+		metrics.MProvisioningDashboardChecksumFailures.Inc()
+	}
+	return err
+}
+
+func (fr *FileReader) checkChecksumSidecar(path string) error {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	raw, err := ioutil.ReadFile(path + checksumSidecarSuffix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			if fr.RequireChecksumSidecar {
+				return ErrChecksumSidecarMissing
+			}
+			return nil
+		}
+		return err
+	}
+
+	fields := strings.Fields(string(raw))
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: the sidecar is empty", ErrChecksumMismatch)
+	}
+	expected := strings.ToLower(fields[0])
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			fr.log.Warn("Failed to close file", "path", path, "err", err)
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package dashboards
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestVerifyChecksumSidecar(t *testing.T) {
+	content := []byte(`{"title": "CPU"}`)
+	sum := sha256.Sum256(content)
+
+	setup := func(t *testing.T, requireSidecar bool, sidecar string) (*FileReader, string) {
+		t.Helper()
+		dir := t.TempDir()
+		path := filepath.Join(dir, "cpu.json")
+		require.NoError(t, os.WriteFile(path, content, 0600))
+		if sidecar != "" {
+			require.NoError(t, os.WriteFile(path+checksumSidecarSuffix, []byte(sidecar), 0600))
+		}
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":                   dir,
+			"requireChecksumSidecar": requireSidecar,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		return reader, path
+	}
+
+	read := func(t *testing.T, reader *FileReader, path string) error {
+		t.Helper()
+		fileInfo, err := os.Stat(path)
+		require.NoError(t, err)
+		_, err = reader.readDashboardFromFile(path, fileInfo, 0)
+		return err
+	}
+
+	t.Run("should accept files matching their sidecar", func(t *testing.T) {
+		reader, path := setup(t, true, hex.EncodeToString(sum[:])+"  cpu.json\n")
+		require.NoError(t, read(t, reader, path))
+	})
+
+	t.Run("should reject files not matching their sidecar", func(t *testing.T) {
+		reader, path := setup(t, false, "0000000000000000000000000000000000000000000000000000000000000000\n")
+		require.ErrorIs(t, read(t, reader, path), ErrChecksumMismatch)
+	})
+
+	t.Run("should only require sidecars when configured", func(t *testing.T) {
+		reader, path := setup(t, false, "")
+		require.NoError(t, read(t, reader, path))
+
+		reader, path = setup(t, true, "")
+		require.ErrorIs(t, read(t, reader, path), ErrChecksumSidecarMissing)
+	})
+}
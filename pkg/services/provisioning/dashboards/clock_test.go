@@ -0,0 +1,53 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestFileReaderClock(t *testing.T) {
+	newReader := func(t *testing.T, options map[string]interface{}) (*FileReader, *clock.Mock) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, UpdateIntervalSeconds: 10, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		mockClock := clock.NewMock()
+		reader.Clock = mockClock
+		return reader, mockClock
+	}
+
+	t.Run("should compare modification times with the clock", func(t *testing.T) {
+		reader, mockClock := newReader(t, map[string]interface{}{"path": t.TempDir(), "ignoreOlderThan": "1h"})
+		modTime := mockClock.Now()
+
+		mockClock.Add(30 * time.Minute)
+		require.False(t, reader.isTooOld(modTime))
+
+		mockClock.Add(time.Hour)
+		require.True(t, reader.isTooOld(modTime))
+	})
+
+	t.Run("should poll when the clock reaches the interval", func(t *testing.T) {
+		// a missing path fails the runs before reaching the database, which is enough to tell them apart
+		reader, mockClock := newReader(t, map[string]interface{}{"path": filepath.Join(t.TempDir(), "missing")})
+		_, err := os.Stat(reader.Path)
+		require.True(t, os.IsNotExist(err))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go reader.pollChanges(ctx)
+
+		// the timer is created by the polling goroutine, so the clock is advanced until it has fired
+		require.Eventually(t, func() bool {
+			mockClock.Add(10 * time.Second)
+			return !reader.getStatus().LastRun.IsZero()
+		}, time.Second, time.Millisecond)
+	})
+}
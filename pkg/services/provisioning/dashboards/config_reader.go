@@ -2,10 +2,12 @@ package dashboards
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -17,6 +19,10 @@ type configReader struct {
 	path     string
 	log      log.Logger
 	orgStore utils.OrgStore
+	// dataPath is the data directory of Grafana, passed on to the configs read.
+	dataPath string
+	// strict makes readConfig fail on the first invalid config file instead of skipping it.
+	strict bool
 }
 
 func (cr *configReader) parseConfigs(file os.FileInfo) ([]*config, error) {
@@ -29,39 +35,69 @@ func (cr *configReader) parseConfigs(file os.FileInfo) ([]*config, error) {
 		return nil, err
 	}
 
-	apiVersion := &configVersion{APIVersion: 0}
+	var root interface{}
+	if err := yaml.Unmarshal(yamlFile, &root); err != nil {
+		return nil, yamlError(err)
+	}
 
-	// We ignore the error here because it errors out for version 0 which does not have apiVersion
-	// specified (so 0 is default). This can also error in case the apiVersion is not an integer but at the moment
-	// this does not handle that case and would still go on as if version = 0.
-	// TODO: return appropriate error in case the apiVersion is specified but isn't integer (or even if it is
-	//  integer > max version?).
-	_ = yaml.Unmarshal(yamlFile, &apiVersion)
+	switch content := root.(type) {
+	case nil:
+		cr.log.Warn("dashboard provisioning config file is empty", "filename", filename)
+		return []*config{}, nil
+	case []interface{}:
+		// version 0 files hold the list of providers at the top level
+		var v0 []*configV0
+		if err := yaml.Unmarshal(yamlFile, &v0); err != nil {
+			return nil, yamlError(err)
+		}
 
-	if apiVersion.APIVersion > 0 {
-		v1 := &configV1{}
-		err := yaml.Unmarshal(yamlFile, &v1)
-		if err != nil {
+		cr.log.Warn("[Deprecated] the dashboard provisioning config is outdated. please upgrade", "filename", filename)
+		return mapV0ToDashboardsAsConfig(v0)
+	case map[interface{}]interface{}:
+		if _, ok := content["apiVersion"]; !ok {
+			cr.log.Warn("dashboard provisioning config file has no apiVersion, assuming version 1. please add 'apiVersion: 1' at the top of the file",
+				"filename", filename)
+		} else if err := checkAPIVersion(content); err != nil {
 			return nil, err
 		}
 
-		if v1 != nil {
-			return v1.mapToDashboardsAsConfig()
-		}
-	} else {
-		var v0 []*configV0
-		err := yaml.Unmarshal(yamlFile, &v0)
-		if err != nil {
-			return nil, err
+		v1 := &configV1{}
+		if err := yaml.Unmarshal(yamlFile, &v1); err != nil {
+			return nil, yamlError(err)
 		}
 
-		if v0 != nil {
-			cr.log.Warn("[Deprecated] the dashboard provisioning config is outdated. please upgrade", "filename", filename)
-			return mapV0ToDashboardsAsConfig(v0)
+		if len(v1.Providers) == 0 {
+			cr.log.Warn("dashboard provisioning config file has no providers", "filename", filename)
 		}
+		return v1.mapToDashboardsAsConfig()
+	default:
+		return nil, fmt.Errorf("expected an object with apiVersion and providers")
 	}
+}
+
+// checkAPIVersion checks that the apiVersion declared by a config file holding an object is the only version using
+// that layout. An invalid apiVersion is reported rather than ignored, since the providers of the file would
+// otherwise silently not be provisioned.
+func checkAPIVersion(content map[interface{}]interface{}) error {
+	apiVersion, ok := content["apiVersion"].(int)
+	if !ok {
+		return fmt.Errorf("apiVersion must be an integer, got %q", fmt.Sprint(content["apiVersion"]))
+	}
+	if apiVersion != 1 {
+		return fmt.Errorf("unsupported apiVersion %d, set 'apiVersion: 1', or list the providers at the top of the file for the deprecated version 0 layout", apiVersion)
+	}
+
+	return nil
+}
 
-	return []*config{}, nil
+// yamlError turns the errors reported by the YAML decoder for values of the wrong type, each prefixed with the
+// line of the problem, into a single line error.
+func yamlError(err error) error {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("yaml: %s", strings.Join(typeErr.Errors, "; "))
+	}
+	return err
 }
 
 func (cr *configReader) readConfig(ctx context.Context) ([]*config, error) {
@@ -80,23 +116,68 @@ func (cr *configReader) readConfig(ctx context.Context) ([]*config, error) {
 
 		parsedDashboards, err := cr.parseConfigs(file)
 		if err != nil {
-			return nil, fmt.Errorf("could not parse provisioning config file: %s error: %v", file.Name(), err)
+			if cr.strict {
+				return nil, fmt.Errorf("could not parse provisioning config file: %s error: %w", file.Name(), err)
+			}
+			cr.log.Error("skipping invalid provisioning config file", "file", file.Name(), "error", err)
+			continue
 		}
 
+		dir, _ := filepath.Abs(cr.path)
+		for _, dashboard := range parsedDashboards {
+			dashboard.file = file.Name()
+			dashboard.dir = dir
+			dashboard.dataPath = cr.dataPath
+		}
 		if len(parsedDashboards) > 0 {
 			dashboards = append(dashboards, parsedDashboards...)
 		}
 	}
 
-	uidUsage := map[string]uint8{}
+	provisioned := make([]*config, 0, len(dashboards))
 	for _, dashboard := range dashboards {
 		if dashboard.OrgID == 0 {
 			dashboard.OrgID = 1
 		}
 
-		if err := utils.CheckOrgExists(ctx, cr.orgStore, dashboard.OrgID); err != nil {
+		onMissingOrg, err := onMissingOrgOption(dashboard.Options)
+		if err != nil {
 			return nil, fmt.Errorf("failed to provision dashboards with %q reader: %w", dashboard.Name, err)
 		}
+		orgIDs := []int64{dashboard.OrgID}
+
+		orgIDMapping, err := orgIDMappingOption(dashboard.Options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to provision dashboards with %q reader: %w", dashboard.Name, err)
+		}
+		mappedOrgIDs := make([]int64, 0, len(orgIDMapping))
+		for _, orgID := range orgIDMapping {
+			mappedOrgIDs = append(mappedOrgIDs, orgID)
+		}
+		// sorted so that missing organizations are created in a predictable order
+		sort.Slice(mappedOrgIDs, func(i, j int) bool { return mappedOrgIDs[i] < mappedOrgIDs[j] })
+		orgIDs = append(orgIDs, mappedOrgIDs...)
+
+		listedOrgIDs, err := orgIDsOption(dashboard.Options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to provision dashboards with %q reader: %w", dashboard.Name, err)
+		}
+		orgIDs = append(orgIDs, listedOrgIDs...)
+
+		exists := true
+		for _, orgID := range orgIDs {
+			exists, err = cr.checkOrgExists(ctx, dashboard, orgID, onMissingOrg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to provision dashboards with %q reader: %w", dashboard.Name, err)
+			}
+			if !exists {
+				break
+			}
+		}
+		if !exists {
+			continue
+		}
+		provisioned = append(provisioned, dashboard)
 
 		if dashboard.Type == "" {
 			dashboard.Type = "file"
@@ -105,16 +186,30 @@ func (cr *configReader) readConfig(ctx context.Context) ([]*config, error) {
 		if dashboard.UpdateIntervalSeconds == 0 {
 			dashboard.UpdateIntervalSeconds = 10
 		}
+	}
+
+	for uid, providers := range folderUIDCollisions(provisioned) {
+		cr.log.Error("the same folder UID is used more than once", "folderUid", uid, "providers", strings.Join(providers, ", "))
+	}
+
+	return expandOrgIDs(provisioned)
+}
+
+// folderUIDCollisions returns the folder UIDs used by more than one provider, along with the providers using them
+// and the files they are defined in.
+func folderUIDCollisions(dashboards []*config) map[string][]string {
+	uidUsage := map[string][]string{}
+	for _, dashboard := range dashboards {
 		if len(dashboard.FolderUID) > 0 {
-			uidUsage[dashboard.FolderUID]++
+			uidUsage[dashboard.FolderUID] = append(uidUsage[dashboard.FolderUID], fmt.Sprintf("%q (%s)", dashboard.Name, dashboard.file))
 		}
 	}
 
-	for uid, times := range uidUsage {
-		if times > 1 {
-			cr.log.Error("the same folder UID is used more than once", "folderUid", uid)
+	for uid, providers := range uidUsage {
+		if len(providers) < 2 {
+			delete(uidUsage, uid)
 		}
 	}
 
-	return dashboards, nil
+	return uidUsage
 }
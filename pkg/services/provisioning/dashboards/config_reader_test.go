@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -87,9 +88,74 @@ func TestDashboardsAsConfig(t *testing.T) {
 
 			require.Equal(t, 0, len(cfg))
 		})
+
+		t.Run("Invalid config files", func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "valid.yaml"),
+				[]byte("apiVersion: 1\nproviders:\n  - name: valid\n    options:\n      path: /tmp\n"), 0600))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"),
+				[]byte("apiVersion: 1\nproviders:\n  - name: invalid\n    disableDeletion: [true]\n"), 0600))
+
+			t.Run("Should be skipped when config is not strict", func(t *testing.T) {
+				cfgProvider := configReader{path: dir, log: logger, orgStore: store}
+				cfg, err := cfgProvider.readConfig(context.Background())
+				require.NoError(t, err)
+				require.Len(t, cfg, 1)
+				require.Equal(t, "valid", cfg[0].Name)
+			})
+
+			t.Run("Should fail with the file and line when config is strict", func(t *testing.T) {
+				cfgProvider := configReader{path: dir, log: logger, orgStore: store, strict: true}
+				_, err := cfgProvider.readConfig(context.Background())
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "invalid.yaml")
+				require.Contains(t, err.Error(), "line 4")
+			})
+		})
+
+		t.Run("Config files without an apiVersion should be read as version 1", func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "missing.yaml"),
+				[]byte("providers:\n  - name: default\n    options:\n      path: /tmp\n"), 0600))
+
+			cfgProvider := configReader{path: dir, log: logger, orgStore: store, strict: true}
+			cfg, err := cfgProvider.readConfig(context.Background())
+			require.NoError(t, err)
+			require.Len(t, cfg, 1)
+			require.Equal(t, "default", cfg[0].Name)
+		})
+
+		t.Run("Config files with an invalid apiVersion should be reported", func(t *testing.T) {
+			providers := "providers:\n  - name: default\n    options:\n      path: /tmp\n"
+			for name, content := range map[string]string{
+				"not-integer": "apiVersion: one\n" + providers,
+				"unsupported": "apiVersion: 2\n" + providers,
+				"zero":        "apiVersion: 0\n" + providers,
+			} {
+				dir := t.TempDir()
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name+".yaml"), []byte(content), 0600))
+
+				cfgProvider := configReader{path: dir, log: logger, orgStore: store, strict: true}
+				_, err := cfgProvider.readConfig(context.Background())
+				require.Error(t, err, name)
+				require.Contains(t, err.Error(), name+".yaml")
+				require.Contains(t, err.Error(), "apiVersion")
+			}
+		})
 	})
 }
 
+func TestFolderUIDCollisions(t *testing.T) {
+	collisions := folderUIDCollisions([]*config{
+		{Name: "team-a", FolderUID: "shared", file: "a.yaml"},
+		{Name: "team-b", FolderUID: "shared", file: "b.yaml"},
+		{Name: "team-c", FolderUID: "own", file: "b.yaml"},
+		{Name: "general"},
+	})
+
+	require.Equal(t, map[string][]string{"shared": {`"team-a" (a.yaml)`, `"team-b" (b.yaml)`}}, collisions)
+}
+
 func validateDashboardAsConfig(t *testing.T, cfg []*config) {
 	t.Helper()
 
@@ -0,0 +1,63 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestCreateOnly(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cpu.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"uid": "cpu", "title": "CPU"}`), 0600))
+	fileInfo, err := os.Stat(path)
+	require.NoError(t, err)
+
+	newReader := func(t *testing.T, store uidDashboardStore) (*FileReader, *dashboards.FakeDashboardProvisioning) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":       dir,
+			"createOnly": true,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, store)
+		require.NoError(t, err)
+		return reader, fakeService
+	}
+	save := func(t *testing.T, reader *FileReader, refs map[string]*models.DashboardProvisioning) {
+		_, err := reader.saveDashboard(context.Background(), path, 0, fileInfo, refs, newUsageTracker())
+		require.NoError(t, err)
+	}
+
+	t.Run("should create new dashboards", func(t *testing.T) {
+		reader, fakeService := newReader(t, uidDashboardStore{})
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 5}, nil).Once()
+
+		save(t, reader, map[string]*models.DashboardProvisioning{})
+	})
+
+	t.Run("should leave provisioned dashboards alone when their file changed", func(t *testing.T) {
+		reader, fakeService := newReader(t, uidDashboardStore{})
+
+		save(t, reader, map[string]*models.DashboardProvisioning{
+			path: {Id: 3, DashboardId: 5, Name: configName, ExternalId: path, CheckSum: "old"},
+		})
+		fakeService.AssertNotCalled(t, "SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+		require.Equal(t, 1, reader.stats.unchanged)
+	})
+
+	t.Run("should leave existing dashboards with the same uid alone", func(t *testing.T) {
+		reader, fakeService := newReader(t, uidDashboardStore{"cpu": 5})
+
+		save(t, reader, map[string]*models.DashboardProvisioning{})
+		fakeService.AssertNotCalled(t, "SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
+
+	"github.com/benbjohnson/clock"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
@@ -20,10 +23,22 @@ type DashboardProvisioner interface {
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
+	GetProvisionersStatus() []ProvisionerStatus
+	GetProvidersConfig() []ProviderConfig
+	ValidateDashboard(name string, path string, content []byte) (DashboardValidation, error)
+	SetTransformers(transformers ...DashboardTransformer)
+	SetSecretsDecrypter(decrypter SecretsDecrypter)
+	PromoteCanary(name string) error
+	UnprovisionAll(ctx context.Context, name string, deleteDashboards bool) (int, error)
+	ForceReprovision()
+	Wait()
+	Export(ctx context.Context, name string, orgID int64, dir string) (int, error)
+	RunProviders(ctx context.Context, name string) ([]ProvisionerStatus, error)
+	WaitForGeneration(ctx context.Context, name string, generation int64) error
 }
 
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
-type DashboardProvisionerFactory func(context.Context, string, dashboards.DashboardProvisioningService, utils.OrgStore, utils.DashboardStore) (DashboardProvisioner, error)
+type DashboardProvisionerFactory func(context.Context, string, string, dashboards.DashboardProvisioningService, utils.OrgStore, utils.DashboardStore, bool, string, int) (DashboardProvisioner, error)
 
 // Provisioner is responsible for syncing dashboard from disk to Grafana's database.
 type Provisioner struct {
@@ -32,16 +47,41 @@ type Provisioner struct {
 	configs            []*config
 	duplicateValidator duplicateValidator
 	provisioner        dashboards.DashboardProvisioningService
+	dashboardStore     utils.DashboardStore
+	cfgReader          *configReader
+	transformers       []DashboardTransformer
+	secrets            SecretsDecrypter
+	orphanStrategy     string
+	clock              clock.Clock
+	runSlots           runSlots
+
+	// configChecksum is the checksum of the config files the providers were last read from.
+	configChecksum string
+	// pollers tracks the polling goroutines of the providers by name.
+	pollers map[string]*readerPoller
+	// mutex guards the providers, which are replaced when the config files change.
+	mutex sync.RWMutex
 }
 
 func (provider *Provisioner) HasDashboardSources() bool {
 	return len(provider.fileReaders) > 0
 }
 
-// New returns a new DashboardProvisioner
-func New(ctx context.Context, configDirectory string, provisioner dashboards.DashboardProvisioningService, orgStore utils.OrgStore, dashboardStore utils.DashboardStore) (DashboardProvisioner, error) {
+// New returns a new DashboardProvisioner. dataPath is the data directory of Grafana, under which the archives of
+// the providers are extracted. Unless strictConfig is set, invalid config files are skipped instead of failing the
+// creation of the provisioner. orphanStrategy tells what to do with the dashboards of the providers
+// which no longer exist. maxConcurrentRuns bounds how many providers may run at once, zero meaning no limit.
+func New(ctx context.Context, configDirectory string, dataPath string, provisioner dashboards.DashboardProvisioningService, orgStore utils.OrgStore,
+	dashboardStore utils.DashboardStore, strictConfig bool, orphanStrategy string, maxConcurrentRuns int) (DashboardProvisioner, error) {
 	logger := log.New("provisioning.dashboard")
-	cfgReader := &configReader{path: configDirectory, log: logger, orgStore: orgStore}
+	orphanStrategy, err := validateOrphanStrategy(orphanStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgReader := &configReader{path: configDirectory, dataPath: dataPath, log: logger, orgStore: orgStore, strict: strictConfig}
+	// computed before reading the configs, so that changes made in between are picked up by the next check
+	configChecksum, _ := configDirectoryChecksum(configDirectory)
 	configs, err := cfgReader.readConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%v: %w", "Failed to read dashboards config", err)
@@ -51,6 +91,10 @@ func New(ctx context.Context, configDirectory string, provisioner dashboards.Das
 	if err != nil {
 		return nil, fmt.Errorf("%v: %w", "Failed to initialize file readers", err)
 	}
+	slots := newRunSlots(maxConcurrentRuns)
+	for _, reader := range fileReaders {
+		reader.runSlots = slots
+	}
 
 	d := &Provisioner{
 		log:                logger,
@@ -58,6 +102,13 @@ func New(ctx context.Context, configDirectory string, provisioner dashboards.Das
 		configs:            configs,
 		duplicateValidator: newDuplicateValidator(logger, fileReaders),
 		provisioner:        provisioner,
+		dashboardStore:     dashboardStore,
+		cfgReader:          cfgReader,
+		configChecksum:     configChecksum,
+		pollers:            map[string]*readerPoller{},
+		orphanStrategy:     orphanStrategy,
+		clock:              clock.New(),
+		runSlots:           slots,
 	}
 
 	return d, nil
@@ -67,6 +118,12 @@ func New(ctx context.Context, configDirectory string, provisioner dashboards.Das
 // the database with the latest versions of those dashboards.
 func (provider *Provisioner) Provision(ctx context.Context) error {
 	for _, reader := range provider.fileReaders {
+		if reader.hasStartupDelay() {
+			provider.log.Debug("Deferring first run of provisioner", "name", reader.Cfg.Name, "delay", reader.StartupDelay,
+				"jitter", reader.StartupJitter)
+			continue
+		}
+
 		if err := reader.walkDisk(ctx); err != nil {
 			if os.IsNotExist(err) {
 				// don't stop the provisioning service in case the folder is missing. The folder can appear after the startup
@@ -82,32 +139,64 @@ func (provider *Provisioner) Provision(ctx context.Context) error {
 	return nil
 }
 
-// CleanUpOrphanedDashboards deletes provisioned dashboards missing a linked reader.
+// CleanUpOrphanedDashboards handles the provisioned dashboards missing a linked reader according to the orphan
+// strategy of the provisioner.
 func (provider *Provisioner) CleanUpOrphanedDashboards(ctx context.Context) {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+
 	currentReaders := make([]string, len(provider.fileReaders))
 
 	for index, reader := range provider.fileReaders {
 		currentReaders[index] = reader.Cfg.Name
 	}
 
+	if provider.orphanStrategy != orphanStrategyDelete {
+		provider.keepOrphanedDashboards(ctx, currentReaders)
+		return
+	}
+
 	if err := provider.provisioner.DeleteOrphanedProvisionedDashboards(ctx, &models.DeleteOrphanedProvisionedDashboardsCommand{ReaderNames: currentReaders}); err != nil {
 		provider.log.Warn("Failed to delete orphaned provisioned dashboards", "err", err)
 	}
 }
 
 // PollChanges starts polling for changes in dashboard definition files. It creates a goroutine for each provider
-// defined in the config.
+// defined in the config, and one reloading the providers when the config files change.
 func (provider *Provisioner) PollChanges(ctx context.Context) {
-	for _, reader := range provider.fileReaders {
-		go reader.pollChanges(ctx)
+	provider.mutex.RLock()
+	readers := provider.fileReaders
+	provider.mutex.RUnlock()
+
+	for _, reader := range readers {
+		provider.startPolling(ctx, reader, false)
 	}
 
 	go provider.duplicateValidator.Run(ctx)
+	go provider.watchConfigs(ctx)
+}
+
+// Wait blocks until the polling goroutines of every provider have returned, which includes finishing the dashboards
+// they were saving when polling was cancelled.
+func (provider *Provisioner) Wait() {
+	provider.mutex.RLock()
+	pollers := make([]*readerPoller, 0, len(provider.pollers))
+	for _, poller := range provider.pollers {
+		pollers = append(pollers, poller)
+	}
+	provider.mutex.RUnlock()
+
+	for _, poller := range pollers {
+		<-poller.done
+	}
 }
 
 // GetProvisionerResolvedPath returns resolved path for the specified provisioner name. Can be used to generate
 // relative path to provisioning file from it's external_id.
 func (provider *Provisioner) GetProvisionerResolvedPath(name string) string {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+
 	for _, reader := range provider.fileReaders {
 		if reader.Cfg.Name == name {
 			return reader.resolvedPath()
@@ -118,6 +207,9 @@ func (provider *Provisioner) GetProvisionerResolvedPath(name string) string {
 
 // GetAllowUIUpdatesFromConfig return if a dashboard provisioner allows updates from the UI
 func (provider *Provisioner) GetAllowUIUpdatesFromConfig(name string) bool {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+
 	for _, config := range provider.configs {
 		if config.Name == name {
 			return config.AllowUIUpdates
@@ -126,6 +218,133 @@ func (provider *Provisioner) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// GetProvisionersStatus returns the status of the last run of every provisioner.
+func (provider *Provisioner) GetProvisionersStatus() []ProvisionerStatus {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+
+	statuses := make([]ProvisionerStatus, 0, len(provider.fileReaders))
+	for _, reader := range provider.fileReaders {
+		statuses = append(statuses, reader.getStatus())
+	}
+	return statuses
+}
+
+// GetProvidersConfig returns the effective configuration of every provider.
+func (provider *Provisioner) GetProvidersConfig() []ProviderConfig {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+
+	configs := make([]ProviderConfig, 0, len(provider.fileReaders))
+	for _, reader := range provider.fileReaders {
+		configs = append(configs, reader.providerConfig())
+	}
+	return configs
+}
+
+// ValidateDashboard reads content the same way the provider with the given name would provision it from path,
+// relative to the path of the provider, without saving anything.
+func (provider *Provisioner) ValidateDashboard(name string, path string, content []byte) (DashboardValidation, error) {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name == name {
+			return reader.validateDashboardContent(path, content)
+		}
+	}
+	return DashboardValidation{}, ErrProvisionerNotFound
+}
+
+// PromoteCanary switches the provisioner with the given name from its canary to its whole path, starting with
+// its next run.
+func (provider *Provisioner) PromoteCanary(name string) error {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name == name {
+			return reader.promoteCanary()
+		}
+	}
+	return ErrProvisionerNotFound
+}
+
+// WaitForGeneration waits until the provider with the given name reached the given generation of its status, see
+// FileReader.WaitForGeneration.
+func (provider *Provisioner) WaitForGeneration(ctx context.Context, name string, generation int64) error {
+	provider.mutex.RLock()
+	var found *FileReader
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name == name {
+			found = reader
+			break
+		}
+	}
+	provider.mutex.RUnlock()
+
+	if found == nil {
+		return ErrProvisionerNotFound
+	}
+	return found.WaitForGeneration(ctx, generation)
+}
+
+// RunProviders runs the provider with the given name right away, or every provider when name is empty, and returns
+// their status once their run is done. A failed run is reported in the status of its provider rather than
+// preventing the other providers from running.
+func (provider *Provisioner) RunProviders(ctx context.Context, name string) ([]ProvisionerStatus, error) {
+	// the readers are run without holding the lock, so that a slow run doesn't block the other callers
+	provider.mutex.RLock()
+	readers := make([]*FileReader, len(provider.fileReaders))
+	copy(readers, provider.fileReaders)
+	provider.mutex.RUnlock()
+
+	statuses := []ProvisionerStatus{}
+	for _, reader := range readers {
+		if name != "" && reader.Cfg.Name != name {
+			continue
+		}
+		if err := reader.walkDisk(ctx); err != nil {
+			provider.log.Warn("Failed to run provisioner", "name", reader.Cfg.Name, "error", err)
+		}
+		statuses = append(statuses, reader.getStatus())
+	}
+
+	if name != "" && len(statuses) == 0 {
+		return nil, ErrProvisionerNotFound
+	}
+	return statuses, nil
+}
+
+// ForceReprovision makes the next run of every provider save all its dashboards again, regardless of their
+// checksum.
+func (provider *Provisioner) ForceReprovision() {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+
+	for _, reader := range provider.fileReaders {
+		reader.forceReprovision()
+	}
+}
+
+// SetTransformers sets the transformers applied to the dashboards of every provider. It must be called before the
+// provisioner starts provisioning.
+func (provider *Provisioner) SetTransformers(transformers ...DashboardTransformer) {
+	provider.transformers = transformers
+	for _, reader := range provider.fileReaders {
+		reader.Transformers = transformers
+	}
+}
+
+// SetSecretsDecrypter sets the decrypter of the secrets embedded in the dashboards of every provider. It must be
+// called before the provisioner starts provisioning.
+func (provider *Provisioner) SetSecretsDecrypter(decrypter SecretsDecrypter) {
+	provider.secrets = decrypter
+	for _, reader := range provider.fileReaders {
+		reader.Secrets = decrypter
+	}
+}
+
 func getFileReaders(
 	configs []*config, logger log.Logger, service dashboards.DashboardProvisioningService, store utils.DashboardStore,
 ) ([]*FileReader, error) {
@@ -8,6 +8,18 @@ type calls struct {
 	PollChanges                 []interface{}
 	GetProvisionerResolvedPath  []interface{}
 	GetAllowUIUpdatesFromConfig []interface{}
+	GetProvisionersStatus       []interface{}
+	SetTransformers             []interface{}
+	SetSecretsDecrypter         []interface{}
+	PromoteCanary               []interface{}
+	UnprovisionAll              []interface{}
+	ForceReprovision            []interface{}
+	Wait                        []interface{}
+	Export                      []interface{}
+	RunProviders                []interface{}
+	WaitForGeneration           []interface{}
+	GetProvidersConfig          []interface{}
+	ValidateDashboard           []interface{}
 }
 
 // ProvisionerMock is a mock implementation of `Provisioner`
@@ -17,6 +29,18 @@ type ProvisionerMock struct {
 	PollChangesFunc                 func(ctx context.Context)
 	GetProvisionerResolvedPathFunc  func(name string) string
 	GetAllowUIUpdatesFromConfigFunc func(name string) bool
+	GetProvisionersStatusFunc       func() []ProvisionerStatus
+	SetTransformersFunc             func(transformers ...DashboardTransformer)
+	SetSecretsDecrypterFunc         func(decrypter SecretsDecrypter)
+	PromoteCanaryFunc               func(name string) error
+	UnprovisionAllFunc              func(ctx context.Context, name string, deleteDashboards bool) (int, error)
+	ForceReprovisionFunc            func()
+	WaitFunc                        func()
+	ExportFunc                      func(ctx context.Context, name string, orgID int64, dir string) (int, error)
+	RunProvidersFunc                func(ctx context.Context, name string) ([]ProvisionerStatus, error)
+	WaitForGenerationFunc           func(ctx context.Context, name string, generation int64) error
+	GetProvidersConfigFunc          func() []ProviderConfig
+	ValidateDashboardFunc           func(name string, path string, content []byte) (DashboardValidation, error)
 }
 
 // NewDashboardProvisionerMock returns a new dashboardprovisionermock
@@ -65,5 +89,109 @@ func (dpm *ProvisionerMock) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// GetProvisionersStatus is a mock implementation of `Provisioner.GetProvisionersStatus`
+func (dpm *ProvisionerMock) GetProvisionersStatus() []ProvisionerStatus {
+	dpm.Calls.GetProvisionersStatus = append(dpm.Calls.GetProvisionersStatus, nil)
+	if dpm.GetProvisionersStatusFunc != nil {
+		return dpm.GetProvisionersStatusFunc()
+	}
+	return nil
+}
+
 // CleanUpOrphanedDashboards not implemented for mocks
 func (dpm *ProvisionerMock) CleanUpOrphanedDashboards(ctx context.Context) {}
+
+// SetTransformers is a mock implementation of `Provisioner.SetTransformers`
+func (dpm *ProvisionerMock) SetTransformers(transformers ...DashboardTransformer) {
+	dpm.Calls.SetTransformers = append(dpm.Calls.SetTransformers, transformers)
+	if dpm.SetTransformersFunc != nil {
+		dpm.SetTransformersFunc(transformers...)
+	}
+}
+
+// SetSecretsDecrypter is a mock implementation of `Provisioner.SetSecretsDecrypter`
+func (dpm *ProvisionerMock) SetSecretsDecrypter(decrypter SecretsDecrypter) {
+	dpm.Calls.SetSecretsDecrypter = append(dpm.Calls.SetSecretsDecrypter, decrypter)
+	if dpm.SetSecretsDecrypterFunc != nil {
+		dpm.SetSecretsDecrypterFunc(decrypter)
+	}
+}
+
+// PromoteCanary is a mock implementation of `Provisioner.PromoteCanary`
+func (dpm *ProvisionerMock) PromoteCanary(name string) error {
+	dpm.Calls.PromoteCanary = append(dpm.Calls.PromoteCanary, name)
+	if dpm.PromoteCanaryFunc != nil {
+		return dpm.PromoteCanaryFunc(name)
+	}
+	return nil
+}
+
+// UnprovisionAll is a mock implementation of `Provisioner.UnprovisionAll`
+func (dpm *ProvisionerMock) UnprovisionAll(ctx context.Context, name string, deleteDashboards bool) (int, error) {
+	dpm.Calls.UnprovisionAll = append(dpm.Calls.UnprovisionAll, name)
+	if dpm.UnprovisionAllFunc != nil {
+		return dpm.UnprovisionAllFunc(ctx, name, deleteDashboards)
+	}
+	return 0, nil
+}
+
+// ForceReprovision is a mock implementation of `Provisioner.ForceReprovision`
+func (dpm *ProvisionerMock) ForceReprovision() {
+	dpm.Calls.ForceReprovision = append(dpm.Calls.ForceReprovision, nil)
+	if dpm.ForceReprovisionFunc != nil {
+		dpm.ForceReprovisionFunc()
+	}
+}
+
+// Wait is a mock implementation of `Provisioner.Wait`
+func (dpm *ProvisionerMock) Wait() {
+	dpm.Calls.Wait = append(dpm.Calls.Wait, nil)
+	if dpm.WaitFunc != nil {
+		dpm.WaitFunc()
+	}
+}
+
+// Export is a mock implementation of `Provisioner.Export`
+func (dpm *ProvisionerMock) Export(ctx context.Context, name string, orgID int64, dir string) (int, error) {
+	dpm.Calls.Export = append(dpm.Calls.Export, name)
+	if dpm.ExportFunc != nil {
+		return dpm.ExportFunc(ctx, name, orgID, dir)
+	}
+	return 0, nil
+}
+
+// RunProviders is a mock implementation of `Provisioner.RunProviders`
+func (dpm *ProvisionerMock) RunProviders(ctx context.Context, name string) ([]ProvisionerStatus, error) {
+	dpm.Calls.RunProviders = append(dpm.Calls.RunProviders, name)
+	if dpm.RunProvidersFunc != nil {
+		return dpm.RunProvidersFunc(ctx, name)
+	}
+	return nil, nil
+}
+
+// WaitForGeneration is a mock implementation of `Provisioner.WaitForGeneration`
+func (dpm *ProvisionerMock) WaitForGeneration(ctx context.Context, name string, generation int64) error {
+	dpm.Calls.WaitForGeneration = append(dpm.Calls.WaitForGeneration, name)
+	if dpm.WaitForGenerationFunc != nil {
+		return dpm.WaitForGenerationFunc(ctx, name, generation)
+	}
+	return nil
+}
+
+// GetProvidersConfig is a mock implementation of `Provisioner.GetProvidersConfig`
+func (dpm *ProvisionerMock) GetProvidersConfig() []ProviderConfig {
+	dpm.Calls.GetProvidersConfig = append(dpm.Calls.GetProvidersConfig, nil)
+	if dpm.GetProvidersConfigFunc != nil {
+		return dpm.GetProvidersConfigFunc()
+	}
+	return nil
+}
+
+// ValidateDashboard is a mock implementation of `Provisioner.ValidateDashboard`
+func (dpm *ProvisionerMock) ValidateDashboard(name string, path string, content []byte) (DashboardValidation, error) {
+	dpm.Calls.ValidateDashboard = append(dpm.Calls.ValidateDashboard, name)
+	if dpm.ValidateDashboardFunc != nil {
+		return dpm.ValidateDashboardFunc(name, path, content)
+	}
+	return DashboardValidation{}, nil
+}
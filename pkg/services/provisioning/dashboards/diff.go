@@ -0,0 +1,85 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// maxDiffBytes caps the size of the logged diffs, longer ones are truncated.
+const maxDiffBytes = 16 * 1024
+
+// logDiff logs what changed between the stored version of a provisioned dashboard and the one read from its file.
+// Dashboards holding secrets are left out, as the stored version holds them decrypted.
+func (fr *FileReader) logDiff(ctx context.Context, path string, dash *dashboards.SaveDashboardDTO, provisionedData *models.DashboardProvisioning) {
+	if hasSecrets(dash.Dashboard.Data.Interface()) {
+		fr.log.Debug("not computing the diff of a dashboard holding secrets", "file", path)
+		return
+	}
+
+	diff, err := fr.dashboardDiff(ctx, dash, provisionedData)
+	if err != nil {
+		fr.log.Warn("failed to compute the diff of dashboard", "file", path, "error", err)
+		return
+	}
+	if diff == "" {
+		return
+	}
+
+	truncated := len(diff) > maxDiffBytes
+	if truncated {
+		diff = diff[:maxDiffBytes]
+	}
+	fr.log.Info("provisioned dashboard changed", "provisioner", fr.Cfg.Name, "file", path, "uid", dash.Dashboard.Uid,
+		"diff", diff, "truncated", truncated)
+}
+
+// dashboardDiff returns the delta between the stored version of a provisioned dashboard and dash, in the
+// jsondiffpatch format. It's empty if they don't differ or if the dashboard isn't stored anymore.
+func (fr *FileReader) dashboardDiff(ctx context.Context, dash *dashboards.SaveDashboardDTO, provisionedData *models.DashboardProvisioning) (string, error) {
+	query := &models.GetDashboardQuery{OrgId: dash.OrgId, Id: provisionedData.DashboardId}
+	if err := fr.dashboardStore.GetDashboard(ctx, query); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	stored, err := withoutVersionFields(query.Result.Data)
+	if err != nil {
+		return "", err
+	}
+	provisioned, err := withoutVersionFields(dash.Dashboard.Data)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := dashdiffs.CalculateDiff(ctx, &dashdiffs.Options{DiffType: dashdiffs.DiffDelta}, stored, provisioned)
+	if errors.Is(err, dashdiffs.ErrNilDiff) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(result.Delta), nil
+}
+
+// withoutVersionFields returns a copy of data without the fields set by the database, which always differ from
+// the file.
+func withoutVersionFields(data *simplejson.Json) (*simplejson.Json, error) {
+	raw, err := data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	cleaned, err := simplejson.NewJson(raw)
+	if err != nil {
+		return nil, err
+	}
+	cleaned.Del("id")
+	cleaned.Del("version")
+	return cleaned, nil
+}
@@ -0,0 +1,71 @@
+package dashboards
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+type storedDashboardStore struct {
+	data *simplejson.Json
+}
+
+func (s *storedDashboardStore) GetDashboard(_ context.Context, query *models.GetDashboardQuery) error {
+	if s.data == nil {
+		return dashboards.ErrDashboardNotFound
+	}
+	query.Result = &models.Dashboard{Id: query.Id, Data: s.data}
+	return nil
+}
+
+func TestDashboardDiff(t *testing.T) {
+	store := &storedDashboardStore{}
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": defaultDashboards, "emitDiffs": true}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, store)
+	require.NoError(t, err)
+	require.True(t, reader.EmitDiffs)
+
+	provisionedData := &models.DashboardProvisioning{DashboardId: 3}
+	newDash := func(data string) *dashboards.SaveDashboardDTO {
+		json, err := simplejson.NewJson([]byte(data))
+		require.NoError(t, err)
+		return &dashboards.SaveDashboardDTO{OrgId: 1, Dashboard: models.NewDashboardFromJson(json)}
+	}
+
+	t.Run("should describe the changed fields", func(t *testing.T) {
+		store.data = newDash(`{"id": 3, "version": 7, "title": "CPU", "refresh": "1m"}`).Dashboard.Data
+
+		diff, err := reader.dashboardDiff(context.Background(), newDash(`{"title": "CPU", "refresh": "5m"}`), provisionedData)
+		require.NoError(t, err)
+		require.Contains(t, diff, "refresh")
+		require.NotContains(t, diff, "version")
+		require.NotContains(t, diff, "title")
+	})
+
+	t.Run("should be empty without changes", func(t *testing.T) {
+		store.data = newDash(`{"id": 3, "version": 7, "title": "CPU"}`).Dashboard.Data
+
+		diff, err := reader.dashboardDiff(context.Background(), newDash(`{"title": "CPU"}`), provisionedData)
+		require.NoError(t, err)
+		require.Empty(t, diff)
+	})
+
+	t.Run("should be empty once the dashboard is gone", func(t *testing.T) {
+		store.data = nil
+
+		diff, err := reader.dashboardDiff(context.Background(), newDash(`{"title": "CPU"}`), provisionedData)
+		require.NoError(t, err)
+		require.Empty(t, diff)
+	})
+
+	t.Run("should detect secrets", func(t *testing.T) {
+		require.True(t, hasSecrets(newDash(`{"panels": [{"token": {"$secret": "c2VjcmV0"}}]}`).Dashboard.Data.Interface()))
+		require.False(t, hasSecrets(newDash(`{"panels": [{"token": "plain"}]}`).Dashboard.Data.Interface()))
+	})
+}
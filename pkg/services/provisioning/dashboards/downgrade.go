@@ -0,0 +1,25 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// isDowngrade reports whether saving dash would lower the `schemaVersion` of the stored version of a provisioned
+// dashboard, along with both versions. Dashboards which aren't stored anymore are never downgrades.
+func (fr *FileReader) isDowngrade(ctx context.Context, dash *dashboards.SaveDashboardDTO, provisionedData *models.DashboardProvisioning) (bool, int64, int64, error) {
+	query := &models.GetDashboardQuery{OrgId: dash.OrgId, Id: provisionedData.DashboardId}
+	if err := fr.dashboardStore.GetDashboard(ctx, query); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return false, 0, 0, nil
+		}
+		return false, 0, 0, err
+	}
+
+	stored := query.Result.Data.Get("schemaVersion").MustInt64()
+	provisioned := dash.Dashboard.Data.Get("schemaVersion").MustInt64()
+	return provisioned < stored, stored, provisioned, nil
+}
@@ -0,0 +1,41 @@
+package dashboards
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestPreventDowngrade(t *testing.T) {
+	store := &storedDashboardStore{}
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": defaultDashboards, "preventDowngrade": true}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, store)
+	require.NoError(t, err)
+	require.True(t, reader.PreventDowngrade)
+
+	provisionedData := &models.DashboardProvisioning{DashboardId: 3}
+	newDash := func(schemaVersion int) *dashboards.SaveDashboardDTO {
+		data := simplejson.NewFromAny(map[string]interface{}{"title": "CPU", "schemaVersion": schemaVersion})
+		return &dashboards.SaveDashboardDTO{OrgId: 1, Dashboard: models.NewDashboardFromJson(data)}
+	}
+	store.data = newDash(30).Dashboard.Data
+
+	for schemaVersion, downgrade := range map[int]bool{29: true, 30: false, 31: false} {
+		isDowngrade, stored, provisioned, err := reader.isDowngrade(context.Background(), newDash(schemaVersion), provisionedData)
+		require.NoError(t, err)
+		require.Equal(t, downgrade, isDowngrade, schemaVersion)
+		require.Equal(t, int64(30), stored)
+		require.Equal(t, int64(schemaVersion), provisioned)
+	}
+
+	store.data = nil
+	isDowngrade, _, _, err := reader.isDowngrade(context.Background(), newDash(29), provisionedData)
+	require.NoError(t, err)
+	require.False(t, isDowngrade)
+}
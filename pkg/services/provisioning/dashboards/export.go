@@ -0,0 +1,107 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// Export writes the dashboards of the organization orgID which aren't provisioned, such as those created from the UI,
+// as dashboard files into dir, ready to be provisioned by the provider with the given name. When the provider creates
+// its folders from the file structure, every dashboard is written into the directory named after its folder. Files
+// are named after the uid of the dashboard, and the fields Grafana sets on save are left out, so that provisioning
+// the exported files doesn't change the dashboards. Provisioned dashboards are left out, as their files already
+// exist and their stored content may hold decrypted secrets. It returns the number of dashboards written.
+func (provider *Provisioner) Export(ctx context.Context, name string, orgID int64, dir string) (int, error) {
+	provider.mutex.RLock()
+	var reader *FileReader
+	for _, r := range provider.fileReaders {
+		if r.Cfg.Name == name {
+			reader = r
+		}
+	}
+	provider.mutex.RUnlock()
+	if reader == nil {
+		return 0, fmt.Errorf("%w: %s", ErrProvisionerNotFound, name)
+	}
+
+	query := &models.GetDashboardsByOrgIdQuery{OrgId: orgID, ExcludeProvisioned: true}
+	if err := provider.provisioner.GetDashboardsByOrgID(ctx, query); err != nil {
+		return 0, fmt.Errorf("failed to list dashboards: %w", err)
+	}
+
+	count := 0
+	written := map[string]string{}
+	for _, dashboard := range query.Result {
+		folderPath := ""
+		if reader.FoldersFromFilesStructure {
+			var err error
+			folderPath, err = provider.exportFolderPath(ctx, dashboard.FolderId, orgID, reader.NestedFolders)
+			if err != nil {
+				return count, err
+			}
+		}
+
+		path := filepath.Join(dir, folderPath, exportFileName(dashboard))
+		if other, exists := written[path]; exists {
+			return count, fmt.Errorf("dashboards %q and %q would both be exported to %s", other, dashboard.Uid, path)
+		}
+		written[path] = dashboard.Uid
+
+		if err := writeExportedDashboard(path, dashboard); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	provider.log.Info("Exported dashboards", "provisioner", name, "orgId", orgID, "dir", dir, "count", count)
+	return count, nil
+}
+
+// exportFolderPath returns the directory of the dashboards of the folder folderID, relative to the export directory.
+// Unless nested is set, only the folder itself is used rather than the path through its parents.
+func (provider *Provisioner) exportFolderPath(ctx context.Context, folderID, orgID int64, nested bool) (string, error) {
+	var elements []string
+	for folderID != 0 {
+		query := &models.GetDashboardQuery{Id: folderID, OrgId: orgID}
+		if err := provider.dashboardStore.GetDashboard(ctx, query); err != nil {
+			return "", fmt.Errorf("failed to read folder %d: %w", folderID, err)
+		}
+		// a folder title can't spread over several directories
+		elements = append([]string{strings.ReplaceAll(query.Result.Title, "/", "-")}, elements...)
+		if !nested {
+			break
+		}
+		folderID = query.Result.FolderId
+	}
+	return filepath.Join(elements...), nil
+}
+
+// exportFileName returns the name of the file a dashboard is exported to.
+func exportFileName(dashboard *models.Dashboard) string {
+	if dashboard.Uid != "" {
+		return dashboard.Uid + ".json"
+	}
+	return dashboard.Slug + ".json"
+}
+
+func writeExportedDashboard(path string, dashboard *models.Dashboard) error {
+	data, err := withoutVersionFields(dashboard.Data)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(data.Interface(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0600)
+}
@@ -0,0 +1,113 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// idDashboardStore holds dashboards and folders by id.
+type idDashboardStore map[int64]*models.Dashboard
+
+func (s idDashboardStore) GetDashboard(_ context.Context, query *models.GetDashboardQuery) error {
+	dashboard, ok := s[query.Id]
+	if !ok {
+		return dashboards.ErrDashboardNotFound
+	}
+	query.Result = dashboard
+	return nil
+}
+
+func TestExport(t *testing.T) {
+	store := idDashboardStore{
+		1: {Id: 1, Title: "Team A", IsFolder: true},
+	}
+	uiDashboards := []*models.Dashboard{
+		{Id: 2, Uid: "cpu", FolderId: 1, Data: simplejson.NewFromAny(map[string]interface{}{
+			"id": 2, "uid": "cpu", "title": "CPU", "version": 7,
+		})},
+		{Id: 3, Uid: "memory", Data: simplejson.NewFromAny(map[string]interface{}{
+			"id": 3, "uid": "memory", "title": "Memory", "version": 2,
+		})},
+	}
+
+	newProvisioner := func(t *testing.T, options map[string]interface{}) *Provisioner {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+		fakeService.On("GetDashboardsByOrgID", context.Background(), mock.MatchedBy(func(query *models.GetDashboardsByOrgIdQuery) bool {
+			return query.OrgId == 1 && query.ExcludeProvisioned
+		})).Return(nil).Once().Run(func(args mock.Arguments) {
+			args.Get(1).(*models.GetDashboardsByOrgIdQuery).Result = uiDashboards
+		})
+
+		options["path"] = defaultDashboards
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, store)
+		require.NoError(t, err)
+		return &Provisioner{
+			log:            log.New("test-logger"),
+			fileReaders:    []*FileReader{reader},
+			provisioner:    fakeService,
+			dashboardStore: store,
+		}
+	}
+
+	t.Run("should write the dashboards of the organization which aren't provisioned", func(t *testing.T) {
+		dir := t.TempDir()
+		count, err := newProvisioner(t, map[string]interface{}{}).Export(context.Background(), configName, 1, dir)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		content, err := os.ReadFile(filepath.Join(dir, "cpu.json"))
+		require.NoError(t, err)
+		data, err := simplejson.NewJson(content)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"uid": "cpu", "title": "CPU"}, data.Interface())
+		require.FileExists(t, filepath.Join(dir, "memory.json"))
+	})
+
+	t.Run("should write the dashboards into the directories of their folders", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := newProvisioner(t, map[string]interface{}{"foldersFromFilesStructure": true}).
+			Export(context.Background(), configName, 1, dir)
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(dir, "Team A", "cpu.json"))
+		require.FileExists(t, filepath.Join(dir, "memory.json"))
+	})
+
+	t.Run("should fail for unknown providers", func(t *testing.T) {
+		provisioner := &Provisioner{log: log.New("test-logger")}
+		_, err := provisioner.Export(context.Background(), configName, 1, t.TempDir())
+		require.ErrorIs(t, err, ErrProvisionerNotFound)
+	})
+
+	t.Run("should provision the exported files without changes", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := newProvisioner(t, map[string]interface{}{}).Export(context.Background(), configName, 1, dir)
+		require.NoError(t, err)
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": dir}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		path := filepath.Join(dir, "cpu.json")
+		fileInfo, err := os.Stat(path)
+		require.NoError(t, err)
+		jsonFile, err := reader.readDashboardFromFile(path, fileInfo, 0)
+		require.NoError(t, err)
+
+		stored, err := withoutVersionFields(uiDashboards[0].Data)
+		require.NoError(t, err)
+		provisioned, err := withoutVersionFields(jsonFile.dashboard.Dashboard.Data)
+		require.NoError(t, err)
+		require.Equal(t, stored.Interface(), provisioned.Interface())
+	})
+}
@@ -0,0 +1,88 @@
+package dashboards
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// fileCache keeps the parsed content of dashboard files between polls so that unchanged files,
+// identified by their modification time and size, don't have to be read, hashed and parsed again.
+type fileCache struct {
+	mux     sync.Mutex
+	entries map[string]*fileCacheEntry
+}
+
+type fileCacheEntry struct {
+	modTime  time.Time
+	size     int64
+	checkSum string
+	data     *simplejson.Json
+}
+
+func newFileCache() *fileCache {
+	return &fileCache{entries: map[string]*fileCacheEntry{}}
+}
+
+// get returns a copy of the cached content for path if the file did not change since it was cached.
+func (c *fileCache) get(path string, fileInfo os.FileInfo) (*simplejson.Json, string, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	entry, ok := c.entries[path]
+	if !ok || !entry.modTime.Equal(fileInfo.ModTime()) || entry.size != fileInfo.Size() {
+		return nil, "", false
+	}
+
+	return copyJSON(entry.data), entry.checkSum, true
+}
+
+func (c *fileCache) set(path string, fileInfo os.FileInfo, data *simplejson.Json, checkSum string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.entries[path] = &fileCacheEntry{
+		modTime:  fileInfo.ModTime(),
+		size:     fileInfo.Size(),
+		checkSum: checkSum,
+		data:     copyJSON(data),
+	}
+}
+
+// prune drops the entries of files which are no longer on disk.
+func (c *fileCache) prune(filesFoundOnDisk map[string]os.FileInfo) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for path := range c.entries {
+		if _, exists := filesFoundOnDisk[path]; !exists {
+			delete(c.entries, path)
+		}
+	}
+}
+
+// copyJSON returns a deep copy of data, as dashboards built from it are modified before being saved.
+func copyJSON(data *simplejson.Json) *simplejson.Json {
+	return simplejson.NewFromAny(copyJSONValue(data.Interface()))
+}
+
+func copyJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = copyJSONValue(val)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, val := range v {
+			a[i] = copyJSONValue(val)
+		}
+		return a
+	default:
+		return v
+	}
+}
@@ -0,0 +1,75 @@
+package dashboards
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+type cacheFileInfo struct {
+	*FakeFileInfo
+	modTime time.Time
+	size    int64
+}
+
+func (fi cacheFileInfo) ModTime() time.Time {
+	return fi.modTime
+}
+
+func (fi cacheFileInfo) Size() int64 {
+	return fi.size
+}
+
+func TestFileCache(t *testing.T) {
+	now := time.Now()
+	fileInfo := cacheFileInfo{FakeFileInfo: &FakeFileInfo{}, modTime: now, size: 10}
+
+	t.Run("should return cached content for unchanged files", func(t *testing.T) {
+		cache := newFileCache()
+		cache.set("a.json", fileInfo, simplejson.NewFromAny(map[string]interface{}{"title": "A"}), "sum")
+
+		data, checkSum, ok := cache.get("a.json", fileInfo)
+		require.True(t, ok)
+		require.Equal(t, "sum", checkSum)
+		require.Equal(t, "A", data.Get("title").MustString())
+	})
+
+	t.Run("should miss when modification time or size changed", func(t *testing.T) {
+		cache := newFileCache()
+		cache.set("a.json", fileInfo, simplejson.New(), "sum")
+
+		_, _, ok := cache.get("a.json", cacheFileInfo{FakeFileInfo: &FakeFileInfo{}, modTime: now.Add(time.Second), size: 10})
+		require.False(t, ok)
+
+		_, _, ok = cache.get("a.json", cacheFileInfo{FakeFileInfo: &FakeFileInfo{}, modTime: now, size: 11})
+		require.False(t, ok)
+	})
+
+	t.Run("should not share content with callers", func(t *testing.T) {
+		cache := newFileCache()
+		cache.set("a.json", fileInfo, simplejson.NewFromAny(map[string]interface{}{"title": "A"}), "sum")
+
+		data, _, _ := cache.get("a.json", fileInfo)
+		data.Set("title", "B")
+
+		data, _, _ = cache.get("a.json", fileInfo)
+		require.Equal(t, "A", data.Get("title").MustString())
+	})
+
+	t.Run("should prune files no longer on disk", func(t *testing.T) {
+		cache := newFileCache()
+		cache.set("a.json", fileInfo, simplejson.New(), "sum")
+		cache.set("b.json", fileInfo, simplejson.New(), "sum")
+
+		cache.prune(map[string]os.FileInfo{"b.json": fileInfo})
+
+		_, _, ok := cache.get("a.json", fileInfo)
+		require.False(t, ok)
+		_, _, ok = cache.get("b.json", fileInfo)
+		require.True(t, ok)
+	})
+}
@@ -0,0 +1,30 @@
+package dashboards
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// recordFileError persists the last error met provisioning the file of a provisioned dashboard on its provisioning
+// record, so that it's exposed in the dashboard meta rather than only logged. A nil err clears the recorded error.
+// Files which never provisioned a dashboard have no record to annotate and are only logged.
+func (fr *FileReader) recordFileError(ctx context.Context, provisionedData *models.DashboardProvisioning, err error) {
+	if provisionedData == nil || fr.isDatabaseAccessRestricted() {
+		return
+	}
+
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+	}
+	if lastError == provisionedData.LastError {
+		return
+	}
+
+	if err := fr.dashboardProvisioningService.SetProvisionedDashboardError(ctx, provisionedData.Id, lastError); err != nil {
+		fr.log.Error("failed to record provisioning error of dashboard", "file", provisionedData.ExternalId, "error", err)
+		return
+	}
+	provisionedData.LastError = lastError
+}
@@ -0,0 +1,84 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cpu.json")
+	writeFile := func(t *testing.T, content string) os.FileInfo {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		// the file cache is keyed by modification time, which may not change between quick writes
+		changed := time.Now().Add(time.Duration(len(content)) * time.Minute)
+		require.NoError(t, os.Chtimes(path, changed, changed))
+		fileInfo, err := os.Stat(path)
+		require.NoError(t, err)
+		return fileInfo
+	}
+
+	newReader := func(t *testing.T) (*FileReader, *dashboards.FakeDashboardProvisioning) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":        dir,
+			"skipInvalid": true,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		return reader, fakeService
+	}
+	save := func(reader *FileReader, fileInfo os.FileInfo, provisionedData *models.DashboardProvisioning) {
+		refs := map[string]*models.DashboardProvisioning{path: provisionedData}
+		_, err := reader.saveDashboard(context.Background(), path, 0, fileInfo, refs, newUsageTracker())
+		require.NoError(t, err)
+	}
+
+	t.Run("should record the error of files failing to provision", func(t *testing.T) {
+		reader, fakeService := newReader(t)
+		fileInfo := writeFile(t, `{"title": "CPU"`)
+		provisionedData := &models.DashboardProvisioning{Id: 3, DashboardId: 5, Name: configName, ExternalId: path, CheckSum: "old"}
+		fakeService.On("SetProvisionedDashboardError", mock.Anything, int64(3), mock.MatchedBy(func(lastError string) bool {
+			return lastError != ""
+		})).Return(nil).Once()
+
+		save(reader, fileInfo, provisionedData)
+		require.NotEmpty(t, provisionedData.LastError)
+
+		// the same error isn't recorded again
+		save(reader, fileInfo, provisionedData)
+	})
+
+	t.Run("should clear the error once the file is saved", func(t *testing.T) {
+		reader, fakeService := newReader(t)
+		fileInfo := writeFile(t, `{"title": "CPU"}`)
+		provisionedData := &models.DashboardProvisioning{Id: 3, DashboardId: 5, Name: configName, ExternalId: path, CheckSum: "old", LastError: "invalid"}
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 5}, nil).Once()
+
+		save(reader, fileInfo, provisionedData)
+		require.Empty(t, provisionedData.LastError)
+	})
+
+	t.Run("should clear the error of files reverted to their provisioned content", func(t *testing.T) {
+		reader, fakeService := newReader(t)
+		fileInfo := writeFile(t, `{"title": "Memory"}`)
+		jsonFile, err := reader.readDashboardFromFile(path, fileInfo, 0)
+		require.NoError(t, err)
+		provisionedData := &models.DashboardProvisioning{Id: 3, DashboardId: 5, Name: configName, ExternalId: path, CheckSum: jsonFile.checkSum, LastError: "invalid"}
+		fakeService.On("SetProvisionedDashboardError", mock.Anything, int64(3), "").Return(nil).Once()
+
+		save(reader, fileInfo, provisionedData)
+		require.Empty(t, provisionedData.LastError)
+	})
+}
@@ -0,0 +1,134 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// fileIndexVersion is the version of the layout of the index file. Index files of other versions are ignored.
+const fileIndexVersion = 1
+
+// fileIndex is persisted to IndexPath at the end of every run, so that after a restart the files which didn't
+// change since then don't have to be read and hashed again to find out that their dashboard is up to date.
+type fileIndex struct {
+	Version int `json:"version"`
+	// Fingerprint identifies everything besides the content of a file which goes into its checksum. The entries of
+	// an index with another fingerprint can't be trusted.
+	Fingerprint string                    `json:"fingerprint"`
+	Files       map[string]fileIndexEntry `json:"files"`
+}
+
+type fileIndexEntry struct {
+	Size     int64  `json:"size"`
+	ModTime  int64  `json:"modTime"`
+	CheckSum string `json:"checkSum"`
+	UID      string `json:"uid,omitempty"`
+	Title    string `json:"title"`
+	FolderID int64  `json:"folderId"`
+}
+
+// loadIndex reads the index persisted by a previous run. A missing or unreadable index is treated as empty, so that
+// every file is read again.
+func (fr *FileReader) loadIndex() *fileIndex {
+	index := &fileIndex{Version: fileIndexVersion, Files: map[string]fileIndexEntry{}}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `IndexPath` comes from the provisioning configuration file.
+	content, err := os.ReadFile(fr.IndexPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return index
+	}
+	if err != nil {
+		fr.log.Warn("failed to read provisioning index, reading every file again", "path", fr.IndexPath, "error", err)
+		return index
+	}
+
+	var stored fileIndex
+	if err := json.Unmarshal(content, &stored); err != nil || stored.Version != fileIndexVersion || stored.Files == nil {
+		fr.log.Warn("ignoring invalid provisioning index, reading every file again", "path", fr.IndexPath, "error", err)
+		return index
+	}
+	return &stored
+}
+
+// indexFingerprint covers the auxiliary files, the tags, the transformers and the Grafana version, any of which
+// can change the checksum of a dashboard whose file didn't change.
+func (fr *FileReader) indexFingerprint() (string, error) {
+	return util.Md5SumString(strings.Join([]string{
+		fr.auxiliaryChecksum,
+		strings.Join(fr.Tags, ","),
+		strconv.Itoa(len(fr.Transformers)),
+		fr.grafanaVersion,
+	}, "\n"))
+}
+
+// startIndexedRun prepares the index for a run, dropping the entries of the previous one if anything besides the
+// files changed since.
+func (fr *FileReader) startIndexedRun() error {
+	if fr.index == nil {
+		fr.index = fr.loadIndex()
+	}
+
+	fingerprint, err := fr.indexFingerprint()
+	if err != nil {
+		return err
+	}
+	if fr.index.Fingerprint != fingerprint {
+		fr.index = &fileIndex{Version: fileIndexVersion, Fingerprint: fingerprint, Files: map[string]fileIndexEntry{}}
+	}
+	fr.nextIndex = map[string]fileIndexEntry{}
+	return nil
+}
+
+// finishIndexedRun persists the entries recorded during the run, which replace the previous ones.
+func (fr *FileReader) finishIndexedRun() error {
+	fr.index.Files, fr.nextIndex = fr.nextIndex, nil
+
+	content, err := json.Marshal(fr.index)
+	if err != nil {
+		return err
+	}
+	tmp := fr.IndexPath + ".tmp"
+	if err := os.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fr.IndexPath)
+}
+
+// indexedEntry returns the index entry of a file which didn't change since it was indexed, provided the checksum of
+// the entry is still the one of the provisioned dashboard, so that a dashboard changed by another instance or
+// restored from a backup is checked again.
+func (fr *FileReader) indexedEntry(path string, fileInfo os.FileInfo, provisionedData *models.DashboardProvisioning) (fileIndexEntry, bool) {
+	if fr.index == nil || fr.nextIndex == nil || fr.forcing || provisionedData == nil {
+		return fileIndexEntry{}, false
+	}
+
+	entry, ok := fr.index.Files[path]
+	if !ok || entry.Size != fileInfo.Size() || entry.ModTime != fileInfo.ModTime().UnixNano() ||
+		entry.CheckSum != provisionedData.CheckSum {
+		return fileIndexEntry{}, false
+	}
+	return entry, true
+}
+
+// indexFile records a file whose dashboard is up to date for the index written at the end of the run. Files
+// including other files are left out, as their checksum also depends on the included files.
+func (fr *FileReader) indexFile(path string, fileInfo os.FileInfo, jsonFile *dashboardJSONFile, metadata provisioningMetadata) {
+	if fr.nextIndex == nil || jsonFile.includes {
+		return
+	}
+	fr.nextIndex[path] = fileIndexEntry{
+		Size:     fileInfo.Size(),
+		ModTime:  fileInfo.ModTime().UnixNano(),
+		CheckSum: jsonFile.checkSum,
+		UID:      metadata.uid,
+		Title:    metadata.identity.title,
+		FolderID: metadata.identity.folderID,
+	}
+}
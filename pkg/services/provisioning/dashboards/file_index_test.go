@@ -0,0 +1,84 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestFileIndex(t *testing.T) {
+	dir := t.TempDir()
+	content, err := os.ReadFile(filepath.Join(oneDashboard, "dashboard1.json"))
+	require.NoError(t, err)
+	file := filepath.Join(dir, "dashboard1.json")
+	require.NoError(t, os.WriteFile(file, content, 0600))
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+	newReader := func(t *testing.T, options map[string]interface{}) *FileReader {
+		options["path"] = dir
+		options["indexPath"] = indexPath
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		return reader
+	}
+
+	reader := newReader(t, map[string]interface{}{})
+	fileInfo, err := os.Stat(file)
+	require.NoError(t, err)
+	jsonFile, err := reader.readDashboardFromFile(file, fileInfo, 0)
+	require.NoError(t, err)
+	provisionedData := &models.DashboardProvisioning{DashboardId: 1, Name: configName, ExternalId: file, CheckSum: jsonFile.checkSum}
+
+	fakeService.On("GetProvisionedDashboardData", configName).Return([]*models.DashboardProvisioning{provisionedData}, nil).Once()
+	require.NoError(t, reader.walkDisk(context.Background()))
+	require.FileExists(t, indexPath)
+
+	t.Run("should trust unchanged files after a restart", func(t *testing.T) {
+		restarted := newReader(t, map[string]interface{}{})
+		require.NoError(t, restarted.startIndexedRun())
+
+		entry, ok := restarted.indexedEntry(file, fileInfo, provisionedData)
+		require.True(t, ok)
+		require.Equal(t, jsonFile.dashboard.Dashboard.Uid, entry.UID)
+	})
+
+	t.Run("should not trust entries not matching the provisioned checksum", func(t *testing.T) {
+		restarted := newReader(t, map[string]interface{}{})
+		require.NoError(t, restarted.startIndexedRun())
+
+		_, ok := restarted.indexedEntry(file, fileInfo, &models.DashboardProvisioning{CheckSum: "other"})
+		require.False(t, ok)
+	})
+
+	t.Run("should not trust entries of changed files", func(t *testing.T) {
+		restarted := newReader(t, map[string]interface{}{})
+		require.NoError(t, restarted.startIndexedRun())
+
+		later := fileInfo.ModTime().Add(time.Minute)
+		require.NoError(t, os.Chtimes(file, later, later))
+		changedInfo, err := os.Stat(file)
+		require.NoError(t, err)
+
+		_, ok := restarted.indexedEntry(file, changedInfo, provisionedData)
+		require.False(t, ok)
+	})
+
+	t.Run("should drop the index when the checksums depend on other settings", func(t *testing.T) {
+		restarted := newReader(t, map[string]interface{}{"tags": []interface{}{"generated"}})
+		require.NoError(t, restarted.startIndexedRun())
+
+		_, ok := restarted.indexedEntry(file, fileInfo, provisionedData)
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,51 @@
+package dashboards
+
+import (
+	"os"
+)
+
+// DashboardFile describes a dashboard file found on disk as it would be provisioned.
+type DashboardFile struct {
+	CheckSum string `json:"checkSum"`
+	UID      string `json:"uid,omitempty"`
+	Deleted  bool   `json:"deleted,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// ListDashboardFiles returns the dashboard files found on disk, keyed by the path they are provisioned from,
+// without writing anything. Comparing their checksums to the provisioning data of the provider tells which
+// dashboards were added, changed or removed since the last run. Files which can't be read are logged and left out.
+func (fr *FileReader) ListDashboardFiles() (map[string]DashboardFile, error) {
+	resolvedPath := fr.resolvedPath()
+	if _, err := os.Stat(resolvedPath); err != nil {
+		return nil, err
+	}
+
+	filesFoundOnDisk := map[string]os.FileInfo{}
+	if err := fr.walkFiles(resolvedPath, filesFoundOnDisk); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]DashboardFile, len(filesFoundOnDisk))
+	for path, fileInfo := range filesFoundOnDisk {
+		resolvedFileInfo, err := fr.resolveSymlink(fileInfo, path)
+		if err != nil {
+			fr.log.Warn("failed to resolve dashboard file", "file", path, "error", err)
+			continue
+		}
+
+		jsonFile, err := fr.readDashboardFromFile(path, resolvedFileInfo, 0)
+		if err != nil {
+			fr.log.Warn("failed to load dashboard from file", "file", path, "error", err)
+			continue
+		}
+
+		file := DashboardFile{CheckSum: jsonFile.checkSum, Deleted: jsonFile.deleted, Disabled: jsonFile.disabled}
+		if jsonFile.dashboard != nil {
+			file.UID = jsonFile.dashboard.Dashboard.Uid
+		}
+		files[path] = file
+	}
+
+	return files, nil
+}
@@ -0,0 +1,36 @@
+package dashboards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestListDashboardFiles(t *testing.T) {
+	dir := t.TempDir()
+	raw := []byte(`{"uid": "cpu", "title": "CPU"}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.json"), raw, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "old.json"), []byte(`{"__deleted": true}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{`), 0600))
+	resolvedDir, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": dir}}
+	// no provisioning service is needed since nothing is written
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.NoError(t, err)
+
+	files, err := reader.ListDashboardFiles()
+	require.NoError(t, err)
+
+	checkSum, err := util.Md5SumString(string(raw))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, DashboardFile{CheckSum: checkSum, UID: "cpu"}, files[filepath.Join(resolvedDir, "cpu.json")])
+	require.True(t, files[filepath.Join(resolvedDir, "old.json")].Deleted)
+}
@@ -1,15 +1,25 @@
 package dashboards
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
+	"unicode"
+
+	"github.com/benbjohnson/clock"
+	"golang.org/x/time/rate"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -17,14 +27,60 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
 var (
 	// ErrFolderNameMissing is returned when folder name is missing.
 	ErrFolderNameMissing = errors.New("folder name missing")
+	// ErrDuplicateTitle is returned when several dashboards of a provider share the same title in a folder.
+	ErrDuplicateTitle = errors.New("dashboard title is not unique in folder")
+	// ErrFileTooLarge is returned when a dashboard file exceeds the configured maximum size.
+	ErrFileTooLarge = errors.New("dashboard file is too large")
+	// ErrTransformFailed is returned when a dashboard transformer fails, in which case the file is skipped.
+	ErrTransformFailed = errors.New("dashboard transformer failed")
+	// ErrInvalidDashboard is returned when the content of a dashboard file doesn't have the shape of a dashboard.
+	ErrInvalidDashboard = errors.New("invalid dashboard")
+	// ErrFolderConflict is returned when the name of a folder to provision resolves to an existing dashboard.
+	ErrFolderConflict = errors.New("got invalid response. expected folder, found dashboard")
+	// ErrTooManyFiles is returned when the path of a provider holds more dashboard files than maxFiles, in which
+	// case the run is aborted.
+	ErrTooManyFiles = errors.New("too many dashboard files")
+
+	errShuttingDown = errors.New("provisioning run stopped, Grafana is shutting down")
+)
+
+// DashboardTransformer modifies the parsed content of a dashboard file before it is provisioned. The transformers
+// of a FileReader are applied in order.
+type DashboardTransformer func(*simplejson.Json) error
+
+// defaultMaxFileSizeBytes is the maximum size of a dashboard file unless configured otherwise.
+const defaultMaxFileSizeBytes = 10 * 1024 * 1024
+
+// defaultSaveTimeout is how long saving a single dashboard may take unless configured otherwise.
+const defaultSaveTimeout = 30 * time.Second
+
+const (
+	onDuplicateTitleWarn   = "warn"
+	onDuplicateTitleError  = "error"
+	onDuplicateTitleSuffix = "suffix"
+)
+
+const (
+	fileOrderAsc  = "asc"
+	fileOrderDesc = "desc"
+)
+
+const (
+	onFolderConflictError  = "error"
+	onFolderConflictSkip   = "skip"
+	onFolderConflictSuffix = "suffix"
 )
 
+// maxFolderConflictSuffix bounds the suffixes tried for a folder whose name resolves to an existing dashboard.
+const maxFolderConflictSuffix = 100
+
 // FileReader is responsible for reading dashboards from disk and
 // insert/update dashboards to the Grafana database using
 // `dashboards.DashboardProvisioningService`.
@@ -35,16 +91,163 @@ type FileReader struct {
 	dashboardProvisioningService dashboards.DashboardProvisioningService
 	dashboardStore               utils.DashboardStore
 	FoldersFromFilesStructure    bool
-
+	NestedFolders                bool
+	IgnoreOlderThan              time.Duration
+	Manifest                     string
+	OnDuplicateTitle             string
+	MaxFileSizeBytes             int64
+	FollowSymlinkedDirs          bool
+	OrgIDFromPath                bool
+	OrgIDMapping                 map[string]int64
+	StartupDelay                 time.Duration
+	StartupJitter                time.Duration
+	PruneEmptyFolders            bool
+	FileExtensions               []string
+	Transformers                 []DashboardTransformer
+	Secrets                      SecretsDecrypter
+	SkipInvalid                  bool
+	ReadOnlyAdditive             bool
+	PollJitterFraction           float64
+	MergeStrategy                string
+	ResolveSymlinks              bool
+	LockFile                     bool
+	LockTTL                      time.Duration
+	Archive                      string
+	WritesPerSecond              float64
+	SaveTimeout                  time.Duration
+	FolderRules                  []folderRule
+	SummaryLogLevel              string
+	ManagePermissions            bool
+	PathPattern                  string
+	UnhealthyAfterPolls          int
+	FileOrder                    string
+	OnFolderConflict             string
+	CanaryPattern                string
+	RootFolder                   string
+	RequireChecksumSidecar       bool
+	AuxiliaryFiles               []string
+	ReconcileByUID               bool
+	NormalizeInput               bool
+	MaxFiles                     int64
+	WarnFiles                    int64
+	EmitDiffs                    bool
+	AllowedKinds                 []string
+	DeniedKinds                  []string
+	ForceReprovision             bool
+	PreventDowngrade             bool
+	QuarantineAfter              int64
+	Clock                        clock.Clock
+	ReportPath                   string
+	IntervalRules                []intervalRule
+	SkipHiddenDirs               bool
+	IncludeDirs                  []string
+	ExcludeDirs                  []string
+	CaseInsensitiveExtensions    bool
+	ShutdownGracePeriod          time.Duration
+	Tags                         []string
+	SkipOnUnhealthyDB            bool
+	VerifyOnly                   bool
+	Files                        []string
+	IgnoreMissingListed          bool
+	IndexPath                    string
+	SoftDelete                   bool
+	TrashFolder                  string
+	TrashRetention               time.Duration
+	FolderNameTemplate           *template.Template
+	AllowImmutableDeletion       bool
+	CreateOnly                   bool
+	MaxSymlinkDepth              int
+	CanonicalChecksum            bool
+	StreamParse                  bool
+	PeekKind                     bool
+	StrictJSON                   bool
+	RunTimeout                   time.Duration
+	ProtectReferenced            bool
+	AllowReferencedDeletion      []string
+	UIDPrefix                    string
+	StoreDecryptedSecrets        bool
+	MaxConcurrentFolders         int
+
+	// runMux ensures a single run at a time, while mux guards the state shared with other goroutines.
+	runMux                  sync.Mutex
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
+	fileCache               *fileCache
+	status                  ProvisionerStatus
 	dbWriteAccessRestricted bool
+	// createdFolders holds the folders created by this provisioner, mapped to their organization. It is only
+	// accessed during runs.
+	createdFolders map[int64]int64
+	// folderIDs caches the ids of the folders looked up during the current run.
+	folderIDs map[folderKey]int64
+	// instanceID identifies this Grafana instance in the lock file.
+	instanceID string
+	// archiveModTime and archiveSize identify the last extracted version of the archive.
+	archiveModTime time.Time
+	archiveSize    int64
+	// writeLimiter throttles the writes of dashboards to the database when WritesPerSecond is set.
+	writeLimiter *rate.Limiter
+	// stats counts what the current run did. It is only accessed during runs.
+	stats runStats
+	// pathUnreadable is set when the current run couldn't read the path. It is only accessed during runs.
+	pathUnreadable bool
+	// canaryPromoted is set once the canary has been promoted, after which the whole path is provisioned.
+	canaryPromoted bool
+	// auxiliaryChecksum is the checksum of the auxiliary files for the current run. It is only accessed during runs.
+	auxiliaryChecksum string
+	// forceNextRun makes the next run save every dashboard regardless of its checksum. It's guarded by mux.
+	forceNextRun bool
+	// synced is set once the current run got past the checks which may skip it, so that skipped runs neither count
+	// as a generation nor use up a forced run. It is only accessed during runs.
+	synced bool
+	// forcing is set when the current run saves every dashboard. It is only accessed during runs.
+	forcing bool
+	// forcedConfigChecksum is the checksum of the config file to record once its forceReprovision option was
+	// applied. It is only accessed during runs.
+	forcedConfigChecksum string
+	// quarantine tracks the files failing to load when QuarantineAfter is set.
+	quarantine *quarantine
+	// dbUnhealthy is set while the health probe of SkipOnUnhealthyDB fails. It is only accessed during runs.
+	dbUnhealthy bool
+	// runSlots is shared by the providers of a provisioner to bound how many of them run at once.
+	runSlots runSlots
+	// folderSlots bounds how many folders of the provider are looked up or created in the database at once.
+	folderSlots runSlots
+	// grafanaVersion is the running version, checked against the version range of the dashboards.
+	grafanaVersion string
+	// index holds the files indexed by the last run when IndexPath is set, while nextIndex collects those of the
+	// current run. They are only accessed during runs.
+	index     *fileIndex
+	nextIndex map[string]fileIndexEntry
+	// manifestChecksums holds the checksums of the files listed in the manifest, by path. It is only accessed during
+	// runs.
+	manifestChecksums map[string]manifestChecksum
+	// generationChanged is closed and replaced whenever the generation of the status increases. It's guarded by mux.
+	generationChanged chan struct{}
+	// runAbandoned is set while a run which exceeded RunTimeout is still going on. It's guarded by mux.
+	runAbandoned bool
+}
+
+type folderKey struct {
+	orgID int64
+	// parentID is the folder holding the folder, folders with the same name being distinct in different parents.
+	parentID int64
+	slug     string
+	// uid is set instead of slug for folders looked up by their UID.
+	uid string
 }
 
 // NewDashboardFileReader returns a new filereader based on `config`
 func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
 	var path string
 	path, ok := cfg.Options["path"].(string)
+	archive, _ := cfg.Options["archive"].(string)
+	if archive != "" {
+		if ok {
+			return nil, fmt.Errorf("'path' and 'archive' options can't be used together")
+		}
+		path, ok = archiveExtractionPath(cfg.dataPath, cfg.Name, archive), true
+	}
 	if !ok {
 		path, ok = cfg.Options["folder"].(string)
 		if !ok {
@@ -54,9 +257,382 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		log.Warn("[Deprecated] The folder property is deprecated. Please use path instead.")
 	}
 
+	var pathPattern string
+	if isGlobPattern(path) {
+		path, pathPattern = splitGlobPattern(path)
+		if err := validateGlobPattern(pathPattern); err != nil {
+			return nil, fmt.Errorf("invalid 'path' pattern %q: %w", pathPattern, err)
+		}
+	}
+
 	foldersFromFilesStructure, _ := cfg.Options["foldersFromFilesStructure"].(bool)
-	if foldersFromFilesStructure && cfg.Folder != "" && cfg.FolderUID != "" {
-		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
+
+	nestedFolders, _ := cfg.Options["nestedFolders"].(bool)
+
+	var ignoreOlderThan time.Duration
+	if raw, ok := cfg.Options["ignoreOlderThan"].(string); ok && raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'ignoreOlderThan' option: %w", err)
+		}
+		ignoreOlderThan = d
+	}
+
+	manifest, _ := cfg.Options["manifest"].(string)
+
+	onDuplicateTitle, _ := cfg.Options["onDuplicateTitle"].(string)
+	switch onDuplicateTitle {
+	case "":
+		onDuplicateTitle = onDuplicateTitleWarn
+	case onDuplicateTitleWarn, onDuplicateTitleError, onDuplicateTitleSuffix:
+	default:
+		return nil, fmt.Errorf("invalid 'onDuplicateTitle' option %q, expected one of %q, %q or %q", onDuplicateTitle,
+			onDuplicateTitleWarn, onDuplicateTitleError, onDuplicateTitleSuffix)
+	}
+
+	followSymlinkedDirs, _ := cfg.Options["followSymlinkedDirs"].(bool)
+
+	maxFileSizeBytes, ok, err := int64Option(cfg.Options, "maxFileSizeBytes")
+	if err != nil {
+		return nil, err
+	}
+	if !ok || maxFileSizeBytes <= 0 {
+		maxFileSizeBytes = defaultMaxFileSizeBytes
+	}
+
+	orgIDFromPath, _ := cfg.Options["orgIdFromPath"].(bool)
+	if orgIDFromPath && !foldersFromFilesStructure {
+		return nil, fmt.Errorf("'orgIdFromPath' requires the 'foldersFromFilesStructure' option")
+	}
+
+	orgIDMapping, err := orgIDMappingOption(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	startupDelaySeconds, _, err := int64Option(cfg.Options, "startupDelaySeconds")
+	if err != nil {
+		return nil, err
+	}
+	startupJitterSeconds, _, err := int64Option(cfg.Options, "startupJitterSeconds")
+	if err != nil {
+		return nil, err
+	}
+	if startupDelaySeconds < 0 || startupJitterSeconds < 0 {
+		return nil, fmt.Errorf("'startupDelaySeconds' and 'startupJitterSeconds' options can't be negative")
+	}
+
+	pruneEmptyFolders, _ := cfg.Options["pruneEmptyFolders"].(bool)
+
+	pollJitterFraction, _, err := float64Option(cfg.Options, "pollJitterFraction")
+	if err != nil {
+		return nil, err
+	}
+	if pollJitterFraction < 0 || pollJitterFraction >= 1 {
+		return nil, fmt.Errorf("'pollJitterFraction' option must be between 0 and 1, got %v", pollJitterFraction)
+	}
+
+	resolveSymlinks := true
+	if v, ok := cfg.Options["resolveSymlinks"].(bool); ok {
+		resolveSymlinks = v
+	}
+	if !resolveSymlinks && followSymlinkedDirs {
+		return nil, fmt.Errorf("'followSymlinkedDirs' can't be used when 'resolveSymlinks' is disabled")
+	}
+	maxSymlinkDepth, set, err := int64Option(cfg.Options, "maxSymlinkDepth")
+	if err != nil {
+		return nil, err
+	}
+	if !set {
+		maxSymlinkDepth = defaultMaxSymlinkDepth
+	}
+	if maxSymlinkDepth < 1 {
+		return nil, fmt.Errorf("'maxSymlinkDepth' option must be at least 1")
+	}
+
+	lockFile, _ := cfg.Options["lockFile"].(bool)
+	lockTTLSeconds, _, err := int64Option(cfg.Options, "lockTTLSeconds")
+	if err != nil {
+		return nil, err
+	}
+	if lockTTLSeconds < 0 {
+		return nil, fmt.Errorf("'lockTTLSeconds' option can't be negative")
+	}
+	lockTTL := time.Duration(lockTTLSeconds) * time.Second
+	if lockTTL == 0 {
+		// leave enough time for a couple of runs before other instances may take over
+		lockTTL = 3 * time.Duration(cfg.UpdateIntervalSeconds) * time.Second
+	}
+	if lockTTL == 0 {
+		lockTTL = defaultLockTTL
+	}
+
+	writesPerSecond, _, err := float64Option(cfg.Options, "writesPerSecond")
+	if err != nil {
+		return nil, err
+	}
+	if writesPerSecond < 0 {
+		return nil, fmt.Errorf("'writesPerSecond' option can't be negative")
+	}
+	var writeLimiter *rate.Limiter
+	if writesPerSecond > 0 {
+		writeLimiter = rate.NewLimiter(rate.Limit(writesPerSecond), 1)
+	}
+
+	saveTimeoutSeconds, _, err := int64Option(cfg.Options, "saveTimeoutSeconds")
+	if err != nil {
+		return nil, err
+	}
+	if saveTimeoutSeconds < 0 {
+		return nil, fmt.Errorf("'saveTimeoutSeconds' option can't be negative")
+	}
+	saveTimeout := time.Duration(saveTimeoutSeconds) * time.Second
+	if saveTimeout == 0 {
+		saveTimeout = defaultSaveTimeout
+	}
+
+	shutdownGracePeriodSeconds, set, err := int64Option(cfg.Options, "shutdownGracePeriodSeconds")
+	if err != nil {
+		return nil, err
+	}
+	if shutdownGracePeriodSeconds < 0 {
+		return nil, fmt.Errorf("'shutdownGracePeriodSeconds' option can't be negative")
+	}
+	shutdownGracePeriod := time.Duration(shutdownGracePeriodSeconds) * time.Second
+	if !set {
+		shutdownGracePeriod = defaultShutdownGracePeriod
+	}
+
+	runTimeoutSeconds, _, err := int64Option(cfg.Options, "runTimeoutSeconds")
+	if err != nil {
+		return nil, err
+	}
+	if runTimeoutSeconds < 0 {
+		return nil, fmt.Errorf("'runTimeoutSeconds' option can't be negative")
+	}
+	runTimeout := time.Duration(runTimeoutSeconds) * time.Second
+
+	maxConcurrentFolders, _, err := int64Option(cfg.Options, "maxConcurrentFolders")
+	if err != nil {
+		return nil, err
+	}
+	if maxConcurrentFolders < 0 {
+		return nil, fmt.Errorf("'maxConcurrentFolders' option can't be negative")
+	}
+
+	folderRules, err := folderRulesOption(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryLogLevel, _ := cfg.Options["summaryLogLevel"].(string)
+	switch summaryLogLevel {
+	case "":
+		summaryLogLevel = summaryLogLevelInfo
+	case summaryLogLevelInfo, summaryLogLevelDebug:
+	default:
+		return nil, fmt.Errorf("invalid 'summaryLogLevel' option %q, expected %q or %q", summaryLogLevel,
+			summaryLogLevelInfo, summaryLogLevelDebug)
+	}
+
+	mergeStrategy, _ := cfg.Options["mergeStrategy"].(string)
+	switch mergeStrategy {
+	case "":
+		mergeStrategy = mergeStrategyReplace
+	case mergeStrategyReplace, mergeStrategyMerge:
+	default:
+		return nil, fmt.Errorf("invalid 'mergeStrategy' option %q, expected %q or %q", mergeStrategy,
+			mergeStrategyReplace, mergeStrategyMerge)
+	}
+
+	readOnlyAdditive, _ := cfg.Options["readOnlyAdditive"].(bool)
+	if readOnlyAdditive && pruneEmptyFolders {
+		return nil, fmt.Errorf("'pruneEmptyFolders' can't be used together with 'readOnlyAdditive'")
+	}
+
+	managePermissions, _ := cfg.Options["managePermissions"].(bool)
+	requireChecksumSidecar, _ := cfg.Options["requireChecksumSidecar"].(bool)
+
+	onFolderConflict, _ := cfg.Options["onFolderConflict"].(string)
+	switch onFolderConflict {
+	case "":
+		onFolderConflict = onFolderConflictError
+	case onFolderConflictError, onFolderConflictSkip, onFolderConflictSuffix:
+	default:
+		return nil, fmt.Errorf("invalid 'onFolderConflict' option %q, expected one of %q, %q or %q", onFolderConflict,
+			onFolderConflictError, onFolderConflictSkip, onFolderConflictSuffix)
+	}
+
+	fileOrder, _ := cfg.Options["fileOrder"].(string)
+	switch fileOrder {
+	case "":
+		fileOrder = fileOrderAsc
+	case fileOrderAsc, fileOrderDesc:
+	default:
+		return nil, fmt.Errorf("invalid 'fileOrder' option %q, expected %q or %q", fileOrder, fileOrderAsc, fileOrderDesc)
+	}
+
+	rootFolder, _ := cfg.Options["rootFolder"].(string)
+
+	canaryPattern, _ := cfg.Options["canaryPattern"].(string)
+	if canaryPattern != "" {
+		canaryPattern = filepath.ToSlash(canaryPattern)
+		if err := validateGlobPattern(canaryPattern); err != nil {
+			return nil, fmt.Errorf("invalid 'canaryPattern' option %q: %w", canaryPattern, err)
+		}
+	}
+
+	unhealthyAfterPolls, ok, err := int64Option(cfg.Options, "unhealthyAfterPolls")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		unhealthyAfterPolls = defaultUnhealthyAfterPolls
+	}
+	if unhealthyAfterPolls < 0 {
+		return nil, fmt.Errorf("'unhealthyAfterPolls' option can't be negative")
+	}
+
+	skipInvalid := true
+	if v, ok := cfg.Options["skipInvalid"].(bool); ok {
+		skipInvalid = v
+	}
+
+	fileExtensions, ok, err := stringListOption(cfg.Options, "fileExtensions")
+	if err != nil {
+		return nil, err
+	}
+	if !ok || len(fileExtensions) == 0 {
+		fileExtensions = defaultFileExtensions
+	}
+	caseInsensitiveExtensions, _ := cfg.Options["caseInsensitiveExtensions"].(bool)
+
+	skipHiddenDirs := true
+	if v, ok := cfg.Options["skipHiddenDirs"].(bool); ok {
+		skipHiddenDirs = v
+	}
+	includeDirs, err := dirPatternsOption(cfg.Options, "includeDirs")
+	if err != nil {
+		return nil, err
+	}
+	excludeDirs, err := dirPatternsOption(cfg.Options, "excludeDirs")
+	if err != nil {
+		return nil, err
+	}
+
+	auxiliaryFiles, _, err := stringListOption(cfg.Options, "auxiliaryFiles")
+	if err != nil {
+		return nil, err
+	}
+
+	reconcileByUID, _ := cfg.Options["reconcileByUid"].(bool)
+	normalizeInput, _ := cfg.Options["normalizeInput"].(bool)
+	canonicalChecksum, _ := cfg.Options["canonicalChecksum"].(bool)
+	streamParse, _ := cfg.Options["streamParse"].(bool)
+	strictJSON, _ := cfg.Options["strictJson"].(bool)
+
+	maxFiles, _, err := int64Option(cfg.Options, "maxFiles")
+	if err != nil {
+		return nil, err
+	}
+	warnFiles, _, err := int64Option(cfg.Options, "warnFiles")
+	if err != nil {
+		return nil, err
+	}
+	if maxFiles < 0 || warnFiles < 0 {
+		return nil, fmt.Errorf("'maxFiles' and 'warnFiles' options can't be negative")
+	}
+
+	emitDiffs, _ := cfg.Options["emitDiffs"].(bool)
+
+	allowedKinds, err := kindsOption(cfg.Options, "allowedKinds")
+	if err != nil {
+		return nil, err
+	}
+	deniedKinds, err := kindsOption(cfg.Options, "deniedKinds")
+	if err != nil {
+		return nil, err
+	}
+	// peeking is only worth it when some kinds are filtered out
+	peekKind, _ := cfg.Options["peekKind"].(bool)
+	peekKind = peekKind && (len(allowedKinds) > 0 || len(deniedKinds) > 0)
+
+	forceReprovision, _ := cfg.Options["forceReprovision"].(bool)
+	forceFirstRun, forcedConfigChecksum := forceReprovisionOption(cfg, log)
+	preventDowngrade, _ := cfg.Options["preventDowngrade"].(bool)
+
+	quarantineAfter, _, err := int64Option(cfg.Options, "quarantineAfter")
+	if err != nil {
+		return nil, err
+	}
+	if quarantineAfter < 0 {
+		return nil, fmt.Errorf("'quarantineAfter' option can't be negative")
+	}
+
+	reportPath, _ := cfg.Options["reportPath"].(string)
+
+	tags, err := tagsOption(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	skipOnUnhealthyDB, _ := cfg.Options["skipOnUnhealthyDb"].(bool)
+
+	intervalRules, err := intervalRulesOption(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	verifyOnly, _ := cfg.Options["verifyOnly"].(bool)
+	if verifyOnly && len(intervalRules) > 0 {
+		return nil, fmt.Errorf("'verifyOnly' and 'intervalRules' options can't be used together")
+	}
+
+	files, err := filesOption(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 && pathPattern != "" {
+		return nil, fmt.Errorf("'files' option can't be used with a glob pattern in 'path'")
+	}
+	ignoreMissingListed, _ := cfg.Options["ignoreMissingListed"].(bool)
+
+	indexPath, _ := cfg.Options["indexPath"].(string)
+
+	softDelete, _ := cfg.Options["softDelete"].(bool)
+	trashFolder, _ := cfg.Options["trashFolder"].(string)
+	if trashFolder == "" {
+		trashFolder = defaultTrashFolder
+	}
+	trashRetentionSeconds, _, err := int64Option(cfg.Options, "trashRetentionSeconds")
+	if err != nil {
+		return nil, err
+	}
+	if trashRetentionSeconds < 0 {
+		return nil, fmt.Errorf("'trashRetentionSeconds' option can't be negative")
+	}
+
+	folderNameTemplate, err := folderNameTemplateOption(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	allowImmutableDeletion, _ := cfg.Options["allowImmutableDeletion"].(bool)
+	protectReferenced, _ := cfg.Options["protectReferenced"].(bool)
+	allowReferencedDeletion, _, err := stringListOption(cfg.Options, "allowReferencedDeletion")
+	if err != nil {
+		return nil, err
+	}
+	uidPrefix, err := uidPrefixOption(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+	storeDecryptedSecrets, _ := cfg.Options["storeDecryptedSecrets"].(bool)
+	createOnly, _ := cfg.Options["createOnly"].(bool)
+	for _, rule := range intervalRules {
+		if cfg.UpdateIntervalSeconds > 0 && rule.interval >= time.Duration(cfg.UpdateIntervalSeconds)*time.Second {
+			return nil, fmt.Errorf("the interval of rule %q of 'intervalRules' must be shorter than 'updateIntervalSeconds'", rule.pattern)
+		}
 	}
 
 	return &FileReader{
@@ -66,46 +642,308 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		dashboardProvisioningService: service,
 		dashboardStore:               dashboardStore,
 		FoldersFromFilesStructure:    foldersFromFilesStructure,
+		NestedFolders:                nestedFolders,
+		IgnoreOlderThan:              ignoreOlderThan,
+		Manifest:                     manifest,
+		OnDuplicateTitle:             onDuplicateTitle,
+		MaxFileSizeBytes:             maxFileSizeBytes,
+		FollowSymlinkedDirs:          followSymlinkedDirs,
+		OrgIDFromPath:                orgIDFromPath,
+		OrgIDMapping:                 orgIDMapping,
+		StartupDelay:                 time.Duration(startupDelaySeconds) * time.Second,
+		StartupJitter:                time.Duration(startupJitterSeconds) * time.Second,
+		PruneEmptyFolders:            pruneEmptyFolders,
+		FileExtensions:               fileExtensions,
+		SkipInvalid:                  skipInvalid,
+		ReadOnlyAdditive:             readOnlyAdditive,
+		PollJitterFraction:           pollJitterFraction,
+		MergeStrategy:                mergeStrategy,
+		ResolveSymlinks:              resolveSymlinks,
+		LockFile:                     lockFile,
+		LockTTL:                      lockTTL,
+		Archive:                      archive,
+		WritesPerSecond:              writesPerSecond,
+		SaveTimeout:                  saveTimeout,
+		FolderRules:                  folderRules,
+		SummaryLogLevel:              summaryLogLevel,
+		ManagePermissions:            managePermissions,
+		PathPattern:                  pathPattern,
+		UnhealthyAfterPolls:          int(unhealthyAfterPolls),
+		FileOrder:                    fileOrder,
+		OnFolderConflict:             onFolderConflict,
+		CanaryPattern:                canaryPattern,
+		RootFolder:                   rootFolder,
+		RequireChecksumSidecar:       requireChecksumSidecar,
+		AuxiliaryFiles:               auxiliaryFiles,
+		ReconcileByUID:               reconcileByUID,
+		NormalizeInput:               normalizeInput,
+		MaxFiles:                     maxFiles,
+		WarnFiles:                    warnFiles,
+		EmitDiffs:                    emitDiffs,
+		AllowedKinds:                 allowedKinds,
+		DeniedKinds:                  deniedKinds,
+		ForceReprovision:             forceReprovision,
+		PreventDowngrade:             preventDowngrade,
+		QuarantineAfter:              quarantineAfter,
+		Clock:                        clock.New(),
+		ReportPath:                   reportPath,
+		IntervalRules:                intervalRules,
+		SkipHiddenDirs:               skipHiddenDirs,
+		IncludeDirs:                  includeDirs,
+		ExcludeDirs:                  excludeDirs,
+		CaseInsensitiveExtensions:    caseInsensitiveExtensions,
+		ShutdownGracePeriod:          shutdownGracePeriod,
+		Tags:                         tags,
+		SkipOnUnhealthyDB:            skipOnUnhealthyDB,
+		VerifyOnly:                   verifyOnly,
+		Files:                        files,
+		IgnoreMissingListed:          ignoreMissingListed,
+		IndexPath:                    indexPath,
+		SoftDelete:                   softDelete,
+		TrashFolder:                  trashFolder,
+		TrashRetention:               time.Duration(trashRetentionSeconds) * time.Second,
+		FolderNameTemplate:           folderNameTemplate,
+		AllowImmutableDeletion:       allowImmutableDeletion,
+		CreateOnly:                   createOnly,
+		MaxSymlinkDepth:              int(maxSymlinkDepth),
+		CanonicalChecksum:            canonicalChecksum,
+		StreamParse:                  streamParse,
+		PeekKind:                     peekKind,
+		StrictJSON:                   strictJSON,
+		RunTimeout:                   runTimeout,
+		ProtectReferenced:            protectReferenced,
+		AllowReferencedDeletion:      allowReferencedDeletion,
+		UIDPrefix:                    uidPrefix,
+		StoreDecryptedSecrets:        storeDecryptedSecrets,
+		MaxConcurrentFolders:         int(maxConcurrentFolders),
+		folderSlots:                  newRunSlots(int(maxConcurrentFolders)),
+		quarantine:                   newQuarantine(),
+		forceNextRun:                 forceFirstRun,
+		forcedConfigChecksum:         forcedConfigChecksum,
 		usageTracker:                 newUsageTracker(),
+		fileCache:                    newFileCache(),
+		createdFolders:               map[int64]int64{},
+		folderIDs:                    map[folderKey]int64{},
+		instanceID:                   newInstanceID(),
+		writeLimiter:                 writeLimiter,
+		grafanaVersion:               setting.BuildVersion,
+		status:                       ProvisionerStatus{Name: cfg.Name},
 	}, nil
 }
 
 // pollChanges periodically runs walkDisk based on interval specified in the config.
 func (fr *FileReader) pollChanges(ctx context.Context) {
-	ticker := time.NewTicker(time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds))
+	if fr.hasStartupDelay() {
+		// the first run was deferred by Provision so that providers don't all hit the database at once
+		timer := fr.Clock.Timer(fr.startupDelay())
+		select {
+		case <-timer.C:
+			if err := fr.walkDisk(ctx); err != nil {
+				fr.log.Error("failed to search for dashboards", "error", err)
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+
+	timer := fr.Clock.Timer(fr.pollInterval())
+	defer timer.Stop()
+
+	// the files matching interval rules are checked in between the regular runs
+	schedule := newIntervalSchedule(fr.IntervalRules, fr.Clock.Now())
+	var hotTimer *clock.Timer
+	var hotC <-chan time.Time
+	if len(fr.IntervalRules) > 0 {
+		hotTimer = fr.Clock.Timer(schedule.tick)
+		defer hotTimer.Stop()
+		hotC = hotTimer.C
+	}
+
 	for {
 		select {
-		case <-ticker.C:
+		case <-timer.C:
 			if err := fr.walkDisk(ctx); err != nil {
 				fr.log.Error("failed to search for dashboards", "error", err)
 			}
+			schedule.checkedAll(fr.Clock.Now())
+			timer.Reset(fr.pollInterval())
+		case <-hotC:
+			if due := schedule.due(fr.Clock.Now()); len(due) > 0 {
+				if err := fr.walkHotFiles(ctx, due); err != nil {
+					fr.log.Error("failed to search for frequently polled dashboards", "error", err)
+				}
+			}
+			hotTimer.Reset(schedule.tick)
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// pollInterval returns how long to wait for the next run. The jitter is drawn again for every run, so that
+// providers sharing the same update interval don't end up polling in lockstep.
+func (fr *FileReader) pollInterval() time.Duration {
+	interval := time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds)
+	if fr.PollJitterFraction <= 0 {
+		return interval
+	}
+
+	maxJitter := int64(float64(interval) * fr.PollJitterFraction)
+	// nolint:gosec
+	// The jitter only spreads the load, it doesn't need a cryptographically secure source.
+	return interval + time.Duration(rand.Int63n(2*maxJitter+1)-maxJitter)
+}
+
+func (fr *FileReader) hasStartupDelay() bool {
+	return fr.StartupDelay > 0 || fr.StartupJitter > 0
+}
+
+// startupDelay returns how long to wait before the first run, adding a random share of the jitter to the
+// configured delay so that providers sharing the same settings are staggered.
+func (fr *FileReader) startupDelay() time.Duration {
+	delay := fr.StartupDelay
+	if fr.StartupJitter > 0 {
+		// nolint:gosec
+		// The jitter only spreads the load, it doesn't need a cryptographically secure source.
+		delay += time.Duration(rand.Int63n(int64(fr.StartupJitter) + 1))
+	}
+	return delay
+}
+
 // walkDisk traverses the file system for the defined path, reading dashboard definition files,
-// and applies any change to the database.
+// and applies any change to the database. Concurrent calls wait for the run in progress to finish.
 func (fr *FileReader) walkDisk(ctx context.Context) error {
+	if fr.RunTimeout > 0 {
+		return fr.runWithTimeout(ctx)
+	}
+
+	if err := fr.acquireRunSlot(ctx); err != nil {
+		return err
+	}
+	defer fr.runSlots.release()
+
+	fr.runMux.Lock()
+	defer fr.runMux.Unlock()
+	return fr.run(ctx)
+}
+
+// run performs a single run and records its outcome. The caller must hold runMux.
+func (fr *FileReader) run(ctx context.Context) error {
+	start := fr.Clock.Now()
+	fr.stats = runStats{}
+	fr.pathUnreadable = false
+	fr.synced = false
+	fr.mux.Lock()
+	fr.forcing, fr.forceNextRun = fr.forceNextRun, false
+	fr.mux.Unlock()
+	runCtx, stop := fr.drainContext(ctx)
+	err := fr.syncDashboards(runCtx)
+	stop()
+	if fr.forcing && fr.synced && err == nil {
+		fr.recordForcedConfig()
+	} else if fr.forcing {
+		// the dashboards weren't all saved again, leave it to the next run
+		fr.mux.Lock()
+		fr.forceNextRun = true
+		fr.mux.Unlock()
+	}
+	fr.forcing = false
+	fr.recordRun(start, err)
+	if fr.ReportPath != "" {
+		if reportErr := fr.writeReport(start, err); reportErr != nil {
+			fr.log.Error("failed to write provisioning report", "path", fr.ReportPath, "error", reportErr)
+		}
+	}
+	if err == nil {
+		fr.logRunSummary(start)
+	}
+	return err
+}
+
+// syncDashboards performs a single provisioning run.
+func (fr *FileReader) syncDashboards(ctx context.Context) error {
 	fr.log.Debug("Start walking disk", "path", fr.Path)
+	// folders are looked up again on every run so that renamed or deleted folders are picked up
+	fr.folderIDs = map[folderKey]int64{}
+
+	if fr.Archive != "" {
+		if err := fr.extractArchive(); err != nil {
+			return err
+		}
+	}
+
 	resolvedPath := fr.resolvedPath()
 	if _, err := os.Stat(resolvedPath); err != nil {
+		fr.pathUnreadable = true
 		return err
 	}
 
+	if fr.SkipOnUnhealthyDB && !fr.databaseHealthy(ctx) {
+		return nil
+	}
+
+	// verify only runs don't write anything, so they don't need to keep other instances away
+	if fr.LockFile && !fr.VerifyOnly {
+		acquired, lock, err := fr.acquireLock(resolvedPath, fr.Clock.Now())
+		if err != nil {
+			return fmt.Errorf("failed to acquire provisioning lock: %w", err)
+		}
+		if !acquired {
+			fr.log.Info("skipping provisioning run, the directory is locked by another instance", "instance", lock.Instance,
+				"expires", time.Unix(lock.Expires, 0))
+			return nil
+		}
+	}
+
 	provisionedDashboardRefs, err := getProvisionedDashboardsByPath(fr.dashboardProvisioningService, fr.Cfg.Name)
 	if err != nil {
 		return err
 	}
 
+	if err := fr.updateAuxiliaryChecksum(resolvedPath); err != nil {
+		return fmt.Errorf("failed to read auxiliary files: %w", err)
+	}
+
+	if fr.IndexPath != "" && !fr.VerifyOnly {
+		if err := fr.startIndexedRun(); err != nil {
+			return fmt.Errorf("failed to prepare provisioning index: %w", err)
+		}
+	}
+
 	// Find relevant files
 	filesFoundOnDisk := map[string]os.FileInfo{}
-	if err := filepath.Walk(resolvedPath, createWalkFn(filesFoundOnDisk)); err != nil {
+	if err := fr.walkFiles(resolvedPath, filesFoundOnDisk); err != nil {
 		return err
 	}
+	if err := fr.checkFileCount(resolvedPath, len(filesFoundOnDisk)); err != nil {
+		return err
+	}
+
+	if fr.Manifest != "" {
+		if err := fr.verifyManifest(resolvedPath, filesFoundOnDisk); err != nil {
+			fr.log.Warn("skipping provisioning run, files on disk do not match the manifest", "manifest", fr.Manifest, "error", err)
+			return nil
+		}
+	}
+
+	if fr.inCanary() {
+		fr.filterCanaryMatches(resolvedPath, filesFoundOnDisk, provisionedDashboardRefs)
+	}
 
-	fr.handleMissingDashboardFiles(ctx, provisionedDashboardRefs, filesFoundOnDisk)
+	fr.stats.files = len(filesFoundOnDisk)
+	if fr.VerifyOnly {
+		fr.recordDrift(fr.detectDrift(provisionedDashboardRefs, filesFoundOnDisk))
+		return nil
+	}
+	fr.synced = true
+
+	fr.detectRenamedFiles(ctx, provisionedDashboardRefs, filesFoundOnDisk)
+	if err := fr.handleMissingDashboardFiles(ctx, provisionedDashboardRefs, filesFoundOnDisk); err != nil {
+		return err
+	}
+	fr.fileCache.prune(filesFoundOnDisk)
+	fr.quarantine.prune(filesFoundOnDisk)
 
 	usageTracker := newUsageTracker()
 	if fr.FoldersFromFilesStructure {
@@ -118,12 +956,66 @@ func (fr *FileReader) walkDisk(ctx context.Context) error {
 	}
 
 	fr.mux.Lock()
-	defer fr.mux.Unlock()
-
 	fr.usageTracker = usageTracker
+	fr.mux.Unlock()
+
+	if fr.nextIndex != nil && !shuttingDown(ctx) {
+		if err := fr.finishIndexedRun(); err != nil {
+			fr.log.Error("failed to write provisioning index", "path", fr.IndexPath, "error", err)
+		}
+	}
+
+	if fr.SoftDelete && fr.TrashRetention > 0 && !shuttingDown(ctx) {
+		fr.sweepTrash(ctx)
+	}
+
+	if fr.PruneEmptyFolders && !shuttingDown(ctx) {
+		fr.pruneEmptyFolders(ctx, usageTracker)
+	}
+
+	if fr.OnDuplicateTitle == onDuplicateTitleError {
+		return usageTracker.checkDuplicateTitles()
+	}
+
 	return nil
 }
 
+// pruneEmptyFolders deletes the folders created by this provisioner which no longer hold anything. Folders
+// holding dashboards of the current run are skipped without asking the database, while the others are only
+// deleted if they contain no dashboard, folder, library element or alert rule, whoever they belong to.
+func (fr *FileReader) pruneEmptyFolders(ctx context.Context, usageTracker *usageTracker) {
+	usedFolders := map[int64]struct{}{}
+	for identity := range usageTracker.titleUsage {
+		usedFolders[identity.folderID] = struct{}{}
+	}
+
+	// nested folders only become empty once their children are gone, so keep going while folders are deleted
+	for pruned := true; pruned; {
+		pruned = false
+		for folderID, orgID := range fr.createdFolders {
+			if _, used := usedFolders[folderID]; used {
+				continue
+			}
+
+			deleted, err := fr.dashboardProvisioningService.DeleteEmptyFolderForProvisionedDashboards(ctx, folderID, orgID)
+			if err != nil {
+				if errors.Is(err, dashboards.ErrFolderNotFound) {
+					delete(fr.createdFolders, folderID)
+					continue
+				}
+				fr.log.Error("failed to prune empty folder", "folderId", folderID, "error", err)
+				continue
+			}
+
+			if deleted {
+				fr.log.Info("pruned empty folder", "folderId", folderID)
+				delete(fr.createdFolders, folderID)
+				pruned = true
+			}
+		}
+	}
+}
+
 func (fr *FileReader) changeWritePermissions(restrict bool) {
 	fr.mux.Lock()
 	defer fr.mux.Unlock()
@@ -142,13 +1034,23 @@ func (fr *FileReader) isDatabaseAccessRestricted() bool {
 func (fr *FileReader) storeDashboardsInFolder(ctx context.Context, filesFoundOnDisk map[string]os.FileInfo,
 	dashboardRefs map[string]*models.DashboardProvisioning, usageTracker *usageTracker) error {
 	folderID, err := fr.getOrCreateFolderID(ctx, fr.Cfg, fr.dashboardProvisioningService, fr.Cfg.Folder)
+	if errors.Is(err, ErrFolderConflict) && fr.OnFolderConflict == onFolderConflictSkip {
+		fr.log.Warn("skipping dashboards, their folder name is used by a dashboard", "folder", fr.Cfg.Folder)
+		return nil
+	}
 	if err != nil && !errors.Is(err, ErrFolderNameMissing) {
 		return err
 	}
 
 	// save dashboards based on json files
-	for path, fileInfo := range filesFoundOnDisk {
-		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, fileInfo, dashboardRefs)
+	for _, path := range fr.sortedPaths(filesFoundOnDisk) {
+		if shuttingDown(ctx) {
+			return errShuttingDown
+		}
+		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, filesFoundOnDisk[path], dashboardRefs, usageTracker)
+		if errors.Is(err, ErrInvalidDashboard) {
+			return err
+		}
 		if err != nil {
 			fr.log.Error("failed to save dashboard", "error", err)
 			continue
@@ -163,20 +1065,23 @@ func (fr *FileReader) storeDashboardsInFolder(ctx context.Context, filesFoundOnD
 // in Grafana as they are in on the filesystem.
 func (fr *FileReader) storeDashboardsInFoldersFromFileStructure(ctx context.Context, filesFoundOnDisk map[string]os.FileInfo,
 	dashboardRefs map[string]*models.DashboardProvisioning, resolvedPath string, usageTracker *usageTracker) error {
-	for path, fileInfo := range filesFoundOnDisk {
-		folderName := ""
-
-		dashboardsFolder := filepath.Dir(path)
-		if dashboardsFolder != resolvedPath {
-			folderName = filepath.Base(dashboardsFolder)
+	for _, path := range fr.sortedPaths(filesFoundOnDisk) {
+		if shuttingDown(ctx) {
+			return errShuttingDown
+		}
+		folderID, folderName, err := fr.getOrCreateFileStructureFolderID(ctx, resolvedPath, path)
+		if errors.Is(err, ErrFolderConflict) && fr.OnFolderConflict == onFolderConflictSkip {
+			fr.log.Warn("skipping dashboard, its folder name is used by a dashboard", "file", path, "folder", folderName)
+			continue
 		}
-
-		folderID, err := fr.getOrCreateFolderID(ctx, fr.Cfg, fr.dashboardProvisioningService, folderName)
 		if err != nil && !errors.Is(err, ErrFolderNameMissing) {
 			return fmt.Errorf("can't provision folder %q from file system structure: %w", folderName, err)
 		}
 
-		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, fileInfo, dashboardRefs)
+		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, filesFoundOnDisk[path], dashboardRefs, usageTracker)
+		if errors.Is(err, ErrInvalidDashboard) {
+			return err
+		}
 		usageTracker.track(provisioningMetadata)
 		if err != nil {
 			fr.log.Error("failed to save dashboard", "error", err)
@@ -185,71 +1090,406 @@ func (fr *FileReader) storeDashboardsInFoldersFromFileStructure(ctx context.Cont
 	return nil
 }
 
+// getOrCreateFileStructureFolderID returns the id and name of the folder the dashboard file at path is provisioned
+// into when the folders come from the file structure.
+func (fr *FileReader) getOrCreateFileStructureFolderID(ctx context.Context, resolvedPath string, path string) (int64, string, error) {
+	cfg := fr.Cfg
+	if orgID := fr.orgIDForPath(resolvedPath, path); orgID != fr.Cfg.OrgID {
+		cfg = fr.configForOrg(orgID)
+	}
+
+	// when a folder UID is configured, the folders of the file structure are created inside that folder
+	var parentID int64
+	if cfg.FolderUID != "" {
+		var err error
+		if parentID, err = fr.getOrCreateParentFolderID(ctx, cfg); err != nil {
+			return 0, cfg.Folder, err
+		}
+		childCfg := *cfg
+		childCfg.FolderUID = ""
+		cfg = &childCfg
+	}
+
+	dashboardsFolder := filepath.Dir(path)
+	switch {
+	case dashboardsFolder == resolvedPath && fr.RootFolder != "":
+		// files at the top level go into the root folder rather than the General folder
+		folderID, err := fr.getOrCreateChildFolderID(ctx, cfg, fr.dashboardProvisioningService, fr.RootFolder, parentID)
+		return folderID, fr.RootFolder, err
+	case dashboardsFolder == resolvedPath && parentID != 0:
+		// files at the top level go into the parent folder rather than the General folder
+		return parentID, cfg.Folder, nil
+	case fr.NestedFolders:
+		folderName, err := filepath.Rel(resolvedPath, dashboardsFolder)
+		if err != nil {
+			return 0, dashboardsFolder, err
+		}
+		folderID, err := fr.getOrCreateNestedFolderID(ctx, cfg, fr.dashboardProvisioningService, folderName, parentID)
+		return folderID, folderName, err
+	default:
+		folderName := ""
+		if dashboardsFolder != resolvedPath {
+			folderName = filepath.Base(dashboardsFolder)
+		}
+		folderTitle, err := fr.folderTitle(folderName)
+		if err != nil {
+			return 0, folderName, err
+		}
+		folderID, err := fr.getOrCreateChildFolderID(ctx, cfg, fr.dashboardProvisioningService, folderTitle, parentID)
+		return folderID, folderTitle, err
+	}
+}
+
 // handleMissingDashboardFiles will unprovision or delete dashboards which are missing on disk.
 func (fr *FileReader) handleMissingDashboardFiles(ctx context.Context, provisionedDashboardRefs map[string]*models.DashboardProvisioning,
-	filesFoundOnDisk map[string]os.FileInfo) {
+	filesFoundOnDisk map[string]os.FileInfo) error {
 	// find dashboards to delete since json file is missing
-	var dashboardsToDelete []int64
-	for path, provisioningData := range provisionedDashboardRefs {
-		_, existsOnDisk := filesFoundOnDisk[path]
-		if !existsOnDisk {
-			dashboardsToDelete = append(dashboardsToDelete, provisioningData.DashboardId)
+	var missing []string
+	for path := range provisionedDashboardRefs {
+		if _, existsOnDisk := filesFoundOnDisk[path]; !existsOnDisk {
+			missing = append(missing, path)
 		}
 	}
+	sort.Strings(missing)
+
+	dashboardsToDelete := make([]*models.DashboardProvisioning, 0, len(missing))
+	for _, path := range missing {
+		dashboardsToDelete = append(dashboardsToDelete, provisionedDashboardRefs[path])
+	}
+
+	// the dashboards which are kept may link to the ones about to be removed
+	var references map[referenceKey][]string
+	if fr.ProtectReferenced && len(dashboardsToDelete) > 0 {
+		var err error
+		if references, err = fr.dashboardReferences(ctx, dashboardsToDelete); err != nil {
+			return fmt.Errorf("failed to look up the references between dashboards: %w", err)
+		}
+	}
+
+	for _, provisioningData := range dashboardsToDelete {
+		if shuttingDown(ctx) {
+			return errShuttingDown
+		}
+		if fr.ProtectReferenced && fr.keepReferenced(ctx, provisioningData, references) {
+			continue
+		}
+		fr.deleteProvisionedDashboard(ctx, provisioningData, "missing on disk")
+	}
+	return nil
+}
+
+// waitForWrite blocks until the provider is allowed to write to the database again, or ctx is done.
+func (fr *FileReader) waitForWrite(ctx context.Context) error {
+	if fr.writeLimiter == nil {
+		return nil
+	}
+	return fr.writeLimiter.Wait(ctx)
+}
+
+// deleteProvisionedDashboard removes the dashboard of a file which no longer provisions it, moving it to the trash
+// folder instead of deleting it when SoftDelete is set.
+func (fr *FileReader) deleteProvisionedDashboard(ctx context.Context, provisioningData *models.DashboardProvisioning, reason string) {
+	if fr.SoftDelete && !fr.ReadOnlyAdditive && !fr.Cfg.DisableDeletion {
+		keep, err := fr.keepImmutable(ctx, provisioningData, reason)
+		if err != nil {
+			fr.log.Error("failed to move dashboard to the trash", "id", provisioningData.DashboardId, "error", err)
+			return
+		}
+		if keep {
+			return
+		}
+		fr.trashProvisionedDashboard(ctx, provisioningData, reason)
+		return
+	}
+	fr.removeProvisionedDashboard(ctx, provisioningData, reason)
+}
+
+// removeProvisionedDashboard deletes a provisioned dashboard, or only unprovisions it if deletion is disabled
+// for the provisioner. Nothing is removed in read only additive mode.
+func (fr *FileReader) removeProvisionedDashboard(ctx context.Context, provisioningData *models.DashboardProvisioning, reason string) {
+	dashboardID := provisioningData.DashboardId
+
+	if fr.ReadOnlyAdditive {
+		fr.log.Debug("keeping provisioned dashboard in read only additive mode", "id", dashboardID,
+			"file", provisioningData.ExternalId, "reason", reason)
+		return
+	}
+
+	keep, err := fr.keepImmutable(ctx, provisioningData, reason)
+	if err != nil {
+		fr.log.Error("failed to remove dashboard", "id", dashboardID, "error", err)
+		return
+	}
+	if keep {
+		return
+	}
+
+	if err := fr.waitForWrite(ctx); err != nil {
+		fr.log.Error("failed to remove dashboard", "id", dashboardID, "error", err)
+		return
+	}
 
 	if fr.Cfg.DisableDeletion {
 		// If deletion is disabled for the provisioner we just remove provisioning metadata about the dashboard
 		// so afterwards the dashboard is considered unprovisioned.
-		for _, dashboardID := range dashboardsToDelete {
-			fr.log.Debug("unprovisioning provisioned dashboard. missing on disk", "id", dashboardID)
-			err := fr.dashboardProvisioningService.UnprovisionDashboard(ctx, dashboardID)
-			if err != nil {
-				fr.log.Error("failed to unprovision dashboard", "dashboard_id", dashboardID, "error", err)
-			}
-		}
-	} else {
-		// delete dashboards missing JSON file
-		for _, dashboardID := range dashboardsToDelete {
-			fr.log.Debug("deleting provisioned dashboard, missing on disk", "id", dashboardID)
-			err := fr.dashboardProvisioningService.DeleteProvisionedDashboard(ctx, dashboardID, fr.Cfg.OrgID)
-			if err != nil {
-				fr.log.Error("failed to delete dashboard", "id", dashboardID, "error", err)
-			}
+		fr.log.Debug("unprovisioning provisioned dashboard", "id", dashboardID, "reason", reason)
+		start := fr.Clock.Now()
+		err := fr.dashboardProvisioningService.UnprovisionDashboard(ctx, dashboardID)
+		if err != nil {
+			fr.log.Error("failed to unprovision dashboard", "dashboard_id", dashboardID, "error", err)
+			return
 		}
+		fr.logAction(actionUnprovision, provisioningData.ExternalId, "", 0, provisioningData.CheckSum, start)
+		return
 	}
+
+	fr.log.Debug("deleting provisioned dashboard", "id", dashboardID, "reason", reason)
+	start := fr.Clock.Now()
+	orgID := fr.Cfg.OrgID
+	if fr.OrgIDFromPath {
+		orgID = fr.orgIDForPath(fr.resolvedPath(), provisioningData.ExternalId)
+	}
+	err = fr.dashboardProvisioningService.DeleteProvisionedDashboard(ctx, dashboardID, orgID)
+	if err != nil {
+		fr.log.Error("failed to delete dashboard", "id", dashboardID, "error", err)
+		return
+	}
+	fr.logAction(actionDelete, provisioningData.ExternalId, "", 0, provisioningData.CheckSum, start)
+}
+
+const (
+	actionSave        = "save"
+	actionDelete      = "delete"
+	actionUnprovision = "unprovision"
+	actionTrash       = "trash"
+)
+
+// logAction emits a single structured line describing a change applied to the database. The set of fields
+// is kept stable so that it can be relied upon by log pipelines. The change is also counted for the run summary.
+func (fr *FileReader) logAction(action, path, uid string, folderID int64, checkSum string, start time.Time) {
+	fr.stats.count(action)
+	fr.report(action, path, uid, checkSum, nil)
+	fr.log.Debug("provisioning action",
+		"action", action,
+		"provisioner", fr.Cfg.Name,
+		"file", path,
+		"uid", uid,
+		"folderId", folderID,
+		"checksum", checkSum,
+		"durationMs", fr.Clock.Since(start).Milliseconds(),
+	)
 }
 
 // saveDashboard saves or updates the dashboard provisioning file at path.
 func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID int64, fileInfo os.FileInfo,
-	provisionedDashboardRefs map[string]*models.DashboardProvisioning) (provisioningMetadata, error) {
-	provisioningMetadata := provisioningMetadata{}
-	resolvedFileInfo, err := resolveSymlink(fileInfo, path)
+	provisionedDashboardRefs map[string]*models.DashboardProvisioning, usageTracker *usageTracker) (provisioningMetadata, error) {
+	provisioningMetadata := provisioningMetadata{path: path}
+	resolvedFileInfo, err := fr.resolveSymlink(fileInfo, path)
 	if err != nil {
 		return provisioningMetadata, err
 	}
 
+	if fr.isTooOld(resolvedFileInfo.ModTime()) {
+		fr.log.Warn("ignoring dashboard file older than the configured threshold", "file", path,
+			"modTime", resolvedFileInfo.ModTime(), "ignoreOlderThan", fr.IgnoreOlderThan)
+		return provisioningMetadata, nil
+	}
+
 	provisionedData, alreadyProvisioned := provisionedDashboardRefs[path]
 
-	jsonFile, err := fr.readDashboardFromFile(path, resolvedFileInfo.ModTime(), folderID)
+	if fr.quarantine.isQuarantined(path, resolvedFileInfo, fr.QuarantineAfter) {
+		return provisioningMetadata, nil
+	}
+
+	// files which didn't change since they were indexed are neither read nor hashed again
+	if entry, ok := fr.indexedEntry(path, resolvedFileInfo, provisionedData); ok && usageTracker.uidUsage[entry.UID] == 0 {
+		provisioningMetadata.uid = entry.UID
+		provisioningMetadata.identity = dashboardIdentity{title: entry.Title, folderID: entry.FolderID}
+		fr.nextIndex[path] = entry
+		fr.stats.unchanged++
+		fr.report(reportActionUnchanged, path, entry.UID, entry.CheckSum, nil)
+		return provisioningMetadata, nil
+	}
+
+	// files of other kinds which didn't provision a dashboard yet are skipped without being read, files which did
+	// are still read since they may mark their dashboard as deleted
+	if fr.PeekKind && !alreadyProvisioned {
+		if kind, ok := fr.peekDashboardKind(path, resolvedFileInfo); ok && !fr.isKindAllowed(kind) {
+			fr.log.Debug("skipping dashboard file of a kind which isn't provisioned", "file", path, "kind", kind)
+			return provisioningMetadata, nil
+		}
+	}
+
+	jsonFile, err := fr.readDashboardFromFile(path, resolvedFileInfo, folderID)
 	if err != nil {
+		fr.recordFileError(ctx, provisionedData, err)
+		if !fr.SkipInvalid && !errors.Is(err, ErrTransformFailed) && !errors.Is(err, ErrChecksumMismatch) &&
+			!errors.Is(err, ErrChecksumSidecarMissing) {
+			return provisioningMetadata, fmt.Errorf("%w: failed to load dashboard from %s: %v", ErrInvalidDashboard, path, err)
+		}
 		fr.log.Error("failed to load dashboard from ", "file", path, "error", err)
+		fr.report(reportActionError, path, "", "", err)
+		if fr.QuarantineAfter > 0 && fr.quarantine.fail(path, resolvedFileInfo) == fr.QuarantineAfter {
+			fr.log.Warn("quarantining dashboard file until it changes", "file", path, "failures", fr.QuarantineAfter)
+		}
+		return provisioningMetadata, nil
+	}
+	fr.quarantine.succeed(path)
+
+	if jsonFile.deleted {
+		if alreadyProvisioned {
+			fr.deleteProvisionedDashboard(ctx, provisionedData, "marked as deleted")
+		}
+		return provisioningMetadata, nil
+	}
+
+	// files of other kinds are left alone, including the dashboards they already provisioned
+	if !fr.isKindAllowed(jsonFile.kind) {
+		fr.log.Debug("skipping dashboard file of a kind which isn't provisioned", "file", path, "kind", jsonFile.kind)
+		return provisioningMetadata, nil
+	}
+
+	if jsonFile.disabled {
+		if alreadyProvisioned {
+			fr.deleteProvisionedDashboard(ctx, provisionedData, "disabled")
+		}
+		return provisioningMetadata, nil
+	}
+
+	// the dashboard provisioned by an earlier version, if any, is left as it is
+	if jsonFile.unsupported {
+		fr.log.Warn("skipping dashboard, it doesn't support this Grafana version", "file", path,
+			"grafanaVersion", fr.grafanaVersion)
+		return provisioningMetadata, nil
+	}
+
+	orgID := fr.Cfg.OrgID
+	if fr.OrgIDFromPath {
+		orgID = fr.orgIDForPath(fr.resolvedPath(), path)
+		jsonFile.dashboard.OrgId = orgID
+		jsonFile.dashboard.Dashboard.OrgId = orgID
+	}
+
+	folderOverride, err := resolveFolderOverride(path, jsonFile)
+	if err != nil {
+		fr.log.Error("failed to read folder override for dashboard", "file", path, "error", err)
 		return provisioningMetadata, nil
 	}
+	if folderOverride == "" {
+		folderOverride = fr.folderFromRules(fr.resolvedPath(), path)
+	}
+	if folderOverride != "" {
+		overrideID, err := fr.getOrCreateOverrideFolderID(ctx, folderOverride, orgID)
+		if errors.Is(err, ErrFolderConflict) && fr.OnFolderConflict == onFolderConflictSkip {
+			fr.log.Warn("skipping dashboard, its folder name is used by a dashboard", "file", path, "folder", folderOverride)
+			return provisioningMetadata, nil
+		}
+		if err != nil {
+			return provisioningMetadata, fmt.Errorf("can't provision folder %q: %w", folderOverride, err)
+		}
+		jsonFile.dashboard.Dashboard.FolderId = overrideID
+	}
+
+	var permissions []*models.DashboardACL
+	if fr.ManagePermissions {
+		permissions, err = resolvePermissions(path, jsonFile)
+		if err != nil {
+			fr.log.Error("failed to read permissions for dashboard", "file", path, "error", err)
+			return provisioningMetadata, nil
+		}
+	}
 
+	// dashboards are only saved when their file changed, which keeps the edits made from the UI when allowed
 	upToDate := alreadyProvisioned
 	if provisionedData != nil {
 		upToDate = jsonFile.checkSum == provisionedData.CheckSum
 	}
+	if fr.forcing {
+		upToDate = false
+	}
 
 	// keeps track of which UIDs and titles we have already provisioned
 	dash := jsonFile.dashboard
+	if fr.OnDuplicateTitle == onDuplicateTitleSuffix {
+		usageTracker.disambiguateTitle(dash.Dashboard)
+	}
 	provisioningMetadata.uid = dash.Dashboard.Uid
 	provisioningMetadata.identity = dashboardIdentity{title: dash.Dashboard.Title, folderID: dash.Dashboard.FolderId}
 
+	// files are processed in a stable order, so the first file using a UID wins rather than whichever was saved last
+	if dash.Dashboard.Uid != "" && usageTracker.uidUsage[dash.Dashboard.Uid] > 0 {
+		fr.log.Warn("skipping dashboard, its uid was already provisioned from another file", "file", path,
+			"uid", dash.Dashboard.Uid)
+		fr.report(reportActionError, path, dash.Dashboard.Uid, jsonFile.checkSum,
+			fmt.Errorf("uid %q was already provisioned from another file", dash.Dashboard.Uid))
+		return provisioningMetadata, nil
+	}
+
+	// in create only mode, dashboards which already exist belong to the UI, whatever their file contains
+	if fr.CreateOnly {
+		exists := alreadyProvisioned
+		if !exists && dash.Dashboard.Uid != "" {
+			dashboardID, err := fr.reconcileByUID(ctx, dash.Dashboard)
+			if err != nil {
+				return provisioningMetadata, fmt.Errorf("failed to look up dashboard with uid %q: %w", dash.Dashboard.Uid, err)
+			}
+			exists = dashboardID != 0
+		}
+		if exists {
+			fr.stats.unchanged++
+			fr.report(reportActionUnchanged, path, dash.Dashboard.Uid, jsonFile.checkSum, nil)
+			return provisioningMetadata, nil
+		}
+	}
+
 	if upToDate {
+		// a file reverted to the provisioned content no longer has an error to show
+		fr.recordFileError(ctx, provisionedData, nil)
+		fr.stats.unchanged++
+		fr.report(reportActionUnchanged, path, dash.Dashboard.Uid, jsonFile.checkSum, nil)
+		fr.indexFile(path, resolvedFileInfo, jsonFile, provisioningMetadata)
 		return provisioningMetadata, nil
 	}
 
+	if provisionedData != nil {
+		immutable, err := fr.isStoredImmutable(ctx, orgID, provisionedData.DashboardId)
+		if err != nil {
+			return provisioningMetadata, fmt.Errorf("failed to get stored dashboard to check whether it's immutable: %w", err)
+		}
+		if immutable {
+			fr.log.Warn("immutable dashboard was modified on disk but not applied", "file", path,
+				"uid", dash.Dashboard.Uid, "dashboardId", provisionedData.DashboardId)
+			return provisioningMetadata, nil
+		}
+	}
+
+	if fr.PreventDowngrade && provisionedData != nil {
+		downgrade, stored, provisioned, err := fr.isDowngrade(ctx, dash, provisionedData)
+		if err != nil {
+			return provisioningMetadata, fmt.Errorf("failed to get stored dashboard to compare versions: %w", err)
+		}
+		if downgrade {
+			fr.log.Warn("skipping dashboard, its schema version is lower than the stored one", "file", path,
+				"uid", dash.Dashboard.Uid, "schemaVersion", provisioned, "storedSchemaVersion", stored)
+			return provisioningMetadata, nil
+		}
+	}
+
+	if fr.EmitDiffs && provisionedData != nil {
+		fr.logDiff(ctx, path, dash, provisionedData)
+	}
+
+	if err := fr.decryptSecrets(ctx, dash.Dashboard.Data); err != nil {
+		fr.log.Error("failed to decrypt secrets of dashboard", "file", path, "error", err)
+		return provisioningMetadata, nil
+	}
+
+	if fr.MergeStrategy == mergeStrategyMerge {
+		if err := fr.mergeWithStored(ctx, dash, provisionedData); err != nil {
+			return provisioningMetadata, err
+		}
+	}
+
 	if dash.Dashboard.Id != 0 {
 		dash.Dashboard.Data.Set("id", nil)
 		dash.Dashboard.Id = 0
@@ -257,6 +1497,16 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 
 	if alreadyProvisioned {
 		dash.Dashboard.SetId(provisionedData.DashboardId)
+	} else if fr.ReconcileByUID && dash.Dashboard.Uid != "" {
+		dashboardID, err := fr.reconcileByUID(ctx, dash.Dashboard)
+		if err != nil {
+			return provisioningMetadata, fmt.Errorf("failed to look up dashboard with uid %q: %w", dash.Dashboard.Uid, err)
+		}
+		if dashboardID != 0 {
+			fr.log.Info("linking provisioned dashboard to the existing dashboard with its uid", "file", path,
+				"uid", dash.Dashboard.Uid, "dashboardId", dashboardID)
+			dash.Dashboard.SetId(dashboardID)
+		}
 	}
 
 	if !fr.isDatabaseAccessRestricted() {
@@ -267,10 +1517,36 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 			Updated:    resolvedFileInfo.ModTime().Unix(),
 			CheckSum:   jsonFile.checkSum,
 		}
-		_, err := fr.dashboardProvisioningService.SaveProvisionedDashboard(ctx, dash, dp)
+		if err := fr.waitForWrite(ctx); err != nil {
+			return provisioningMetadata, err
+		}
+		start := fr.Clock.Now()
+		// a hung save is abandoned so that the remaining dashboards are still provisioned
+		saveCtx, cancel := context.WithTimeout(ctx, fr.SaveTimeout)
+		savedDash, err := fr.dashboardProvisioningService.SaveProvisionedDashboard(saveCtx, dash, dp)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("saving dashboard from %s timed out after %s: %w", path, fr.SaveTimeout, err)
+		}
 		if err != nil {
+			fr.recordFileError(ctx, provisionedData, err)
 			return provisioningMetadata, err
 		}
+		if provisionedData != nil {
+			// saving the provisioning record cleared its error
+			provisionedData.LastError = ""
+		}
+
+		uid := dash.Dashboard.Uid
+		if savedDash != nil && savedDash.Uid != "" {
+			uid = savedDash.Uid
+		}
+		fr.logAction(actionSave, path, uid, dash.Dashboard.FolderId, jsonFile.checkSum, start)
+
+		if fr.ManagePermissions && savedDash != nil {
+			fr.applyPermissions(ctx, path, orgID, savedDash.Id, permissions)
+		}
+		fr.indexFile(path, resolvedFileInfo, jsonFile, provisioningMetadata)
 	} else {
 		fr.log.Warn("Not saving new dashboard due to restricted database access", "provisioner", fr.Cfg.Name,
 			"file", path, "folderId", dash.Dashboard.FolderId)
@@ -279,6 +1555,36 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 	return provisioningMetadata, nil
 }
 
+// forceReprovision makes the next run save every dashboard again, even those whose file didn't change, after which
+// runs go back to comparing checksums.
+func (fr *FileReader) forceReprovision() {
+	fr.mux.Lock()
+	defer fr.mux.Unlock()
+	fr.forceNextRun = true
+}
+
+// checkFileCount aborts the run when the path holds more files than maxFiles, which usually means the provider points
+// to the wrong directory, and warns when it holds more than warnFiles. Zero disables either threshold.
+func (fr *FileReader) checkFileCount(resolvedPath string, count int) error {
+	if fr.MaxFiles > 0 && int64(count) > fr.MaxFiles {
+		return fmt.Errorf("%w: found %d files in %s, more than the maximum of %d", ErrTooManyFiles, count, resolvedPath, fr.MaxFiles)
+	}
+	if fr.WarnFiles > 0 && int64(count) > fr.WarnFiles {
+		fr.log.Warn("provider path holds more dashboard files than expected", "path", resolvedPath, "files", count,
+			"warnFiles", fr.WarnFiles)
+	}
+	return nil
+}
+
+// isTooOld reports whether a file last modified at modTime falls outside the ignoreOlderThan window.
+func (fr *FileReader) isTooOld(modTime time.Time) bool {
+	if fr.IgnoreOlderThan <= 0 {
+		return false
+	}
+
+	return modTime.Before(fr.Clock.Now().Add(-fr.IgnoreOlderThan))
+}
+
 func getProvisionedDashboardsByPath(service dashboards.DashboardProvisioningService, name string) (
 	map[string]*models.DashboardProvisioning, error) {
 	arr, err := service.GetProvisionedDashboardData(name)
@@ -295,46 +1601,190 @@ func getProvisionedDashboardsByPath(service dashboards.DashboardProvisioningServ
 }
 
 func (fr *FileReader) getOrCreateFolderID(ctx context.Context, cfg *config, service dashboards.DashboardProvisioningService, folderName string) (int64, error) {
+	return fr.getOrCreateChildFolderID(ctx, cfg, service, folderName, 0)
+}
+
+// getOrCreateOverrideFolderID returns the id of a folder requested by a single dashboard file. Unlike the folder of
+// the provider, it is never created with the configured folder UID.
+func (fr *FileReader) getOrCreateOverrideFolderID(ctx context.Context, folderName string, orgID int64) (int64, error) {
+	cfg := fr.configForOrg(orgID)
+	cfg.FolderUID = ""
+	return fr.getOrCreateFolderID(ctx, cfg, fr.dashboardProvisioningService, folderName)
+}
+
+// orgIDForPath returns the organization the dashboard file at path is provisioned into. When orgIdFromPath is
+// enabled, the top level directory of the file is looked up in the orgIdMapping, falling back to the
+// organization of the provider for files which don't match any entry.
+func (fr *FileReader) orgIDForPath(resolvedPath, path string) int64 {
+	if !fr.OrgIDFromPath {
+		return fr.Cfg.OrgID
+	}
+
+	relativePath, err := filepath.Rel(resolvedPath, filepath.Dir(path))
+	if err != nil || relativePath == "." {
+		return fr.Cfg.OrgID
+	}
+
+	dir := strings.Split(filepath.ToSlash(relativePath), "/")[0]
+	if orgID, ok := fr.OrgIDMapping[dir]; ok {
+		return orgID
+	}
+
+	return fr.Cfg.OrgID
+}
+
+// folderFromRules returns the folder of the first folder rule matching the path of the dashboard relative to the
+// provider path, or an empty string if none matches.
+func (fr *FileReader) folderFromRules(resolvedPath, path string) string {
+	if len(fr.FolderRules) == 0 {
+		return ""
+	}
+
+	relativePath, err := filepath.Rel(resolvedPath, path)
+	if err != nil {
+		return ""
+	}
+	relativePath = filepath.ToSlash(relativePath)
+
+	for _, rule := range fr.FolderRules {
+		if rule.pattern.MatchString(relativePath) {
+			return rule.folder
+		}
+	}
+	return ""
+}
+
+// configForOrg returns a copy of the provider configuration targeting orgID.
+func (fr *FileReader) configForOrg(orgID int64) *config {
+	cfg := *fr.Cfg
+	cfg.OrgID = orgID
+	return &cfg
+}
+
+// getOrCreateNestedFolderID creates every folder along relativePath, parenting each level in the
+// previous one starting from parentID, and returns the id of the innermost folder.
+func (fr *FileReader) getOrCreateNestedFolderID(ctx context.Context, cfg *config, service dashboards.DashboardProvisioningService, relativePath string, parentID int64) (int64, error) {
+	if relativePath == "" || relativePath == "." {
+		return 0, ErrFolderNameMissing
+	}
+
+	for _, dirName := range strings.Split(filepath.ToSlash(relativePath), "/") {
+		folderName, err := fr.folderTitle(dirName)
+		if err != nil {
+			return 0, err
+		}
+		folderID, err := fr.getOrCreateChildFolderID(ctx, cfg, service, folderName, parentID)
+		if err != nil {
+			return 0, err
+		}
+		parentID = folderID
+	}
+
+	return parentID, nil
+}
+
+// getOrCreateChildFolderID returns the id of the folder named folderName within the folder parentID, creating it
+// if needed. When the name resolves to an existing dashboard and onFolderConflict is set to suffix, the first
+// available name among `folderName (2)`, `folderName (3)` and so on is used instead.
+func (fr *FileReader) getOrCreateChildFolderID(ctx context.Context, cfg *config, service dashboards.DashboardProvisioningService, folderName string, parentID int64) (int64, error) {
+	folderID, err := fr.getOrCreateNamedFolderID(ctx, cfg, service, folderName, parentID)
+	if !errors.Is(err, ErrFolderConflict) || fr.OnFolderConflict != onFolderConflictSuffix {
+		return folderID, err
+	}
+
+	for i := 2; i <= maxFolderConflictSuffix; i++ {
+		name := fmt.Sprintf("%s (%d)", folderName, i)
+		folderID, err = fr.getOrCreateNamedFolderID(ctx, cfg, service, name, parentID)
+		if errors.Is(err, ErrFolderConflict) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		fr.log.Warn("folder name is used by a dashboard, provisioning into a suffixed folder", "folder", folderName,
+			"suffixedFolder", name)
+		fr.folderIDs[folderKey{orgID: cfg.OrgID, parentID: parentID, slug: models.SlugifyTitle(folderName)}] = folderID
+		return folderID, nil
+	}
+
+	return 0, err
+}
+
+func (fr *FileReader) getOrCreateNamedFolderID(ctx context.Context, cfg *config, service dashboards.DashboardProvisioningService, folderName string, parentID int64) (int64, error) {
 	if folderName == "" {
 		return 0, ErrFolderNameMissing
 	}
 
-	cmd := &models.GetDashboardQuery{Slug: models.SlugifyTitle(folderName), OrgId: cfg.OrgID}
+	key := folderKey{orgID: cfg.OrgID, parentID: parentID, slug: models.SlugifyTitle(folderName)}
+	if folderID, ok := fr.folderIDs[key]; ok {
+		return folderID, nil
+	}
+
+	if err := fr.folderSlots.acquire(ctx); err != nil {
+		return 0, err
+	}
+	defer fr.folderSlots.release()
+
+	cmd := &models.GetDashboardQuery{Slug: key.slug, FolderId: parentID, OrgId: cfg.OrgID}
 	err := fr.dashboardStore.GetDashboard(ctx, cmd)
 
 	if err != nil && !errors.Is(err, dashboards.ErrDashboardNotFound) {
 		return 0, err
 	}
+	// a folder id of zero doesn't restrict the lookup, so folders found in another folder are left alone
+	if err == nil && cmd.Result.FolderId != parentID {
+		err = dashboards.ErrDashboardNotFound
+	}
 
 	// dashboard folder not found. create one.
 	if errors.Is(err, dashboards.ErrDashboardNotFound) {
 		dash := &dashboards.SaveDashboardDTO{}
 		dash.Dashboard = models.NewDashboardFolder(folderName)
 		dash.Dashboard.IsFolder = true
+		dash.Dashboard.FolderId = parentID
 		dash.Overwrite = true
 		dash.OrgId = cfg.OrgID
-		// set dashboard folderUid if given
-		if cfg.FolderUID == accesscontrol.GeneralFolderUID {
-			return 0, dashboards.ErrFolderInvalidUID
+		// the folderUid only identifies the top level folder, the folders nested in it get a uid of their own
+		if parentID == 0 {
+			if cfg.FolderUID == accesscontrol.GeneralFolderUID {
+				return 0, dashboards.ErrFolderInvalidUID
+			}
+			dash.Dashboard.SetUid(cfg.FolderUID)
 		}
-		dash.Dashboard.SetUid(cfg.FolderUID)
 		dbDash, err := service.SaveFolderForProvisionedDashboards(ctx, dash)
 		if err != nil {
 			return 0, err
 		}
 
+		fr.createdFolders[dbDash.Id] = cfg.OrgID
+		fr.folderIDs[key] = dbDash.Id
 		return dbDash.Id, nil
 	}
 
 	if !cmd.Result.IsFolder {
-		return 0, fmt.Errorf("got invalid response. expected folder, found dashboard")
+		return 0, ErrFolderConflict
 	}
 
+	fr.folderIDs[key] = cmd.Result.Id
 	return cmd.Result.Id, nil
 }
 
-func resolveSymlink(fileinfo os.FileInfo, path string) (os.FileInfo, error) {
-	checkFilepath, err := filepath.EvalSymlinks(path)
+// resolveSymlink returns the file info of the target of path when it is a symlink, unless symlink resolution is
+// disabled for the provider.
+func (fr *FileReader) resolveSymlink(fileInfo os.FileInfo, path string) (os.FileInfo, error) {
+	// the lines of JSON Lines files are already described by their resolved file
+	if _, isLine := fileInfo.(*jsonLineInfo); !fr.ResolveSymlinks || isLine {
+		return fileInfo, nil
+	}
+	return resolveSymlink(fileInfo, path, fr.MaxSymlinkDepth)
+}
+
+func resolveSymlink(fileinfo os.FileInfo, path string, maxDepth int) (os.FileInfo, error) {
+	checkFilepath, err := evalSymlinks(path, maxDepth)
+	if err != nil {
+		return nil, err
+	}
 	if path != checkFilepath {
 		fi, err := os.Lstat(checkFilepath)
 		if err != nil {
@@ -344,16 +1794,94 @@ func resolveSymlink(fileinfo os.FileInfo, path string) (os.FileInfo, error) {
 		return fi, nil
 	}
 
-	return fileinfo, err
+	return fileinfo, nil
+}
+
+// sortedPaths returns the paths of the files found on disk in the configured processing order.
+func (fr *FileReader) sortedPaths(filesOnDisk map[string]os.FileInfo) []string {
+	paths := make([]string, 0, len(filesOnDisk))
+	for path := range filesOnDisk {
+		paths = append(paths, path)
+	}
+
+	if fr.FileOrder == fileOrderDesc {
+		sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	} else {
+		sort.Strings(paths)
+	}
+	return paths
+}
+
+// walkFiles collects the dashboard files found under resolvedPath, keeping only those matching the path
+// pattern when the path of the provider is a glob, or only the listed ones when the files option is set.
+func (fr *FileReader) walkFiles(resolvedPath string, filesOnDisk map[string]os.FileInfo) error {
+	var err error
+	if len(fr.Files) > 0 {
+		err = fr.collectListedFiles(resolvedPath, filesOnDisk)
+	} else if !fr.FollowSymlinkedDirs {
+		err = filepath.Walk(resolvedPath, createWalkFn(filesOnDisk, fr.walkFilter()))
+	} else {
+		err = fr.walkFollowingSymlinks(resolvedPath, resolvedPath, map[string]struct{}{}, filesOnDisk)
+	}
+	if err != nil {
+		return err
+	}
+
+	if fr.PathPattern != "" {
+		fr.filterGlobMatches(resolvedPath, filesOnDisk)
+	}
+	fr.removeAuxiliaryFiles(resolvedPath, filesOnDisk)
+	return fr.expandJSONLines(filesOnDisk)
+}
+
+// walkFollowingSymlinks walks root like filepath.Walk but also descends into symlinked directories. Files are
+// recorded under their path through the symlink, rooted at logicalRoot. Every directory is visited at most once,
+// which prevents symlink cycles from looping forever.
+func (fr *FileReader) walkFollowingSymlinks(root, logicalRoot string, visited map[string]struct{}, filesOnDisk map[string]os.FileInfo) error {
+	realRoot, err := evalSymlinks(root, fr.MaxSymlinkDepth)
+	if err != nil {
+		return err
+	}
+
+	if _, seen := visited[realRoot]; seen {
+		fr.log.Warn("skipping already visited directory, symlink cycle detected", "path", logicalRoot, "target", realRoot)
+		return nil
+	}
+	visited[realRoot] = struct{}{}
+
+	filter := fr.walkFilter()
+	walkFn := createWalkFn(filesOnDisk, filter)
+	return filepath.Walk(realRoot, func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		logicalPath := logicalRoot
+		if rel, err := filepath.Rel(realRoot, path); err == nil && rel != "." {
+			logicalPath = filepath.Join(logicalRoot, rel)
+		}
+
+		if fileInfo.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Stat(path)
+			if err == nil && target.IsDir() {
+				if filter.skipDir(fileInfo.Name()) {
+					return nil
+				}
+				return fr.walkFollowingSymlinks(path, logicalPath, visited, filesOnDisk)
+			}
+		}
+
+		return walkFn(logicalPath, fileInfo, nil)
+	})
 }
 
-func createWalkFn(filesOnDisk map[string]os.FileInfo) filepath.WalkFunc {
+func createWalkFn(filesOnDisk map[string]os.FileInfo, filter walkFilter) filepath.WalkFunc {
 	return func(path string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		isValid, err := validateWalkablePath(fileInfo)
+		isValid, err := validateWalkablePath(fileInfo, filter)
 		if !isValid {
 			return err
 		}
@@ -363,51 +1891,135 @@ func createWalkFn(filesOnDisk map[string]os.FileInfo) filepath.WalkFunc {
 	}
 }
 
-func validateWalkablePath(fileInfo os.FileInfo) (bool, error) {
+func validateWalkablePath(fileInfo os.FileInfo, filter walkFilter) (bool, error) {
 	if fileInfo.IsDir() {
-		if strings.HasPrefix(fileInfo.Name(), ".") {
+		if filter.skipDir(fileInfo.Name()) {
 			return false, filepath.SkipDir
 		}
 		return false, nil
 	}
 
-	if !strings.HasSuffix(fileInfo.Name(), ".json") {
+	if !filter.hasFileExtension(fileInfo.Name()) || isSidecar(fileInfo.Name()) || isFragment(fileInfo.Name()) {
 		return false, nil
 	}
 
 	return true, nil
 }
 
+// gzipDashboardSuffix is the suffix of gzip compressed dashboard files, which are decompressed transparently.
+const gzipDashboardSuffix = ".json.gz"
+
+// defaultFileExtensions are the suffixes of the files picked up unless the fileExtensions option is set.
+var defaultFileExtensions = []string{".json", gzipDashboardSuffix, jsonLinesSuffix}
+
+func hasFileExtension(name string, fileExtensions []string) bool {
+	for _, extension := range fileExtensions {
+		if strings.HasSuffix(name, extension) {
+			return true
+		}
+	}
+	return false
+}
+
 type dashboardJSONFile struct {
 	dashboard    *dashboards.SaveDashboardDTO
 	checkSum     string
 	lastModified time.Time
+	// deleted is set for files marking their dashboard as deleted, in which case dashboard is nil.
+	deleted bool
+	// disabled is set for dashboards with `"enabled": false`, which are kept out of the database until enabled.
+	disabled bool
+	// kind is the kind of the file, see dashboardKind.
+	kind string
+	// unsupported is set for dashboards whose version range doesn't include the running Grafana version.
+	unsupported bool
+	// includes is set for dashboards including other files.
+	includes bool
 }
 
-func (fr *FileReader) readDashboardFromFile(path string, lastModified time.Time, folderID int64) (*dashboardJSONFile, error) {
-	// nolint:gosec
-	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
-	reader, err := os.Open(path)
+// isDeleteMarker reports whether the file content requests the deletion of the dashboard, either as a
+// bare `{"__deleted": true}` marker or through a `deleted` flag on the dashboard itself.
+func isDeleteMarker(data *simplejson.Json) bool {
+	return data.Get("__deleted").MustBool() || data.Get("deleted").MustBool()
+}
+
+func (fr *FileReader) readDashboardFromFile(path string, fileInfo os.FileInfo, folderID int64) (*dashboardJSONFile, error) {
+	lastModified := fileInfo.ModTime()
+
+	data, checkSum, cached := fr.fileCache.get(path, fileInfo)
+	if !cached {
+		line, isLine := fileInfo.(*jsonLineInfo)
+		file := path
+		if isLine {
+			file = line.file
+		}
+		// verified files are cached, so they are only verified again once they change
+		if err := fr.verifyChecksumSidecar(file); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if isLine {
+			data, checkSum, err = fr.parseDashboardContent(line.content)
+		} else {
+			data, checkSum, err = fr.parseDashboardFile(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		fr.fileCache.set(path, fileInfo, data, checkSum)
+	}
+
+	return fr.dashboardFromContent(path, data, checkSum, lastModified, folderID)
+}
+
+// dashboardFromContent turns the parsed content of the dashboard file at path into the dashboard to provision,
+// resolving its includes and applying the transformers and injected tags of the provider.
+func (fr *FileReader) dashboardFromContent(path string, data *simplejson.Json, checkSum string, lastModified time.Time,
+	folderID int64) (*dashboardJSONFile, error) {
+	// includes are resolved on every read since the cache only tracks the dashboard file itself
+	fileCheckSum := checkSum
+	data, checkSum, err := fr.resolveIncludes(path, data, checkSum)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := reader.Close(); err != nil {
-			fr.log.Warn("Failed to close file", "path", path, "err", err)
+	includes := checkSum != fileCheckSum
+
+	if isDeleteMarker(data) {
+		return &dashboardJSONFile{
+			checkSum:     checkSum,
+			lastModified: lastModified,
+			deleted:      true,
+		}, nil
+	}
+
+	if len(fr.Transformers) > 0 {
+		data, checkSum, err = fr.transform(data)
+		if err != nil {
+			return nil, err
 		}
-	}()
+	}
 
-	all, err := ioutil.ReadAll(reader)
+	checkSum, err = fr.withAuxiliaryChecksum(checkSum)
 	if err != nil {
 		return nil, err
 	}
 
-	checkSum, err := util.Md5SumString(string(all))
+	if err := validateDashboard(data); err != nil {
+		return nil, err
+	}
+
+	checkSum, err = fr.injectTags(data, checkSum)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := simplejson.NewJson(all)
+	checkSum, err = fr.prefixUID(data, checkSum)
+	if err != nil {
+		return nil, err
+	}
+
+	supported, err := fr.supportsGrafanaVersion(data)
 	if err != nil {
 		return nil, err
 	}
@@ -421,26 +2033,192 @@ func (fr *FileReader) readDashboardFromFile(path string, lastModified time.Time,
 		dashboard:    dash,
 		checkSum:     checkSum,
 		lastModified: lastModified,
+		disabled:     isDisabled(data),
+		kind:         dashboardKind(data),
+		unsupported:  !supported,
+		includes:     includes,
 	}, nil
 }
 
+// transform applies the transformers to a copy of data, leaving the cached file content untouched. The returned
+// checksum is computed from the transformed content, so that changing a transformer re-provisions the dashboards.
+func (fr *FileReader) transform(data *simplejson.Json) (*simplejson.Json, string, error) {
+	raw, err := data.Encode()
+	if err != nil {
+		return nil, "", err
+	}
+	transformed, err := simplejson.NewJson(raw)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i, transformer := range fr.Transformers {
+		if err := transformer(transformed); err != nil {
+			return nil, "", fmt.Errorf("%w: transformer %d: %v", ErrTransformFailed, i, err)
+		}
+	}
+
+	raw, err = transformed.Encode()
+	if err != nil {
+		return nil, "", err
+	}
+	checkSum, err := util.Md5SumString(string(raw))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return transformed, checkSum, nil
+}
+
+// validateDashboard checks that the parsed content has the minimum shape of a dashboard, so that files which
+// happen to be valid JSON don't end up as broken dashboards.
+func validateDashboard(data *simplejson.Json) error {
+	if _, err := data.Map(); err != nil {
+		return fmt.Errorf("%w: expected a JSON object", ErrInvalidDashboard)
+	}
+
+	if title, err := data.Get("title").String(); err != nil || strings.TrimSpace(title) == "" {
+		return fmt.Errorf("%w: missing title", ErrInvalidDashboard)
+	}
+
+	if uid, ok := data.CheckGet("uid"); ok && uid.Interface() != nil {
+		if _, err := uid.String(); err != nil {
+			return fmt.Errorf("%w: 'uid' must be a string", ErrInvalidDashboard)
+		}
+	}
+
+	for _, key := range []string{"panels", "tags"} {
+		if value, ok := data.CheckGet(key); ok && value.Interface() != nil {
+			if _, err := value.Array(); err != nil {
+				return fmt.Errorf("%w: '%s' must be an array", ErrInvalidDashboard, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isDisabled reports whether the dashboard explicitly opts out of provisioning with `"enabled": false`.
+func isDisabled(data *simplejson.Json) bool {
+	enabled, ok := data.CheckGet("enabled")
+	if !ok {
+		return false
+	}
+	value, err := enabled.Bool()
+	return err == nil && !value
+}
+
+// parseDashboardFile reads the file at path and returns its parsed content along with its checksum.
+func (fr *FileReader) parseDashboardFile(path string) (*simplejson.Json, string, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	reader, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			fr.log.Warn("Failed to close file", "path", path, "err", err)
+		}
+	}()
+
+	var content io.Reader = reader
+	if strings.HasSuffix(strings.ToLower(path), gzipDashboardSuffix) {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, "", err
+		}
+		defer func() {
+			_ = gzipReader.Close()
+		}()
+		// the size limit and the checksum apply to the decompressed content, so that recompressing
+		// a file doesn't trigger an update
+		content = gzipReader
+	}
+
+	// normalizing the input and checking it strictly need the whole content, so those files are always read at once
+	if fr.StreamParse && !fr.NormalizeInput && !fr.StrictJSON {
+		return fr.streamDashboardContent(content)
+	}
+
+	// read one byte more than allowed so that files exceeding the limit can be detected
+	all, err := ioutil.ReadAll(io.LimitReader(content, fr.MaxFileSizeBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	return fr.parseDashboardContent(all)
+}
+
+// parseDashboardContent parses the content of a dashboard file, or of a line of a JSON Lines file, and returns it
+// along with its checksum.
+func (fr *FileReader) parseDashboardContent(all []byte) (*simplejson.Json, string, error) {
+	if int64(len(all)) > fr.MaxFileSizeBytes {
+		return nil, "", fmt.Errorf("%w: exceeds %d bytes", ErrFileTooLarge, fr.MaxFileSizeBytes)
+	}
+	if fr.NormalizeInput {
+		all = normalizeDashboardInput(all)
+	}
+
+	data, err := simplejson.NewJson(all)
+	if err != nil {
+		return nil, "", err
+	}
+	if fr.StrictJSON {
+		if err := checkStrictJSON(all); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// the canonical form has sorted keys and no whitespace, so that reformatting a file doesn't change its checksum
+	if fr.CanonicalChecksum {
+		all, err = data.Encode()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	checkSum, err := util.Md5SumString(string(all))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, checkSum, nil
+}
+
+// utf8BOM is the byte order mark some editors write at the start of UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// normalizeDashboardInput strips the byte order mark and the trailing whitespace of the content of a dashboard file,
+// so that they neither fail the parsing nor change the checksum.
+func normalizeDashboardInput(content []byte) []byte {
+	return bytes.TrimRightFunc(bytes.TrimPrefix(content, utf8BOM), unicode.IsSpace)
+}
+
 func (fr *FileReader) resolvedPath() string {
-	if _, err := os.Stat(fr.Path); os.IsNotExist(err) {
+	configuredPath := fr.Path
+	// a relative path is relative to the config file of the provider rather than to the working directory
+	if !filepath.IsAbs(configuredPath) && fr.Cfg.dir != "" {
+		configuredPath = filepath.Join(fr.Cfg.dir, configuredPath)
+	}
+
+	if _, err := os.Stat(configuredPath); os.IsNotExist(err) {
 		fr.log.Error("Cannot read directory", "error", err)
 	}
 
-	path, err := filepath.Abs(fr.Path)
+	path, err := filepath.Abs(configuredPath)
 	if err != nil {
 		fr.log.Error("Could not create absolute path", "path", fr.Path, "error", err)
 	}
 
-	path, err = filepath.EvalSymlinks(path)
-	if err != nil {
-		fr.log.Error("Failed to read content of symlinked path", "path", fr.Path, "error", err)
+	if fr.ResolveSymlinks {
+		path, err = evalSymlinks(path, fr.MaxSymlinkDepth)
+		if err != nil {
+			fr.log.Error("Failed to read content of symlinked path", "path", fr.Path, "error", err)
+		}
 	}
 
 	if path == "" {
-		path = fr.Path
+		path = configuredPath
 		fr.log.Info("falling back to original path due to EvalSymlink/Abs failure")
 	}
 	return path
@@ -456,6 +2234,7 @@ func (fr *FileReader) getUsageTracker() *usageTracker {
 type provisioningMetadata struct {
 	uid      string
 	identity dashboardIdentity
+	path     string
 }
 
 type dashboardIdentity struct {
@@ -471,19 +2250,66 @@ func newUsageTracker() *usageTracker {
 	return &usageTracker{
 		uidUsage:   map[string]uint8{},
 		titleUsage: map[dashboardIdentity]uint8{},
+		titlePaths: map[dashboardIdentity][]string{},
 	}
 }
 
 type usageTracker struct {
 	uidUsage   map[string]uint8
 	titleUsage map[dashboardIdentity]uint8
+	titlePaths map[dashboardIdentity][]string
+	files      int
 }
 
 func (t *usageTracker) track(pm provisioningMetadata) {
+	t.files++
 	if len(pm.uid) > 0 {
 		t.uidUsage[pm.uid]++
 	}
 	if pm.identity.Exists() {
 		t.titleUsage[pm.identity]++
+		if pm.path != "" {
+			t.titlePaths[pm.identity] = append(t.titlePaths[pm.identity], pm.path)
+		}
+	}
+}
+
+// disambiguateTitle appends a numeric suffix to the dashboard title if the title is already used
+// in the same folder by a dashboard tracked earlier in the run.
+func (t *usageTracker) disambiguateTitle(dash *models.Dashboard) {
+	identity := dashboardIdentity{title: dash.Title, folderID: dash.FolderId}
+	if t.titleUsage[identity] == 0 {
+		return
+	}
+
+	for i := 2; ; i++ {
+		identity.title = fmt.Sprintf("%s (%d)", dash.Title, i)
+		if t.titleUsage[identity] == 0 {
+			break
+		}
+	}
+
+	dash.Title = identity.title
+	dash.Data.Set("title", identity.title)
+	dash.UpdateSlug()
+}
+
+// checkDuplicateTitles returns an error listing the files of every dashboard sharing its title with
+// another one in the same folder.
+func (t *usageTracker) checkDuplicateTitles() error {
+	var conflicts []string
+	for identity, times := range t.titleUsage {
+		if times > 1 {
+			paths := append([]string{}, t.titlePaths[identity]...)
+			sort.Strings(paths)
+			conflicts = append(conflicts, fmt.Sprintf("%q in folder %d: %s", identity.title, identity.folderID, strings.Join(paths, ", ")))
+		}
 	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	sort.Strings(conflicts)
+	return fmt.Errorf("%w: %s", ErrDuplicateTitle, strings.Join(conflicts, "; "))
 }
@@ -4,6 +4,7 @@
 package dashboards
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -36,3 +37,63 @@ func TestProvisionedSymlinkedFolder(t *testing.T) {
 	resolvedPath := reader.resolvedPath()
 	assert.Equal(t, want, resolvedPath)
 }
+
+func TestProvisionedSymlinkedFolderWithoutResolution(t *testing.T) {
+	cfg := &config{
+		Name:    "Default",
+		Type:    "file",
+		OrgID:   1,
+		Options: map[string]interface{}{"path": symlinkedFolder, "resolveSymlinks": false},
+	}
+
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.NoError(t, err)
+
+	want, err := filepath.Abs(symlinkedFolder)
+	require.NoError(t, err)
+	assert.Equal(t, want, reader.resolvedPath())
+
+	cfg.Options["followSymlinkedDirs"] = true
+	_, err = NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.Error(t, err)
+}
+
+func TestWalkFollowingSymlinkedDirs(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared")
+	root := filepath.Join(dir, "root")
+	require.NoError(t, os.MkdirAll(shared, 0750))
+	require.NoError(t, os.MkdirAll(root, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "dashboard.json"), []byte(`{"title": "Shared"}`), 0600))
+	require.NoError(t, os.Symlink(shared, filepath.Join(root, "linked")))
+	// creates a cycle back to the provisioning root
+	require.NoError(t, os.Symlink(root, filepath.Join(shared, "back")))
+
+	cfg := &config{
+		Name:    "Default",
+		Type:    "file",
+		OrgID:   1,
+		Options: map[string]interface{}{"path": root},
+	}
+
+	t.Run("should not follow symlinked directories by default", func(t *testing.T) {
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+
+		files := map[string]os.FileInfo{}
+		require.NoError(t, reader.walkFiles(reader.resolvedPath(), files))
+		assert.Empty(t, files)
+	})
+
+	t.Run("should follow symlinked directories and stop at cycles", func(t *testing.T) {
+		cfg.Options["followSymlinkedDirs"] = true
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+
+		resolvedPath := reader.resolvedPath()
+		files := map[string]os.FileInfo{}
+		require.NoError(t, reader.walkFiles(resolvedPath, files))
+		require.Len(t, files, 1)
+		assert.Contains(t, files, filepath.Join(resolvedPath, "linked", "dashboard.json"))
+	})
+}
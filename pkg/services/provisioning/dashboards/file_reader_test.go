@@ -1,10 +1,14 @@
 package dashboards
 
 import (
+	"compress/gzip"
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/dashboards"
@@ -25,6 +30,9 @@ const (
 	containingID              = "testdata/test-dashboards/containing-id"
 	unprovision               = "testdata/test-dashboards/unprovision"
 	foldersFromFilesStructure = "testdata/test-dashboards/folders-from-files-structure"
+	nestedFoldersStructure    = "testdata/test-dashboards/nested-folders-from-files-structure"
+	deleteMarker              = "testdata/test-dashboards/delete-marker"
+	folderOverride            = "testdata/test-dashboards/folder-override"
 	configName                = "default"
 )
 
@@ -88,6 +96,30 @@ func TestCreatingNewDashboardFileReader(t *testing.T) {
 		resolvedPath := reader.resolvedPath()
 		require.True(t, filepath.IsAbs(resolvedPath))
 	})
+
+	t.Run("using path relative to the config file", func(t *testing.T) {
+		dir, err := filepath.Abs("testdata")
+		require.NoError(t, err)
+		cfg := setup()
+		cfg.Options["path"] = filepath.Join("test-dashboards", "folder-one")
+		cfg.dir = dir
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, filepath.Join(dir, "test-dashboards", "folder-one"), reader.resolvedPath())
+	})
+
+	t.Run("using absolute path with a config file", func(t *testing.T) {
+		fullPath, err := filepath.Abs(defaultDashboards)
+		require.NoError(t, err)
+		cfg := setup()
+		cfg.Options["path"] = fullPath
+		cfg.dir = t.TempDir()
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, fullPath, reader.resolvedPath())
+	})
 }
 
 func TestDashboardFileReader(t *testing.T) {
@@ -125,117 +157,839 @@ func TestDashboardFileReader(t *testing.T) {
 			require.NoError(t, err)
 		})
 
-		t.Run("Can read default dashboard and replace old version in database", func(t *testing.T) {
+		t.Run("Can read default dashboard and replace old version in database", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+
+			inserted := 0
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Once().
+				Run(func(args mock.Arguments) {
+					inserted++
+				})
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, inserted, 1)
+		})
+
+		t.Run("Dashboard with older timestamp and the same checksum will not replace imported dashboard", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			absPath, err := filepath.Abs(oneDashboard + "/dashboard1.json")
+			require.NoError(t, err)
+			stat, err := os.Stat(oneDashboard + "/dashboard1.json")
+			require.NoError(t, err)
+			file, err := os.Open(filepath.Clean(absPath))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = file.Close()
+			})
+
+			checksum, err := util.Md5Sum(file)
+			require.NoError(t, err)
+
+			provisionedDashboard := []*models.DashboardProvisioning{
+				{
+					Name:       "Default",
+					ExternalId: absPath,
+					Updated:    stat.ModTime().AddDate(0, 0, +1).Unix(),
+					CheckSum:   checksum,
+				},
+			}
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Dashboard with older timestamp and different checksum will replace imported dashboard", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			absPath, err := filepath.Abs(oneDashboard + "/dashboard1.json")
+			require.NoError(t, err)
+			stat, err := os.Stat(oneDashboard + "/dashboard1.json")
+			require.NoError(t, err)
+
+			provisionedDashboard := []*models.DashboardProvisioning{
+				{
+					Name:       "Default",
+					ExternalId: absPath,
+					Updated:    stat.ModTime().AddDate(0, 0, +1).Unix(),
+					CheckSum:   "fakechecksum",
+				},
+			}
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Dashboard with newer timestamp and the same checksum will not replace imported dashboard", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			absPath, err := filepath.Abs(oneDashboard + "/dashboard1.json")
+			require.NoError(t, err)
+			stat, err := os.Stat(oneDashboard + "/dashboard1.json")
+			require.NoError(t, err)
+			file, err := os.Open(filepath.Clean(absPath))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = file.Close()
+			})
+
+			checksum, err := util.Md5Sum(file)
+			require.NoError(t, err)
+
+			provisionedDashboard := []*models.DashboardProvisioning{
+				{
+					Name:       "Default",
+					ExternalId: absPath,
+					Updated:    stat.ModTime().AddDate(0, 0, -1).Unix(),
+					CheckSum:   checksum,
+				},
+			}
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Dashboard with newer timestamp and different checksum should replace imported dashboard", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			absPath, err := filepath.Abs(oneDashboard + "/dashboard1.json")
+			require.NoError(t, err)
+			stat, err := os.Stat(oneDashboard + "/dashboard1.json")
+			require.NoError(t, err)
+
+			provisionedDashboard := []*models.DashboardProvisioning{
+				{
+					Name:       "Default",
+					ExternalId: absPath,
+					Updated:    stat.ModTime().AddDate(0, 0, -1).Unix(),
+					CheckSum:   "fakechecksum",
+				},
+			}
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Overrides id from dashboard.json files", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = containingID
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Get folder from files structure", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = foldersFromFilesStructure
+			cfg.Options["foldersFromFilesStructure"] = true
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Times(2)
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Times(3)
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Root files should go into the root folder", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = foldersFromFilesStructure
+			cfg.Options["foldersFromFilesStructure"] = true
+			cfg.Options["rootFolder"] = "Root"
+
+			var folderTitles []string
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Times(3).
+				Run(func(args mock.Arguments) {
+					folderTitles = append(folderTitles, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Title)
+				})
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Times(3)
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"Root", "folderOne", "folderTwo"}, folderTitles)
+		})
+
+		t.Run("Folders from files structure should go into the folderUid folder", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = foldersFromFilesStructure
+			cfg.Options["foldersFromFilesStructure"] = true
+			cfg.Folder = "Parent"
+			cfg.FolderUID = "parent-uid"
+
+			var mux sync.Mutex
+			folderUIDs := map[string]string{}
+			folderParents := map[string]int64{}
+			dashboardFolders := map[string]int64{}
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+				Return(&models.Dashboard{Id: 10}, nil).Times(3).
+				Run(func(args mock.Arguments) {
+					mux.Lock()
+					defer mux.Unlock()
+					dash := args.Get(1).(*dashboards.SaveDashboardDTO)
+					folderUIDs[dash.Dashboard.Title] = dash.Dashboard.Uid
+					folderParents[dash.Dashboard.Title] = dash.Dashboard.FolderId
+				})
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Times(3).
+				Run(func(args mock.Arguments) {
+					mux.Lock()
+					defer mux.Unlock()
+					dash := args.Get(1).(*dashboards.SaveDashboardDTO)
+					dashboardFolders[dash.Dashboard.Title] = dash.Dashboard.FolderId
+				})
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.NoError(t, err)
+			reader.dashboardProvisioningService = fakeService
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"Parent": "parent-uid", "folderOne": "", "folderTwo": ""}, folderUIDs)
+			require.Equal(t, map[string]int64{"Parent": 0, "folderOne": 10, "folderTwo": 10}, folderParents)
+			require.Equal(t, int64(10), dashboardFolders["RootDashboard"])
+		})
+
+		t.Run("Get nested folders from files structure", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = nestedFoldersStructure
+			cfg.Options["foldersFromFilesStructure"] = true
+			cfg.Options["nestedFolders"] = true
+
+			var folderParents []int64
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+				Return(&models.Dashboard{Id: 11}, nil).Once().
+				Run(func(args mock.Arguments) {
+					folderParents = append(folderParents, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.FolderId)
+				})
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+				Return(&models.Dashboard{Id: 12}, nil).Once().
+				Run(func(args mock.Arguments) {
+					folderParents = append(folderParents, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.FolderId)
+				})
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Once().
+				Run(func(args mock.Arguments) {
+					require.Equal(t, int64(12), args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.FolderId)
+				})
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, []int64{0, 11}, folderParents)
+		})
+
+		t.Run("Get organization from files structure", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = foldersFromFilesStructure
+			cfg.Options["foldersFromFilesStructure"] = true
+			cfg.Options["orgIdFromPath"] = true
+			cfg.Options["orgIdMapping"] = map[string]interface{}{"folderOne": 2}
+
+			var mux sync.Mutex
+			folderOrgs := map[string]int64{}
+			dashboardOrgs := map[string]int64{}
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Times(2).
+				Run(func(args mock.Arguments) {
+					mux.Lock()
+					defer mux.Unlock()
+					dash := args.Get(1).(*dashboards.SaveDashboardDTO)
+					folderOrgs[dash.Dashboard.Title] = dash.OrgId
+				})
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Times(3).
+				Run(func(args mock.Arguments) {
+					mux.Lock()
+					defer mux.Unlock()
+					dash := args.Get(1).(*dashboards.SaveDashboardDTO)
+					dashboardOrgs[dash.Dashboard.Title] = dash.Dashboard.OrgId
+				})
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, map[string]int64{"folderOne": 2, "folderTwo": 1}, folderOrgs)
+			require.Equal(t, map[string]int64{"Grafana1": 2, "Grafana2": 1, "RootDashboard": 1}, dashboardOrgs)
+		})
+
+		t.Run("orgIdFromPath requires foldersFromFilesStructure", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = foldersFromFilesStructure
+			cfg.Options["orgIdFromPath"] = true
+
+			_, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.Error(t, err)
+		})
+
+		t.Run("Dashboard older than ignoreOlderThan will not be saved", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			cfg.Options["ignoreOlderThan"] = "1ns"
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Invalid ignoreOlderThan should return error", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			cfg.Options["ignoreOlderThan"] = "yesterday"
+
+			_, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.Error(t, err)
+		})
+
+		t.Run("Duplicate titles should fail the run if onDuplicateTitle = error", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = twoDashboardsWithUID
+			cfg.Options["onDuplicateTitle"] = "error"
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			// the second file shares the uid of the first one, so it isn't saved
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.ErrorIs(t, err, ErrDuplicateTitle)
+			require.Contains(t, err.Error(), "dashboard1.json")
+			require.Contains(t, err.Error(), "dashboard2.json")
+		})
+
+		t.Run("Duplicate titles should be suffixed if onDuplicateTitle = suffix", func(t *testing.T) {
+			setup()
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"uid": "a", "title": "Grafana"}`), 0600))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"uid": "b", "title": "Grafana"}`), 0600))
+			cfg.Options["path"] = dir
+			cfg.Options["onDuplicateTitle"] = "suffix"
+
+			var titles []string
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Times(2).
+				Run(func(args mock.Arguments) {
+					titles = append(titles, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Title)
+				})
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, []string{"Grafana", "Grafana (2)"}, titles)
+		})
+
+		t.Run("Files should be processed in a stable order so that the first file using a uid wins", func(t *testing.T) {
+			for _, order := range []string{"asc", "desc"} {
+				setup()
+				cfg.Options["path"] = twoDashboardsWithUID
+				cfg.Options["fileOrder"] = order
+
+				var saved []string
+				fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+				fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+					Return(&models.Dashboard{}, nil).Once().
+					Run(func(args mock.Arguments) {
+						saved = append(saved, filepath.Base(args.Get(2).(*models.DashboardProvisioning).ExternalId))
+					})
+
+				reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+				reader.dashboardProvisioningService = fakeService
+				require.NoError(t, err)
+
+				err = reader.walkDisk(context.Background())
+				require.NoError(t, err)
+				if order == "asc" {
+					require.Equal(t, []string{"dashboard1.json"}, saved)
+				} else {
+					require.Equal(t, []string{"dashboard2.json"}, saved)
+				}
+			}
+
+			setup()
+			cfg.Options["fileOrder"] = "random"
+			_, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.Error(t, err)
+		})
+
+		t.Run("Dashboard file larger than maxFileSizeBytes will not be saved", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			cfg.Options["maxFileSizeBytes"] = 100
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+
+			absPath, err := filepath.Abs(oneDashboard + "/dashboard1.json")
+			require.NoError(t, err)
+			stat, err := os.Stat(absPath)
+			require.NoError(t, err)
+			_, err = reader.readDashboardFromFile(absPath, stat, 0)
+			require.ErrorIs(t, err, ErrFileTooLarge)
+		})
+
+		t.Run("Dashboard marked as deleted should be deleted once", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = deleteMarker
+			absPath, err := filepath.Abs(deleteMarker + "/dashboard1.json")
+			require.NoError(t, err)
+
+			provisionedDashboard := []*models.DashboardProvisioning{
+				{DashboardId: 1, Name: configName, ExternalId: absPath},
+			}
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
+			fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(1), int64(1)).Return(nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Dashboard files can override their folder", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = folderOverride
+
+			var folders []string
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+				Return(&models.Dashboard{Id: 1}, nil).Times(2).
+				Run(func(args mock.Arguments) {
+					folders = append(folders, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Title)
+				})
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Times(2)
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"Embedded", "Sidecar"}, folders)
+		})
+
+		t.Run("Concurrent runs should not overlap", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+
+			var running, maxRunning int32
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Twice()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Twice().
+				Run(func(args mock.Arguments) {
+					current := atomic.AddInt32(&running, 1)
+					if current > atomic.LoadInt32(&maxRunning) {
+						atomic.StoreInt32(&maxRunning, current)
+					}
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+				})
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 2; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					assert.NoError(t, reader.walkDisk(context.Background()))
+				}()
+			}
+			wg.Wait()
+
+			require.Equal(t, int32(1), atomic.LoadInt32(&maxRunning))
+		})
+
+		t.Run("Gzip compressed dashboards should be provisioned", func(t *testing.T) {
+			setup()
+			raw, err := os.ReadFile(filepath.Join(oneDashboard, "dashboard1.json"))
+			require.NoError(t, err)
+
+			dir := t.TempDir()
+			file, err := os.Create(filepath.Join(dir, "dashboard1.json.gz"))
+			require.NoError(t, err)
+			gzipWriter := gzip.NewWriter(file)
+			_, err = gzipWriter.Write(raw)
+			require.NoError(t, err)
+			require.NoError(t, gzipWriter.Close())
+			require.NoError(t, file.Close())
+
+			checkSum, err := util.Md5SumString(string(raw))
+			require.NoError(t, err)
+
+			cfg.Options["path"] = dir
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Once().
+				Run(func(args mock.Arguments) {
+					require.Equal(t, checkSum, args.Get(2).(*models.DashboardProvisioning).CheckSum)
+				})
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("Startup delay should defer the first run to polling", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			cfg.Options["startupDelaySeconds"] = 2
+			cfg.Options["startupJitterSeconds"] = 3
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.NoError(t, err)
+			reader.dashboardProvisioningService = fakeService
+
+			for i := 0; i < 10; i++ {
+				delay := reader.startupDelay()
+				require.GreaterOrEqual(t, delay, 2*time.Second)
+				require.LessOrEqual(t, delay, 5*time.Second)
+			}
+
+			provisioner := &Provisioner{
+				log:                logger,
+				fileReaders:        []*FileReader{reader},
+				duplicateValidator: newDuplicateValidator(logger, []*FileReader{reader}),
+			}
+			require.NoError(t, provisioner.Provision(context.Background()))
+		})
+
+		t.Run("Empty folders created by the provisioner should be pruned", func(t *testing.T) {
+			setup()
+			raw, err := os.ReadFile(filepath.Join(oneDashboard, "dashboard1.json"))
+			require.NoError(t, err)
+
+			dir := t.TempDir()
+			dashboardPath := filepath.Join(dir, "team", "dashboard1.json")
+			require.NoError(t, os.MkdirAll(filepath.Dir(dashboardPath), 0750))
+			require.NoError(t, os.WriteFile(dashboardPath, raw, 0600))
+
+			cfg.Options["path"] = dir
+			cfg.Options["foldersFromFilesStructure"] = true
+			cfg.Options["pruneEmptyFolders"] = true
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 21}, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 22}, nil).Once()
+			require.NoError(t, reader.walkDisk(context.Background()))
+
+			resolvedPath, err := filepath.EvalSymlinks(dashboardPath)
+			require.NoError(t, err)
+			require.NoError(t, os.Remove(dashboardPath))
+
+			fakeService.On("GetProvisionedDashboardData", configName).
+				Return([]*models.DashboardProvisioning{{DashboardId: 22, Name: configName, ExternalId: resolvedPath}}, nil).Once()
+			fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(22), int64(1)).Return(nil).Once()
+			fakeService.On("DeleteEmptyFolderForProvisionedDashboards", mock.Anything, int64(21), int64(1)).Return(true, nil).Once()
+			require.NoError(t, reader.walkDisk(context.Background()))
+			require.Empty(t, reader.createdFolders)
+		})
+
+		t.Run("Disabled dashboards should be removed but not treated as missing", func(t *testing.T) {
+			setup()
+			dir := t.TempDir()
+			dashboardPath := filepath.Join(dir, "staged.json")
+			require.NoError(t, os.WriteFile(dashboardPath, []byte(`{"title": "Staged", "enabled": false}`), 0600))
+			resolvedPath, err := filepath.EvalSymlinks(dashboardPath)
+			require.NoError(t, err)
+
+			cfg.Options["path"] = dir
+			fakeService.On("GetProvisionedDashboardData", configName).
+				Return([]*models.DashboardProvisioning{{DashboardId: 5, Name: configName, ExternalId: resolvedPath}}, nil).Once()
+			fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(5), int64(1)).Return(nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, reader.getUsageTracker().files)
+		})
+
+		t.Run("Folder lookups should be cached within a run", func(t *testing.T) {
+			setup()
+			dir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "team"), 0750))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "team", "one.json"), []byte(`{"title": "One"}`), 0600))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "team", "two.json"), []byte(`{"title": "Two"}`), 0600))
+
+			cfg.Options["path"] = dir
+			cfg.Options["foldersFromFilesStructure"] = true
+
+			store := &countingDashboardStore{}
+			reader, err := NewDashboardFileReader(cfg, logger, nil, store)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 31}, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Times(2)
+			require.NoError(t, reader.walkDisk(context.Background()))
+			require.Equal(t, 1, store.calls)
+
+			// the cache does not outlive a run, so renamed folders are looked up again
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 31}, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Times(2)
+			require.NoError(t, reader.walkDisk(context.Background()))
+			require.Equal(t, 2, store.calls)
+		})
+
+		t.Run("Folder lookups should wait for a free slot with maxConcurrentFolders", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = defaultDashboards
+			cfg.Options["maxConcurrentFolders"] = 1
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, &fakeDashboardStore{})
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+			require.Equal(t, 1, reader.MaxConcurrentFolders)
+
+			// another lookup holds the only slot
+			require.NoError(t, reader.folderSlots.acquire(context.Background()))
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			_, err = reader.getOrCreateFolderID(ctx, cfg, fakeService, "team")
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			reader.folderSlots.release()
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 32}, nil).Once()
+			folderID, err := reader.getOrCreateFolderID(context.Background(), cfg, fakeService, "team")
+			require.NoError(t, err)
+			require.Equal(t, int64(32), folderID)
+			require.Empty(t, reader.folderSlots)
+		})
+
+		t.Run("Negative maxConcurrentFolders should return error", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = defaultDashboards
+			cfg.Options["maxConcurrentFolders"] = -1
+
+			_, err := NewDashboardFileReader(cfg, logger, nil, &fakeDashboardStore{})
+			require.Error(t, err)
+		})
+
+		t.Run("Transformers should modify dashboards before they are saved", func(t *testing.T) {
 			setup()
 			cfg.Options["path"] = oneDashboard
 
-			inserted := 0
 			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
 			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
 				Return(&models.Dashboard{}, nil).Once().
 				Run(func(args mock.Arguments) {
-					inserted++
+					dash := args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard
+					require.Equal(t, []interface{}{"provisioned"}, dash.Data.Get("tags").MustArray())
 				})
 
 			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
 			reader.dashboardProvisioningService = fakeService
 			require.NoError(t, err)
+			reader.Transformers = []DashboardTransformer{func(data *simplejson.Json) error {
+				data.Set("tags", []interface{}{"provisioned"})
+				return nil
+			}}
 
 			err = reader.walkDisk(context.Background())
 			require.NoError(t, err)
-
-			assert.Equal(t, inserted, 1)
 		})
 
-		t.Run("Dashboard with older timestamp and the same checksum will not replace imported dashboard", func(t *testing.T) {
+		t.Run("Failing transformers should skip the dashboard", func(t *testing.T) {
 			setup()
 			cfg.Options["path"] = oneDashboard
-			absPath, err := filepath.Abs(oneDashboard + "/dashboard1.json")
-			require.NoError(t, err)
-			stat, err := os.Stat(oneDashboard + "/dashboard1.json")
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
 			require.NoError(t, err)
-			file, err := os.Open(filepath.Clean(absPath))
+			reader.Transformers = []DashboardTransformer{func(data *simplejson.Json) error {
+				return errors.New("unsupported datasource")
+			}}
+
+			err = reader.walkDisk(context.Background())
 			require.NoError(t, err)
-			t.Cleanup(func() {
-				_ = file.Close()
-			})
 
-			checksum, err := util.Md5Sum(file)
+			stat, err := os.Stat(filepath.Join(oneDashboard, "dashboard1.json"))
 			require.NoError(t, err)
+			_, err = reader.readDashboardFromFile(filepath.Join(oneDashboard, "dashboard1.json"), stat, 0)
+			require.ErrorIs(t, err, ErrTransformFailed)
+		})
 
-			provisionedDashboard := []*models.DashboardProvisioning{
-				{
-					Name:       "Default",
-					ExternalId: absPath,
-					Updated:    stat.ModTime().AddDate(0, 0, +1).Unix(),
-					CheckSum:   checksum,
-				},
-			}
+		t.Run("Invalid dashboards should fail the run unless skipped", func(t *testing.T) {
+			setup()
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "panels.json"), []byte(`{"title": "Panels", "panels": {}}`), 0600))
 
-			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
+			cfg.Options["path"] = dir
+			cfg.Options["skipInvalid"] = false
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
 
 			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
 			reader.dashboardProvisioningService = fakeService
 			require.NoError(t, err)
 
+			err = reader.walkDisk(context.Background())
+			require.ErrorIs(t, err, ErrInvalidDashboard)
+
+			cfg.Options["skipInvalid"] = true
+			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+
+			reader, err = NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
 			err = reader.walkDisk(context.Background())
 			require.NoError(t, err)
 		})
 
-		t.Run("Dashboard with older timestamp and different checksum will replace imported dashboard", func(t *testing.T) {
+		t.Run("Poll interval should be jittered around the update interval", func(t *testing.T) {
 			setup()
 			cfg.Options["path"] = oneDashboard
-			absPath, err := filepath.Abs(oneDashboard + "/dashboard1.json")
-			require.NoError(t, err)
-			stat, err := os.Stat(oneDashboard + "/dashboard1.json")
+			cfg.Options["pollJitterFraction"] = 0.1
+			cfg.UpdateIntervalSeconds = 10
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
 			require.NoError(t, err)
 
-			provisionedDashboard := []*models.DashboardProvisioning{
-				{
-					Name:       "Default",
-					ExternalId: absPath,
-					Updated:    stat.ModTime().AddDate(0, 0, +1).Unix(),
-					CheckSum:   "fakechecksum",
-				},
+			for i := 0; i < 10; i++ {
+				interval := reader.pollInterval()
+				require.GreaterOrEqual(t, interval, 9*time.Second)
+				require.LessOrEqual(t, interval, 11*time.Second)
 			}
 
-			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
-			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+			cfg.Options["pollJitterFraction"] = 1.5
+			_, err = NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.Error(t, err)
+		})
+
+		t.Run("Writes should be throttled and stop on cancellation", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			cfg.Options["writesPerSecond"] = 0.001
 
 			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
-			reader.dashboardProvisioningService = fakeService
 			require.NoError(t, err)
+			require.NoError(t, reader.waitForWrite(context.Background()))
 
-			err = reader.walkDisk(context.Background())
-			require.NoError(t, err)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			require.Error(t, reader.waitForWrite(ctx))
+
+			cfg.Options["writesPerSecond"] = -1
+			_, err = NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.Error(t, err)
 		})
 
-		t.Run("Dashboard with newer timestamp and the same checksum will not replace imported dashboard", func(t *testing.T) {
+		t.Run("Renamed dashboard files should update the existing dashboard", func(t *testing.T) {
 			setup()
-			cfg.Options["path"] = oneDashboard
-			absPath, err := filepath.Abs(oneDashboard + "/dashboard1.json")
-			require.NoError(t, err)
-			stat, err := os.Stat(oneDashboard + "/dashboard1.json")
+			dir := t.TempDir()
+			raw := []byte(`{"title": "Renamed"}`)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), raw, 0600))
+			resolvedDir, err := filepath.EvalSymlinks(dir)
 			require.NoError(t, err)
-			file, err := os.Open(filepath.Clean(absPath))
-			require.NoError(t, err)
-			t.Cleanup(func() {
-				_ = file.Close()
-			})
-
-			checksum, err := util.Md5Sum(file)
+			checkSum, err := util.Md5SumString(string(raw))
 			require.NoError(t, err)
 
-			provisionedDashboard := []*models.DashboardProvisioning{
-				{
-					Name:       "Default",
-					ExternalId: absPath,
-					Updated:    stat.ModTime().AddDate(0, 0, -1).Unix(),
-					CheckSum:   checksum,
-				},
-			}
-
-			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
+			cfg.Options["path"] = dir
+			fakeService.On("GetProvisionedDashboardData", configName).Return([]*models.DashboardProvisioning{
+				{DashboardId: 7, Name: configName, ExternalId: filepath.Join(resolvedDir, "a.json"), CheckSum: checkSum},
+			}, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Once().
+				Run(func(args mock.Arguments) {
+					require.Equal(t, int64(7), args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Id)
+					require.Equal(t, filepath.Join(resolvedDir, "b.json"), args.Get(2).(*models.DashboardProvisioning).ExternalId)
+				})
 
 			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
 			reader.dashboardProvisioningService = fakeService
@@ -245,25 +999,28 @@ func TestDashboardFileReader(t *testing.T) {
 			require.NoError(t, err)
 		})
 
-		t.Run("Dashboard with newer timestamp and different checksum should replace imported dashboard", func(t *testing.T) {
+		t.Run("Renamed dashboard files should not revert UI edits when AllowUIUpdates = true", func(t *testing.T) {
 			setup()
-			cfg.Options["path"] = oneDashboard
-			absPath, err := filepath.Abs(oneDashboard + "/dashboard1.json")
+			dir := t.TempDir()
+			raw := []byte(`{"title": "Renamed"}`)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), raw, 0600))
+			resolvedDir, err := filepath.EvalSymlinks(dir)
 			require.NoError(t, err)
-			stat, err := os.Stat(oneDashboard + "/dashboard1.json")
+			checkSum, err := util.Md5SumString(string(raw))
 			require.NoError(t, err)
 
-			provisionedDashboard := []*models.DashboardProvisioning{
-				{
-					Name:       "Default",
-					ExternalId: absPath,
-					Updated:    stat.ModTime().AddDate(0, 0, -1).Unix(),
-					CheckSum:   "fakechecksum",
-				},
-			}
-
-			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
-			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+			cfg.Options["path"] = dir
+			cfg.AllowUIUpdates = true
+			fakeService := &dashboards.FakeDashboardProvisioning{}
+			defer fakeService.AssertExpectations(t)
+			stored := &models.DashboardProvisioning{Id: 3, DashboardId: 7, Name: configName,
+				ExternalId: filepath.Join(resolvedDir, "a.json"), CheckSum: checkSum}
+			fakeService.On("GetProvisionedDashboardData", configName).Return([]*models.DashboardProvisioning{stored}, nil).Times(3)
+			fakeService.On("SetProvisionedDashboardExternalID", mock.Anything, int64(3), filepath.Join(resolvedDir, "b.json")).
+				Return(nil).Once().
+				Run(func(args mock.Arguments) {
+					stored.ExternalId = args.String(2)
+				})
 
 			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
 			reader.dashboardProvisioningService = fakeService
@@ -271,31 +1028,87 @@ func TestDashboardFileReader(t *testing.T) {
 
 			err = reader.walkDisk(context.Background())
 			require.NoError(t, err)
+			require.Equal(t, filepath.Join(resolvedDir, "b.json"), stored.ExternalId)
+			require.Equal(t, checkSum, stored.CheckSum)
+
+			// the stored record now points at the new path, so the next run neither moves nor saves it
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			fakeService.AssertNotCalled(t, "SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+			fakeService.AssertNumberOfCalls(t, "SetProvisionedDashboardExternalID", 1)
+
+			// once the file content changes, the file wins over the UI edits again
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"title": "Changed"}`), 0600))
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 7}, nil).Once()
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+			fakeService.AssertNumberOfCalls(t, "SaveProvisionedDashboard", 1)
+			fakeService.AssertNumberOfCalls(t, "SetProvisionedDashboardExternalID", 1)
 		})
 
-		t.Run("Overrides id from dashboard.json files", func(t *testing.T) {
+		t.Run("Hung saves should time out without stopping the run", func(t *testing.T) {
 			setup()
-			cfg.Options["path"] = containingID
+			cfg.Options["path"] = defaultDashboards
 
 			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
-			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(nil, context.DeadlineExceeded).Times(2).
+				Run(func(args mock.Arguments) {
+					<-args.Get(0).(context.Context).Done()
+				})
 
 			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
 			reader.dashboardProvisioningService = fakeService
 			require.NoError(t, err)
+			require.Equal(t, 30*time.Second, reader.SaveTimeout)
+			reader.SaveTimeout = 10 * time.Millisecond
 
 			err = reader.walkDisk(context.Background())
 			require.NoError(t, err)
 		})
 
-		t.Run("Get folder from files structure", func(t *testing.T) {
+		t.Run("Folder rules should pick the folder of the first matching rule", func(t *testing.T) {
 			setup()
-			cfg.Options["path"] = foldersFromFilesStructure
-			cfg.Options["foldersFromFilesStructure"] = true
+			cfg.Options["path"] = oneDashboard
+			cfg.Options["folderRules"] = []interface{}{
+				map[string]interface{}{"pattern": "(^|/)prod/", "folder": "Production"},
+				map[string]interface{}{"pattern": "(^|/)dev/", "folder": "Development"},
+				map[string]interface{}{"pattern": "\\.json$", "folder": "Other"},
+			}
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.NoError(t, err)
+
+			root := filepath.FromSlash("/dashboards")
+			require.Equal(t, "Production", reader.folderFromRules(root, filepath.Join(root, "team", "prod", "a.json")))
+			require.Equal(t, "Development", reader.folderFromRules(root, filepath.Join(root, "dev", "b.json")))
+			require.Equal(t, "Other", reader.folderFromRules(root, filepath.Join(root, "c.json")))
+			require.Equal(t, "", reader.folderFromRules(root, filepath.Join(root, "c.json.gz")))
+
+			cfg.Options["folderRules"] = []interface{}{map[string]interface{}{"pattern": "(", "folder": "Broken"}}
+			_, err = NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.Error(t, err)
+		})
+
+		t.Run("Folder rules should provision dashboards into the matching folder", func(t *testing.T) {
+			setup()
+			cfg.Options["path"] = oneDashboard
+			cfg.Options["folderRules"] = []interface{}{
+				map[string]interface{}{"pattern": "^dashboard1\\.json$", "folder": "Production"},
+			}
 
 			fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
-			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Times(2)
-			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Times(3)
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+				Return(&models.Dashboard{Id: 41}, nil).Once().
+				Run(func(args mock.Arguments) {
+					require.Equal(t, "Production", args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Title)
+				})
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+				Return(&models.Dashboard{}, nil).Once().
+				Run(func(args mock.Arguments) {
+					require.Equal(t, int64(41), args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.FolderId)
+				})
 
 			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
 			reader.dashboardProvisioningService = fakeService
@@ -414,14 +1227,26 @@ func TestDashboardFileReader(t *testing.T) {
 		noFiles := map[string]os.FileInfo{}
 
 		t.Run("should skip dirs that starts with .", func(t *testing.T) {
-			shouldSkip := createWalkFn(noFiles)("path", &FakeFileInfo{isDirectory: true, name: ".folder"}, nil)
+			shouldSkip := createWalkFn(noFiles, walkFilter{fileExtensions: defaultFileExtensions, skipHiddenDirs: true})("path", &FakeFileInfo{isDirectory: true, name: ".folder"}, nil)
 			require.Equal(t, shouldSkip, filepath.SkipDir)
 		})
 
 		t.Run("should keep walking if file is not .json", func(t *testing.T) {
-			shouldSkip := createWalkFn(noFiles)("path", &FakeFileInfo{isDirectory: true, name: "folder"}, nil)
+			shouldSkip := createWalkFn(noFiles, walkFilter{fileExtensions: defaultFileExtensions, skipHiddenDirs: true})("path", &FakeFileInfo{isDirectory: true, name: "folder"}, nil)
 			require.Nil(t, shouldSkip)
 		})
+
+		t.Run("should only pick up files with the configured extensions", func(t *testing.T) {
+			files := map[string]os.FileInfo{}
+			walkFn := createWalkFn(files, walkFilter{fileExtensions: []string{".element.json", ".libpanel"}})
+			for _, name := range []string{"a.element.json", "b.libpanel", "fixture.json", "a.element.meta.json"} {
+				require.NoError(t, walkFn(name, &FakeFileInfo{name: name}, nil))
+			}
+
+			require.Len(t, files, 2)
+			require.Contains(t, files, "a.element.json")
+			require.Contains(t, files, "b.libpanel")
+		})
 	})
 
 	t.Run("Given missing dashboard file", func(t *testing.T) {
@@ -479,9 +1304,56 @@ func TestDashboardFileReader(t *testing.T) {
 			err = reader.walkDisk(context.Background())
 			require.NoError(t, err)
 		})
+
+		t.Run("Missing dashboard should be kept if readOnlyAdditive = true", func(t *testing.T) {
+			setupFakeService()
+			cfg.DisableDeletion = true
+			cfg.Options["readOnlyAdditive"] = true
+
+			fakeService.On("GetProvisionedDashboardData", configName).Return(provisionedDashboard, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+
+			reader, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			reader.dashboardProvisioningService = fakeService
+			require.NoError(t, err)
+
+			err = reader.walkDisk(context.Background())
+			require.NoError(t, err)
+		})
+
+		t.Run("readOnlyAdditive can't be used with pruneEmptyFolders", func(t *testing.T) {
+			setupFakeService()
+			cfg.Options["readOnlyAdditive"] = true
+			cfg.Options["pruneEmptyFolders"] = true
+
+			_, err := NewDashboardFileReader(cfg, logger, nil, fakeStore)
+			require.Error(t, err)
+		})
 	})
 }
 
+func TestValidateDashboard(t *testing.T) {
+	tests := map[string]string{
+		"array":         `[]`,
+		"missing title": `{"uid": "abc"}`,
+		"blank title":   `{"title": " "}`,
+		"numeric uid":   `{"title": "A", "uid": 1}`,
+		"object panels": `{"title": "A", "panels": {}}`,
+		"string tags":   `{"title": "A", "tags": "prod"}`,
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := simplejson.NewJson([]byte(raw))
+			require.NoError(t, err)
+			require.ErrorIs(t, validateDashboard(data), ErrInvalidDashboard)
+		})
+	}
+
+	data, err := simplejson.NewJson([]byte(`{"title": "A", "uid": null, "panels": [], "tags": ["prod"]}`))
+	require.NoError(t, err)
+	require.NoError(t, validateDashboard(data))
+}
+
 type FakeFileInfo struct {
 	isDirectory bool
 	name        string
@@ -516,3 +1388,274 @@ type fakeDashboardStore struct{}
 func (fds *fakeDashboardStore) GetDashboard(_ context.Context, _ *models.GetDashboardQuery) error {
 	return dashboards.ErrDashboardNotFound
 }
+
+type countingDashboardStore struct {
+	calls int
+}
+
+func (cds *countingDashboardStore) GetDashboard(_ context.Context, _ *models.GetDashboardQuery) error {
+	cds.calls++
+	return dashboards.ErrDashboardNotFound
+}
+
+// conflictingDashboardStore returns a dashboard for the slugs it holds, and no dashboard otherwise.
+type conflictingDashboardStore struct {
+	slugs map[string]bool
+}
+
+func (cds *conflictingDashboardStore) GetDashboard(_ context.Context, query *models.GetDashboardQuery) error {
+	if !cds.slugs[query.Slug] {
+		return dashboards.ErrDashboardNotFound
+	}
+	query.Result = &models.Dashboard{Id: 99, Slug: query.Slug}
+	return nil
+}
+
+func TestOnFolderConflict(t *testing.T) {
+	store := &conflictingDashboardStore{slugs: map[string]bool{"team-a": true, "team-a-2": true}}
+	newReader := func(t *testing.T, onFolderConflict string) (*FileReader, *dashboards.FakeDashboardProvisioning) {
+		t.Helper()
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Folder: "Team A", Options: map[string]interface{}{
+			"path":             oneDashboard,
+			"onFolderConflict": onFolderConflict,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, store)
+		require.NoError(t, err)
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		reader.dashboardProvisioningService = fakeService
+		return reader, fakeService
+	}
+
+	t.Run("should fail the run by default", func(t *testing.T) {
+		reader, fakeService := newReader(t, "")
+
+		require.ErrorIs(t, reader.walkDisk(context.Background()), ErrFolderConflict)
+		fakeService.AssertExpectations(t)
+	})
+
+	t.Run("should leave the dashboards unprovisioned with skip", func(t *testing.T) {
+		reader, fakeService := newReader(t, "skip")
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		fakeService.AssertExpectations(t)
+	})
+
+	t.Run("should create a suffixed folder with suffix", func(t *testing.T) {
+		reader, fakeService := newReader(t, "suffix")
+		fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+			Return(&models.Dashboard{Id: 7}, nil).Once().
+			Run(func(args mock.Arguments) {
+				require.Equal(t, "Team A (3)", args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Title)
+			})
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{}, nil).Once().
+			Run(func(args mock.Arguments) {
+				require.Equal(t, int64(7), args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.FolderId)
+			})
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		fakeService.AssertExpectations(t)
+	})
+
+	t.Run("should reject unknown values", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":             oneDashboard,
+			"onFolderConflict": "rename",
+		}}
+		_, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, store)
+		require.Error(t, err)
+	})
+}
+
+func TestNormalizeInput(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.json")
+	windows := filepath.Join(dir, "windows.json")
+	require.NoError(t, os.WriteFile(plain, []byte(`{"title": "CPU"}`), 0600))
+	require.NoError(t, os.WriteFile(windows, []byte("\xEF\xBB\xBF{\"title\": \"CPU\"}\r\n\r\n"), 0600))
+
+	newReader := func(t *testing.T, normalize bool) *FileReader {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":           dir,
+			"normalizeInput": normalize,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		return reader
+	}
+
+	t.Run("should give the same checksum to normalized files", func(t *testing.T) {
+		reader := newReader(t, true)
+		_, plainSum, err := reader.parseDashboardFile(plain)
+		require.NoError(t, err)
+		data, windowsSum, err := reader.parseDashboardFile(windows)
+		require.NoError(t, err)
+		require.Equal(t, plainSum, windowsSum)
+		require.Equal(t, "CPU", data.Get("title").MustString())
+	})
+
+	t.Run("should keep the content as is by default", func(t *testing.T) {
+		_, _, err := newReader(t, false).parseDashboardFile(windows)
+		require.Error(t, err)
+	})
+}
+
+func TestMaxFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.json"), []byte(`{"title": "CPU"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "memory.json"), []byte(`{"title": "Memory"}`), 0600))
+
+	newReader := func(t *testing.T, maxFiles, warnFiles int) (*FileReader, *dashboards.FakeDashboardProvisioning) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":      dir,
+			"maxFiles":  maxFiles,
+			"warnFiles": warnFiles,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		return reader, fakeService
+	}
+
+	t.Run("should abort the run above maxFiles", func(t *testing.T) {
+		reader, _ := newReader(t, 1, 0)
+		require.ErrorIs(t, reader.walkDisk(context.Background()), ErrTooManyFiles)
+	})
+
+	t.Run("should still provision the files above warnFiles", func(t *testing.T) {
+		reader, fakeService := newReader(t, 2, 1)
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{}, nil).Twice()
+		require.NoError(t, reader.walkDisk(context.Background()))
+	})
+
+	t.Run("should reject negative thresholds", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":     dir,
+			"maxFiles": -1,
+		}}
+		_, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestForceReprovision(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cpu.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"title": "CPU", "uid": "cpu"}`), 0600))
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+
+	var provisioned []*models.DashboardProvisioning
+	fakeService.On("GetProvisionedDashboardData", configName).Return(func(string) []*models.DashboardProvisioning {
+		return provisioned
+	}, nil)
+	fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+		Return(&models.Dashboard{Id: 1}, nil).
+		Run(func(args mock.Arguments) {
+			dp := args.Get(2).(*models.DashboardProvisioning)
+			provisioned = []*models.DashboardProvisioning{{DashboardId: 1, Name: configName, ExternalId: dp.ExternalId, CheckSum: dp.CheckSum}}
+		})
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path":             dir,
+		"forceReprovision": true,
+	}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	// only the first run is forced by the option
+	require.NoError(t, reader.walkDisk(context.Background()))
+	require.NoError(t, reader.walkDisk(context.Background()))
+	fakeService.AssertNumberOfCalls(t, "SaveProvisionedDashboard", 1)
+
+	// a forced run saves the unchanged dashboard again, once
+	reader.forceReprovision()
+	require.NoError(t, reader.walkDisk(context.Background()))
+	require.NoError(t, reader.walkDisk(context.Background()))
+	fakeService.AssertNumberOfCalls(t, "SaveProvisionedDashboard", 2)
+
+	// with a data path, the option is only applied once per version of the config file
+	configDir := t.TempDir()
+	configFile := filepath.Join(configDir, "dashboards.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("forceReprovision: true\n"), 0600))
+	cfg.dir, cfg.file, cfg.dataPath = configDir, "dashboards.yaml", t.TempDir()
+	restart := func() {
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		require.NoError(t, reader.walkDisk(context.Background()))
+	}
+
+	restart()
+	fakeService.AssertNumberOfCalls(t, "SaveProvisionedDashboard", 3)
+	restart()
+	fakeService.AssertNumberOfCalls(t, "SaveProvisionedDashboard", 3)
+
+	require.NoError(t, os.WriteFile(configFile, []byte("forceReprovision: true\n# forced again\n"), 0600))
+	restart()
+	restart()
+	fakeService.AssertNumberOfCalls(t, "SaveProvisionedDashboard", 4)
+}
+
+func TestNestedFolderParents(t *testing.T) {
+	newReader := func(t *testing.T, folderUID string, folders int64) (*FileReader, *[]*models.Dashboard) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+
+		var saved []*models.Dashboard
+		for id := int64(21); id < 21+folders; id++ {
+			id := id
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).
+				Return(&models.Dashboard{Id: id}, nil).Once().
+				Run(func(args mock.Arguments) {
+					saved = append(saved, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard)
+				})
+		}
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, FolderUID: folderUID, Options: map[string]interface{}{
+			"path": defaultDashboards,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		return reader, &saved
+	}
+
+	t.Run("should tell apart folders with the same name in different parents", func(t *testing.T) {
+		reader, saved := newReader(t, "", 4)
+
+		ax, err := reader.getOrCreateNestedFolderID(context.Background(), reader.Cfg, reader.dashboardProvisioningService, "a/x", 0)
+		require.NoError(t, err)
+		bx, err := reader.getOrCreateNestedFolderID(context.Background(), reader.Cfg, reader.dashboardProvisioningService, "b/x", 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(22), ax)
+		require.Equal(t, int64(24), bx)
+
+		// looked up again from the cache
+		again, err := reader.getOrCreateNestedFolderID(context.Background(), reader.Cfg, reader.dashboardProvisioningService, "b/x", 0)
+		require.NoError(t, err)
+		require.Equal(t, bx, again)
+
+		parents := []int64{}
+		for _, folder := range *saved {
+			parents = append(parents, folder.FolderId)
+		}
+		require.Equal(t, []int64{0, 21, 0, 23}, parents)
+	})
+
+	t.Run("should only give the folder uid to the top level folder", func(t *testing.T) {
+		reader, saved := newReader(t, "team-uid", 3)
+
+		_, err := reader.getOrCreateNestedFolderID(context.Background(), reader.Cfg, reader.dashboardProvisioningService, "a/x/y", 0)
+		require.NoError(t, err)
+		require.Len(t, *saved, 3)
+		require.Equal(t, "team-uid", (*saved)[0].Uid)
+		require.Empty(t, (*saved)[1].Uid)
+		require.Empty(t, (*saved)[2].Uid)
+	})
+}
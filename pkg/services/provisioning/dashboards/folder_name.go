@@ -0,0 +1,67 @@
+package dashboards
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// folderNameFuncs are the functions available to the folderNameTemplate option.
+var folderNameFuncs = template.FuncMap{
+	"title": titleCase,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	// replace takes the replaced string last, so that it can be used in pipelines
+	"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+}
+
+// folderNameData is the data the folderNameTemplate option is executed with.
+type folderNameData struct {
+	// Name is the name of the directory the folder is created for.
+	Name string
+}
+
+// folderNameTemplateOption reads the folderNameTemplate option, which turns the name of a directory into the title
+// of its folder when the folders come from the file structure, for example `{{ .Name | replace "_" " " | title }}`.
+func folderNameTemplateOption(options map[string]interface{}) (*template.Template, error) {
+	raw, _ := options["folderNameTemplate"].(string)
+	if raw == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("folderNameTemplate").Funcs(folderNameFuncs).Option("missingkey=error").Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'folderNameTemplate' option: %w", err)
+	}
+	return tmpl, nil
+}
+
+// folderTitle returns the title of the folder created for the directory named dirName. Folders are looked up by
+// the slug of their title, so the same directory always resolves to the same folder.
+func (fr *FileReader) folderTitle(dirName string) (string, error) {
+	if fr.FolderNameTemplate == nil || dirName == "" {
+		return dirName, nil
+	}
+
+	var title strings.Builder
+	if err := fr.FolderNameTemplate.Execute(&title, folderNameData{Name: dirName}); err != nil {
+		return "", fmt.Errorf("failed to apply 'folderNameTemplate' to %q: %w", dirName, err)
+	}
+	if strings.TrimSpace(title.String()) == "" {
+		return "", fmt.Errorf("'folderNameTemplate' turned %q into an empty folder name", dirName)
+	}
+	return title.String(), nil
+}
+
+// titleCase upper cases the first letter of every word of s.
+func titleCase(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if i == 0 || unicode.IsSpace(runes[i-1]) {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(runes)
+}
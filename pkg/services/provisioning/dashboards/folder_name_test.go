@@ -0,0 +1,74 @@
+package dashboards
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestFolderNameTemplate(t *testing.T) {
+	newReader := func(t *testing.T, template string, service dashboards.DashboardProvisioningService) *FileReader {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":                      foldersFromFilesStructure,
+			"foldersFromFilesStructure": true,
+			"folderNameTemplate":        template,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), service, &fakeDashboardStore{})
+		require.NoError(t, err)
+		return reader
+	}
+
+	tests := []struct {
+		template string
+		dirName  string
+		title    string
+	}{
+		{template: `{{ .Name | replace "_" " " | title }}`, dirName: "team_alpha", title: "Team Alpha"},
+		{template: `Team {{ .Name | upper }}`, dirName: "a", title: "Team A"},
+		{template: `{{ .Name }} (provisioned)`, dirName: "infra", title: "infra (provisioned)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			title, err := newReader(t, tt.template, nil).folderTitle(tt.dirName)
+			require.NoError(t, err)
+			require.Equal(t, tt.title, title)
+		})
+	}
+
+	t.Run("should reject invalid templates", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":               foldersFromFilesStructure,
+			"folderNameTemplate": "{{ .Name ",
+		}}
+		_, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject templates producing empty names", func(t *testing.T) {
+		_, err := newReader(t, `{{ .Name | replace "_" "" }}`, nil).folderTitle("___")
+		require.Error(t, err)
+	})
+
+	t.Run("should create the folders with the transformed names", func(t *testing.T) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+		fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.MatchedBy(func(dto *dashboards.SaveDashboardDTO) bool {
+			return dto.Dashboard.Title == "Folder One (provisioned)"
+		})).Return(&models.Dashboard{Id: 1}, nil).Once()
+
+		reader := newReader(t, `{{ .Name | replace "folder" "Folder " }} (provisioned)`, fakeService)
+		resolvedPath := reader.resolvedPath()
+		folderID, folderName, err := reader.getOrCreateFileStructureFolderID(context.Background(), resolvedPath,
+			filepath.Join(resolvedPath, "folderOne", "dashboard1.json"))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), folderID)
+		require.Equal(t, "Folder One (provisioned)", folderName)
+	})
+}
@@ -0,0 +1,70 @@
+package dashboards
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// forcedConfigPath returns the file, under the data directory of Grafana, recording the checksum of the config file
+// whose forceReprovision option was last applied to the provider.
+func forcedConfigPath(dataPath, name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return filepath.Join(dataPath, "provisioning", "dashboards", fmt.Sprintf("forced-%x", sum[:8]))
+}
+
+// forceReprovisionOption tells whether the forceReprovision option of the provider forces its first run. The option is
+// applied once per version of the config file, so that restarts and reloads don't save every dashboard again. It
+// also returns the checksum of the config file to record once the forced run succeeded, which is empty when it
+// can't be recorded, for instance without a data directory, in which case the option is applied on every start.
+func forceReprovisionOption(cfg *config, logger log.Logger) (bool, string) {
+	force, _ := cfg.Options["forceReprovision"].(bool)
+	if !force || cfg.dataPath == "" || cfg.file == "" {
+		return force, ""
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because the config file was just read by the config reader.
+	raw, err := ioutil.ReadFile(filepath.Join(cfg.dir, cfg.file))
+	if err != nil {
+		logger.Warn("failed to read config file to check whether forceReprovision was applied", "file", cfg.file, "error", err)
+		return true, ""
+	}
+	checkSum, err := util.Md5SumString(string(raw))
+	if err != nil {
+		return true, ""
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because the path is derived from the data path.
+	applied, err := ioutil.ReadFile(forcedConfigPath(cfg.dataPath, cfg.Name))
+	if err == nil && string(applied) == checkSum {
+		logger.Debug("forceReprovision was already applied for this version of the config file", "file", cfg.file)
+		return false, ""
+	}
+	return true, checkSum
+}
+
+// recordForcedConfig records that the forceReprovision option of the current config file was applied. It is only
+// called during runs.
+func (fr *FileReader) recordForcedConfig() {
+	if fr.forcedConfigChecksum == "" {
+		return
+	}
+
+	path := forcedConfigPath(fr.Cfg.dataPath, fr.Cfg.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		fr.log.Error("failed to record that forceReprovision was applied", "path", path, "error", err)
+		return
+	}
+	if err := ioutil.WriteFile(path, []byte(fr.forcedConfigChecksum), 0600); err != nil {
+		fr.log.Error("failed to record that forceReprovision was applied", "path", path, "error", err)
+		return
+	}
+	fr.forcedConfigChecksum = ""
+}
@@ -0,0 +1,39 @@
+package dashboards
+
+import (
+	"context"
+)
+
+// advanceGeneration increases the generation of the status at the end of a successful run which synced the
+// dashboards and wakes up the callers waiting for it. The caller must hold mux.
+func (fr *FileReader) advanceGeneration() {
+	fr.status.Generation++
+	if fr.generationChanged != nil {
+		close(fr.generationChanged)
+		fr.generationChanged = nil
+	}
+}
+
+// WaitForGeneration waits until the provisioner has completed the given number of successful runs which synced the
+// dashboards, or ctx is done. A run in progress when files are written may have listed the files before, so waiting
+// for two generations past the one read before writing them guarantees that they have been provisioned.
+func (fr *FileReader) WaitForGeneration(ctx context.Context, generation int64) error {
+	for {
+		fr.mux.Lock()
+		if fr.status.Generation >= generation {
+			fr.mux.Unlock()
+			return nil
+		}
+		if fr.generationChanged == nil {
+			fr.generationChanged = make(chan struct{})
+		}
+		changed := fr.generationChanged
+		fr.mux.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
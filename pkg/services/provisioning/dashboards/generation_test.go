@@ -0,0 +1,74 @@
+package dashboards
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestGeneration(t *testing.T) {
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": t.TempDir()}}
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	t.Run("should count successful runs", func(t *testing.T) {
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, dashboards.ErrDashboardNotFound).Once()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.Error(t, reader.walkDisk(context.Background()))
+		require.Equal(t, int64(1), reader.getStatus().Generation)
+	})
+
+	t.Run("should return once the generation is reached", func(t *testing.T) {
+		require.NoError(t, reader.WaitForGeneration(context.Background(), 1))
+	})
+
+	t.Run("should wait for the next runs", func(t *testing.T) {
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Twice()
+
+		done := make(chan error)
+		go func() {
+			done <- reader.WaitForGeneration(context.Background(), 3)
+		}()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		select {
+		case err := <-done:
+			t.Fatalf("returned before the generation was reached: %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.NoError(t, <-done)
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, reader.WaitForGeneration(ctx, 4), context.Canceled)
+	})
+	t.Run("should not count skipped runs", func(t *testing.T) {
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		reader.VerifyOnly = true
+		defer func() { reader.VerifyOnly = false }()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.Equal(t, int64(3), reader.getStatus().Generation)
+	})
+
+	t.Run("should wait through the provisioner", func(t *testing.T) {
+		provisioner := &Provisioner{fileReaders: []*FileReader{reader}}
+		require.NoError(t, provisioner.WaitForGeneration(context.Background(), configName, 3))
+		require.ErrorIs(t, provisioner.WaitForGeneration(context.Background(), "unknown", 3), ErrProvisionerNotFound)
+	})
+}
@@ -0,0 +1,84 @@
+package dashboards
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// globMetaChars are the characters which turn the path of a provider into a glob pattern.
+const globMetaChars = "*?["
+
+func isGlobPattern(p string) bool {
+	return strings.ContainsAny(p, globMetaChars)
+}
+
+// splitGlobPattern splits a glob pattern into the directory holding every file it can match, which is the
+// part before the first element containing a metacharacter, and the pattern the paths relative to that
+// directory must match, using / as separator.
+func splitGlobPattern(pattern string) (string, string) {
+	elements := strings.Split(filepath.ToSlash(pattern), "/")
+	for i, element := range elements {
+		if isGlobPattern(element) {
+			dir := strings.Join(elements[:i], "/")
+			switch {
+			case i == 0:
+				dir = "."
+			case dir == "":
+				dir = "/"
+			}
+			return filepath.FromSlash(dir), strings.Join(elements[i:], "/")
+		}
+	}
+	return pattern, ""
+}
+
+// validateGlobPattern returns path.ErrBadPattern if any element of pattern is malformed.
+func validateGlobPattern(pattern string) error {
+	for _, element := range strings.Split(pattern, "/") {
+		if _, err := path.Match(element, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchGlob reports whether the slash separated name matches pattern. Elements are matched with path.Match,
+// except for `**` which matches any number of directories, including none.
+func matchGlob(pattern, name string) bool {
+	return matchGlobElements(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlobElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], name[0]); err != nil || !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// filterGlobMatches removes the files found under resolvedPath which don't match the path pattern of the provider.
+func (fr *FileReader) filterGlobMatches(resolvedPath string, filesOnDisk map[string]os.FileInfo) {
+	for p := range filesOnDisk {
+		relativePath, err := filepath.Rel(resolvedPath, p)
+		if err != nil || !matchGlob(fr.PathPattern, filepath.ToSlash(relativePath)) {
+			delete(filesOnDisk, p)
+		}
+	}
+}
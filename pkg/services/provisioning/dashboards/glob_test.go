@@ -0,0 +1,68 @@
+package dashboards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		matches bool
+	}{
+		{pattern: "*.json", name: "cpu.json", matches: true},
+		{pattern: "*.json", name: "team/cpu.json", matches: false},
+		{pattern: "**/panels/*.json", name: "panels/cpu.json", matches: true},
+		{pattern: "**/panels/*.json", name: "team/a/panels/cpu.json", matches: true},
+		{pattern: "**/panels/*.json", name: "team/panels/old/cpu.json", matches: false},
+		{pattern: "team/**", name: "team/a/b/cpu.json", matches: true},
+		{pattern: "team-?/*.json", name: "team-a/cpu.json", matches: true},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.matches, matchGlob(tt.pattern, tt.name), "%s against %s", tt.name, tt.pattern)
+	}
+}
+
+func TestSplitGlobPattern(t *testing.T) {
+	dir, pattern := splitGlobPattern("/provisioning/**/panels/*.json")
+	require.Equal(t, filepath.FromSlash("/provisioning"), dir)
+	require.Equal(t, "**/panels/*.json", pattern)
+
+	dir, pattern = splitGlobPattern("*.json")
+	require.Equal(t, ".", dir)
+	require.Equal(t, "*.json", pattern)
+}
+
+func TestGlobPath(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"panels/cpu.json", "team/panels/memory.json", "team/other/disk.json", "root.json"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(t, os.WriteFile(path, []byte(`{"title": "Dashboard"}`), 0600))
+	}
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path": filepath.Join(dir, "**", "panels", "*.json"),
+	}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, dir, reader.Path)
+
+	resolvedPath := reader.resolvedPath()
+	files := map[string]os.FileInfo{}
+	require.NoError(t, reader.walkFiles(resolvedPath, files))
+	require.Len(t, files, 2)
+	require.Contains(t, files, filepath.Join(resolvedPath, "panels", "cpu.json"))
+	require.Contains(t, files, filepath.Join(resolvedPath, "team", "panels", "memory.json"))
+
+	cfg.Options["path"] = filepath.Join(dir, "[a-", "*.json")
+	_, err = NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.Error(t, err)
+}
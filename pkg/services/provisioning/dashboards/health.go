@@ -0,0 +1,38 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// healthProbeTimeout is how long the database may take to answer the health probe.
+const healthProbeTimeout = 5 * time.Second
+
+// healthProbeUID is the uid looked up by the health probe. No dashboard is expected to use it, so a healthy
+// database answers with dashboards.ErrDashboardNotFound.
+const healthProbeUID = "provisioning-health-probe"
+
+// databaseHealthy runs a cheap query against the database and reports whether it answered. The outcome is only
+// logged when it changes, so that an outage results in a single warning rather than one per run.
+func (fr *FileReader) databaseHealthy(ctx context.Context) bool {
+	probeCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	err := fr.dashboardStore.GetDashboard(probeCtx, &models.GetDashboardQuery{Uid: healthProbeUID, OrgId: fr.Cfg.OrgID})
+	healthy := err == nil || errors.Is(err, dashboards.ErrDashboardNotFound)
+
+	switch {
+	case !healthy && !fr.dbUnhealthy:
+		fr.log.Warn("skipping provisioning runs while the database is unhealthy", "error", err)
+	case !healthy:
+		fr.log.Debug("skipping provisioning run, the database is still unhealthy", "error", err)
+	case fr.dbUnhealthy:
+		fr.log.Info("database is healthy again, resuming provisioning runs")
+	}
+	fr.dbUnhealthy = !healthy
+	return healthy
+}
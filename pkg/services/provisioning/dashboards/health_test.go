@@ -0,0 +1,49 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// probedDashboardStore answers every lookup with err.
+type probedDashboardStore struct {
+	err error
+}
+
+func (pds *probedDashboardStore) GetDashboard(_ context.Context, _ *models.GetDashboardQuery) error {
+	return pds.err
+}
+
+func TestSkipOnUnhealthyDB(t *testing.T) {
+	store := &probedDashboardStore{err: errors.New("database is locked")}
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path":              oneDashboard,
+		"skipOnUnhealthyDb": true,
+	}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, store)
+	require.NoError(t, err)
+
+	t.Run("should skip the run while the database is unhealthy", func(t *testing.T) {
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.True(t, reader.dbUnhealthy)
+		fakeService.AssertNotCalled(t, "GetProvisionedDashboardData", configName)
+	})
+
+	t.Run("should run once the database is healthy again", func(t *testing.T) {
+		store.err = dashboards.ErrDashboardNotFound
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, errors.New("stop")).Once()
+
+		require.Error(t, reader.walkDisk(context.Background()))
+		require.False(t, reader.dbUnhealthy)
+	})
+}
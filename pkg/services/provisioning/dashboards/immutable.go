@@ -0,0 +1,51 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// isImmutable reports whether a dashboard is marked with `"immutable": true`.
+func isImmutable(data *simplejson.Json) bool {
+	return data.Get("immutable").MustBool()
+}
+
+// isStoredImmutable reports whether the stored version of a provisioned dashboard is immutable. The stored version
+// is the one checked, so that removing the flag from the file doesn't lift the protection either.
+func (fr *FileReader) isStoredImmutable(ctx context.Context, orgID int64, dashboardID int64) (bool, error) {
+	query := &models.GetDashboardQuery{OrgId: orgID, Id: dashboardID}
+	if err := fr.dashboardStore.GetDashboard(ctx, query); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isImmutable(query.Result.Data), nil
+}
+
+// keepImmutable reports whether a provisioned dashboard must be kept rather than removed because it's immutable,
+// which is only overridden by the allowImmutableDeletion option.
+func (fr *FileReader) keepImmutable(ctx context.Context, provisioningData *models.DashboardProvisioning, reason string) (bool, error) {
+	if fr.AllowImmutableDeletion {
+		return false, nil
+	}
+
+	orgID := fr.Cfg.OrgID
+	if fr.OrgIDFromPath {
+		orgID = fr.orgIDForPath(fr.resolvedPath(), provisioningData.ExternalId)
+	}
+	immutable, err := fr.isStoredImmutable(ctx, orgID, provisioningData.DashboardId)
+	if err != nil {
+		return false, fmt.Errorf("failed to get dashboard to check whether it's immutable: %w", err)
+	}
+	if immutable {
+		fr.log.Warn("keeping immutable dashboard, set 'allowImmutableDeletion' to remove it", "id",
+			provisioningData.DashboardId, "file", provisioningData.ExternalId, "reason", reason)
+	}
+	return immutable, nil
+}
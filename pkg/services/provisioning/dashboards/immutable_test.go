@@ -0,0 +1,84 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestImmutableDashboards(t *testing.T) {
+	newReader := func(t *testing.T, options map[string]interface{}, store idDashboardStore) (*FileReader, *dashboards.FakeDashboardProvisioning) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+		options["path"] = oneDashboard
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, store)
+		require.NoError(t, err)
+		return reader, fakeService
+	}
+	immutableStore := func() idDashboardStore {
+		return idDashboardStore{2: {Id: 2, OrgId: 1, Uid: "cpu", Data: simplejson.NewFromAny(map[string]interface{}{
+			"uid": "cpu", "title": "CPU", "immutable": true,
+		})}}
+	}
+	provisioningData := &models.DashboardProvisioning{DashboardId: 2, Name: configName, ExternalId: "/dashboards/cpu.json"}
+
+	t.Run("should not apply changes to immutable dashboards", func(t *testing.T) {
+		reader, fakeService := newReader(t, map[string]interface{}{}, immutableStore())
+		file, err := filepath.Abs(filepath.Join(oneDashboard, "dashboard1.json"))
+		require.NoError(t, err)
+		fileInfo, err := os.Stat(file)
+		require.NoError(t, err)
+		refs := map[string]*models.DashboardProvisioning{
+			file: {DashboardId: 2, Name: configName, ExternalId: file, CheckSum: "changed"},
+		}
+
+		_, err = reader.saveDashboard(context.Background(), file, 0, fileInfo, refs, newUsageTracker())
+		require.NoError(t, err)
+		fakeService.AssertNotCalled(t, "SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should keep immutable dashboards", func(t *testing.T) {
+		reader, fakeService := newReader(t, map[string]interface{}{}, immutableStore())
+
+		reader.removeProvisionedDashboard(context.Background(), provisioningData, "missing on disk")
+		fakeService.AssertNotCalled(t, "DeleteProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should delete immutable dashboards when allowed", func(t *testing.T) {
+		reader, fakeService := newReader(t, map[string]interface{}{"allowImmutableDeletion": true}, immutableStore())
+		fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(2), int64(1)).Return(nil).Once()
+
+		reader.removeProvisionedDashboard(context.Background(), provisioningData, "missing on disk")
+	})
+
+	t.Run("should delete mutable dashboards", func(t *testing.T) {
+		store := immutableStore()
+		store[2].Data.Del("immutable")
+		reader, fakeService := newReader(t, map[string]interface{}{}, store)
+		fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(2), int64(1)).Return(nil).Once()
+
+		reader.removeProvisionedDashboard(context.Background(), provisioningData, "missing on disk")
+	})
+	t.Run("should not remove dashboards whose lookup failed", func(t *testing.T) {
+		reader, fakeService := newReader(t, map[string]interface{}{}, idDashboardStore{})
+		reader.dashboardStore = &probedDashboardStore{err: errors.New("database is locked")}
+
+		keep, err := reader.keepImmutable(context.Background(), provisioningData, "missing on disk")
+		require.Error(t, err)
+		require.False(t, keep)
+
+		reader.removeProvisionedDashboard(context.Background(), provisioningData, "missing on disk")
+		fakeService.AssertNotCalled(t, "DeleteProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,114 @@
+package dashboards
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// includeDirective is the key of the objects which are replaced with the content of another file.
+const includeDirective = "$include"
+
+// fragmentSuffix is the suffix of files which are only meant to be included by dashboards, e.g.
+// `thresholds.fragment.json`. They are never provisioned as dashboards.
+const fragmentSuffix = ".fragment.json"
+
+// ErrIncludeCycle is returned when a dashboard file includes itself, directly or through other files.
+var ErrIncludeCycle = errors.New("include cycle")
+
+func isFragment(name string) bool {
+	return strings.HasSuffix(name, fragmentSuffix)
+}
+
+// resolveIncludes replaces every `{"$include": "path"}` object in data with the parsed content of the referenced
+// file, relative to the including file. Included files must be stored under the path of the provider, and can
+// include other files in turn. The checksums of the included
+// files are folded into checkSum, so that changing a fragment updates every dashboard including it.
+func (fr *FileReader) resolveIncludes(path string, data *simplejson.Json, checkSum string) (*simplejson.Json, string, error) {
+	var checkSums []string
+	resolved, err := fr.resolveIncludesIn(data.Interface(), []string{path}, &checkSums)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(checkSums) == 0 {
+		return data, checkSum, nil
+	}
+
+	checkSum, err = util.Md5SumString(checkSum + strings.Join(checkSums, ""))
+	if err != nil {
+		return nil, "", err
+	}
+	return simplejson.NewFromAny(resolved), checkSum, nil
+}
+
+// resolveIncludesIn resolves the includes within value in place. stack holds the files being included, starting
+// with the dashboard file, and checkSums collects the checksums of the included files in a stable order.
+func (fr *FileReader) resolveIncludesIn(value interface{}, stack []string, checkSums *[]string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if target, ok := v[includeDirective]; ok && len(v) == 1 {
+			return fr.include(target, stack, checkSums)
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			resolved, err := fr.resolveIncludesIn(v[key], stack, checkSums)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = resolved
+		}
+	case []interface{}:
+		for i, item := range v {
+			resolved, err := fr.resolveIncludesIn(item, stack, checkSums)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+	}
+
+	return value, nil
+}
+
+func (fr *FileReader) include(target interface{}, stack []string, checkSums *[]string) (interface{}, error) {
+	relativePath, ok := target.(string)
+	if !ok || relativePath == "" {
+		return nil, fmt.Errorf("%q must be a non-empty path", includeDirective)
+	}
+
+	path := filepath.FromSlash(relativePath)
+	if filepath.IsAbs(path) {
+		return nil, fmt.Errorf("failed to include %q: %w", relativePath, ErrFileOutsidePath)
+	}
+	path = filepath.Join(filepath.Dir(stack[len(stack)-1]), path)
+	withinPath, err := filepath.Rel(fr.resolvedPath(), path)
+	if err != nil || withinPath == ".." || strings.HasPrefix(withinPath, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("failed to include %q: %w", relativePath, ErrFileOutsidePath)
+	}
+
+	next := append(append([]string{}, stack...), path)
+	for _, including := range stack {
+		if including == path {
+			return nil, fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(next, " -> "))
+		}
+	}
+
+	data, checkSum, err := fr.parseDashboardFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to include %q: %w", relativePath, err)
+	}
+	*checkSums = append(*checkSums, checkSum)
+
+	return fr.resolveIncludesIn(data.Interface(), next, checkSums)
+}
@@ -0,0 +1,101 @@
+package dashboards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestResolveIncludes(t *testing.T) {
+	setup := func(t *testing.T, files map[string]string) (*FileReader, string) {
+		t.Helper()
+		dir := t.TempDir()
+		for name, content := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		}
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": dir}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		return reader, dir
+	}
+
+	read := func(t *testing.T, reader *FileReader, path string) (*dashboardJSONFile, error) {
+		t.Helper()
+		fileInfo, err := os.Stat(path)
+		require.NoError(t, err)
+		return reader.readDashboardFromFile(path, fileInfo, 0)
+	}
+
+	t.Run("should splice included files and fold them into the checksum", func(t *testing.T) {
+		reader, dir := setup(t, map[string]string{
+			"cpu.json":                        `{"title": "CPU", "panels": [{"title": "Usage", "thresholds": {"$include": "shared/thresholds.fragment.json"}}]}`,
+			"shared/thresholds.fragment.json": `{"steps": [{"value": 80}, {"$include": "red.fragment.json"}]}`,
+			"shared/red.fragment.json":        `{"color": "red"}`,
+		})
+		path := filepath.Join(dir, "cpu.json")
+
+		jsonFile, err := read(t, reader, path)
+		require.NoError(t, err)
+		data := jsonFile.dashboard.Dashboard.Data
+		steps := data.Get("panels").GetIndex(0).GetPath("thresholds", "steps")
+		require.Equal(t, "red", steps.GetIndex(1).Get("color").MustString())
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "shared", "red.fragment.json"), []byte(`{"color": "dark-red"}`), 0600))
+		changed, err := read(t, reader, path)
+		require.NoError(t, err)
+		require.NotEqual(t, jsonFile.checkSum, changed.checkSum)
+	})
+
+	t.Run("should not change the checksum of files without includes", func(t *testing.T) {
+		reader, dir := setup(t, map[string]string{"cpu.json": `{"title": "CPU"}`})
+
+		jsonFile, err := read(t, reader, filepath.Join(dir, "cpu.json"))
+		require.NoError(t, err)
+		_, checkSum, err := reader.parseDashboardFile(filepath.Join(dir, "cpu.json"))
+		require.NoError(t, err)
+		require.Equal(t, checkSum, jsonFile.checkSum)
+	})
+
+	t.Run("should report include cycles", func(t *testing.T) {
+		reader, dir := setup(t, map[string]string{
+			"cpu.json":        `{"title": "CPU", "panels": [{"$include": "a.fragment.json"}]}`,
+			"a.fragment.json": `{"title": "A", "targets": {"$include": "b.fragment.json"}}`,
+			"b.fragment.json": `{"$include": "a.fragment.json"}`,
+		})
+
+		_, err := read(t, reader, filepath.Join(dir, "cpu.json"))
+		require.ErrorIs(t, err, ErrIncludeCycle)
+	})
+
+	t.Run("should not include files outside of the path", func(t *testing.T) {
+		outside := filepath.Join(t.TempDir(), "secret.fragment.json")
+		require.NoError(t, os.WriteFile(outside, []byte(`{"color": "red"}`), 0600))
+
+		for _, target := range []string{outside, "../secret.fragment.json", "shared/../../secret.fragment.json"} {
+			reader, dir := setup(t, map[string]string{
+				"cpu.json": `{"title": "CPU", "panels": [{"$include": "` + filepath.ToSlash(target) + `"}]}`,
+			})
+
+			_, err := read(t, reader, filepath.Join(dir, "cpu.json"))
+			require.ErrorIs(t, err, ErrFileOutsidePath, target)
+		}
+	})
+
+	t.Run("should not provision fragments", func(t *testing.T) {
+		reader, _ := setup(t, map[string]string{
+			"cpu.json":          `{"title": "CPU"}`,
+			"red.fragment.json": `{"color": "red"}`,
+		})
+
+		files := map[string]os.FileInfo{}
+		require.NoError(t, reader.walkFiles(reader.resolvedPath(), files))
+		require.Len(t, files, 1)
+	})
+}
@@ -0,0 +1,171 @@
+package dashboards
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// intervalRule polls the dashboards whose path relative to the provider path matches the glob pattern more often
+// than the rest of the provider.
+type intervalRule struct {
+	pattern  string
+	interval time.Duration
+}
+
+// intervalRulesOption reads the `intervalRules` option, a list of `pattern` and `updateIntervalSeconds` pairs.
+func intervalRulesOption(options map[string]interface{}) ([]intervalRule, error) {
+	raw, ok := options["intervalRules"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("option %q must be a list of rules", "intervalRules")
+	}
+
+	rules := make([]intervalRule, 0, len(items))
+	for i, item := range items {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("rule %d of %q must have a pattern and an updateIntervalSeconds", i, "intervalRules")
+		}
+		pattern, _ := entry["pattern"].(string)
+		seconds, set, err := int64Option(entry, "updateIntervalSeconds")
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %d of %q: %w", i, "intervalRules", err)
+		}
+		if pattern == "" || !set {
+			return nil, fmt.Errorf("rule %d of %q must have a pattern and an updateIntervalSeconds", i, "intervalRules")
+		}
+		if seconds <= 0 {
+			return nil, fmt.Errorf("'updateIntervalSeconds' of rule %d of %q must be positive", i, "intervalRules")
+		}
+		if err := validateGlobPattern(pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern in rule %d of %q: %w", i, "intervalRules", err)
+		}
+		rules = append(rules, intervalRule{pattern: pattern, interval: time.Duration(seconds) * time.Second})
+	}
+
+	return rules, nil
+}
+
+// intervalSchedule keeps track of when the files of every interval rule were last checked.
+type intervalSchedule struct {
+	rules       []intervalRule
+	lastChecked []time.Time
+	tick        time.Duration
+}
+
+func newIntervalSchedule(rules []intervalRule, now time.Time) *intervalSchedule {
+	s := &intervalSchedule{rules: rules, lastChecked: make([]time.Time, len(rules))}
+	for i, rule := range rules {
+		s.lastChecked[i] = now
+		if s.tick == 0 || rule.interval < s.tick {
+			s.tick = rule.interval
+		}
+	}
+	return s
+}
+
+// due returns the rules whose interval elapsed since their files were last checked, and marks them as checked.
+func (s *intervalSchedule) due(now time.Time) []intervalRule {
+	var due []intervalRule
+	for i, rule := range s.rules {
+		if now.Sub(s.lastChecked[i]) >= rule.interval {
+			due = append(due, rule)
+			s.lastChecked[i] = now
+		}
+	}
+	return due
+}
+
+// checkedAll marks the files of every rule as checked, which happens on every regular run.
+func (s *intervalSchedule) checkedAll(now time.Time) {
+	for i := range s.lastChecked {
+		s.lastChecked[i] = now
+	}
+}
+
+// walkHotFiles provisions the files matching any of rules. Unlike walkDisk, it never removes the dashboards of
+// missing files nor prunes folders, which is left to the regular runs of the provider.
+func (fr *FileReader) walkHotFiles(ctx context.Context, rules []intervalRule) error {
+	if err := fr.acquireRunSlot(ctx); err != nil {
+		return err
+	}
+	defer fr.runSlots.release()
+
+	fr.runMux.Lock()
+	defer fr.runMux.Unlock()
+
+	ctx, stop := fr.drainContext(ctx)
+	defer stop()
+
+	fr.stats = runStats{}
+	fr.folderIDs = map[folderKey]int64{}
+
+	resolvedPath := fr.resolvedPath()
+	if _, err := os.Stat(resolvedPath); err != nil {
+		return err
+	}
+
+	if fr.SkipOnUnhealthyDB && !fr.databaseHealthy(ctx) {
+		return nil
+	}
+
+	if fr.LockFile {
+		acquired, _, err := fr.acquireLock(resolvedPath, fr.Clock.Now())
+		if err != nil {
+			return fmt.Errorf("failed to acquire provisioning lock: %w", err)
+		}
+		if !acquired {
+			return nil
+		}
+	}
+
+	provisionedDashboardRefs, err := getProvisionedDashboardsByPath(fr.dashboardProvisioningService, fr.Cfg.Name)
+	if err != nil {
+		return err
+	}
+
+	filesFoundOnDisk := map[string]os.FileInfo{}
+	if err := fr.walkFiles(resolvedPath, filesFoundOnDisk); err != nil {
+		return err
+	}
+	if fr.inCanary() {
+		fr.filterCanaryMatches(resolvedPath, filesFoundOnDisk, provisionedDashboardRefs)
+	}
+	for p := range filesFoundOnDisk {
+		if !matchesIntervalRules(rules, resolvedPath, p) {
+			delete(filesFoundOnDisk, p)
+		}
+	}
+	if len(filesFoundOnDisk) == 0 {
+		return nil
+	}
+
+	fr.log.Debug("checking frequently polled dashboard files", "files", len(filesFoundOnDisk))
+	usageTracker := newUsageTracker()
+	if fr.FoldersFromFilesStructure {
+		return fr.storeDashboardsInFoldersFromFileStructure(ctx, filesFoundOnDisk, provisionedDashboardRefs, resolvedPath, usageTracker)
+	}
+	return fr.storeDashboardsInFolder(ctx, filesFoundOnDisk, provisionedDashboardRefs, usageTracker)
+}
+
+func matchesIntervalRules(rules []intervalRule, resolvedPath, p string) bool {
+	relativePath, err := filepath.Rel(resolvedPath, p)
+	if err != nil {
+		return false
+	}
+	relativePath = filepath.ToSlash(relativePath)
+
+	for _, rule := range rules {
+		if matchGlob(rule.pattern, relativePath) {
+			return true
+		}
+	}
+	return false
+}
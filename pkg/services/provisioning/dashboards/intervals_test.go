@@ -0,0 +1,98 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestIntervalRules(t *testing.T) {
+	newConfig := func(options map[string]interface{}) *config {
+		return &config{Name: configName, Type: "file", OrgID: 1, UpdateIntervalSeconds: 60, Options: options}
+	}
+
+	t.Run("should read the rules", func(t *testing.T) {
+		reader, err := NewDashboardFileReader(newConfig(map[string]interface{}{
+			"path": defaultDashboards,
+			"intervalRules": []interface{}{
+				map[string]interface{}{"pattern": "generated/**", "updateIntervalSeconds": 5},
+				map[string]interface{}{"pattern": "*.json", "updateIntervalSeconds": "20"},
+			},
+		}), log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []intervalRule{
+			{pattern: "generated/**", interval: 5 * time.Second},
+			{pattern: "*.json", interval: 20 * time.Second},
+		}, reader.IntervalRules)
+	})
+
+	t.Run("should reject invalid rules", func(t *testing.T) {
+		for _, rule := range []map[string]interface{}{
+			{"pattern": "generated/**"},
+			{"updateIntervalSeconds": 5},
+			{"pattern": "generated/**", "updateIntervalSeconds": 0},
+			{"pattern": "[", "updateIntervalSeconds": 5},
+			{"pattern": "generated/**", "updateIntervalSeconds": 60},
+		} {
+			_, err := NewDashboardFileReader(newConfig(map[string]interface{}{
+				"path":          defaultDashboards,
+				"intervalRules": []interface{}{rule},
+			}), log.New("test-logger"), nil, nil)
+			require.Error(t, err, rule)
+		}
+	})
+
+	t.Run("should only return the rules whose interval elapsed", func(t *testing.T) {
+		start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		schedule := newIntervalSchedule([]intervalRule{
+			{pattern: "fast/**", interval: 5 * time.Second},
+			{pattern: "medium/**", interval: 10 * time.Second},
+		}, start)
+		require.Equal(t, 5*time.Second, schedule.tick)
+
+		require.Equal(t, []intervalRule{{pattern: "fast/**", interval: 5 * time.Second}}, schedule.due(start.Add(5*time.Second)))
+		require.Len(t, schedule.due(start.Add(10*time.Second)), 2)
+		require.Empty(t, schedule.due(start.Add(12*time.Second)))
+
+		schedule.checkedAll(start.Add(14 * time.Second))
+		require.Empty(t, schedule.due(start.Add(16*time.Second)))
+	})
+
+	t.Run("should only provision the files matching the rules", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "generated"), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "generated", "cpu.json"), []byte(`{"title": "CPU", "uid": "cpu"}`), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "memory.json"), []byte(`{"title": "Memory", "uid": "memory"}`), 0600))
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+
+		var saved []string
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{}, nil).
+			Run(func(args mock.Arguments) {
+				saved = append(saved, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Uid)
+			})
+
+		reader, err := NewDashboardFileReader(newConfig(map[string]interface{}{
+			"path": dir,
+			"intervalRules": []interface{}{
+				map[string]interface{}{"pattern": "generated/**", "updateIntervalSeconds": 5},
+			},
+		}), log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		require.NoError(t, reader.walkHotFiles(context.Background(), reader.IntervalRules))
+		require.Equal(t, []string{"cpu"}, saved)
+	})
+}
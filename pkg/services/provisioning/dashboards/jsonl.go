@@ -0,0 +1,102 @@
+package dashboards
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// jsonLinesSuffix is the suffix of the files holding one dashboard per line.
+const jsonLinesSuffix = ".jsonl"
+
+// jsonLineSeparator separates the path of a JSON Lines file from the key of one of its lines in the paths their
+// dashboards are provisioned from, e.g. `generated.jsonl#cpu`. The key is the uid of the dashboard, or its line
+// number for dashboards without uid, so that editing or removing a line only affects its own dashboard.
+const jsonLineSeparator = "#"
+
+func isJSONLinesFile(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), jsonLinesSuffix)
+}
+
+// jsonLineInfo is the file info of a single line of a JSON Lines file. It carries the content of the line, which
+// is read along with the whole file when walking the path of the provider.
+type jsonLineInfo struct {
+	os.FileInfo
+	// file is the path of the JSON Lines file holding the line.
+	file    string
+	name    string
+	content []byte
+}
+
+func (i *jsonLineInfo) Name() string { return i.name }
+func (i *jsonLineInfo) Size() int64  { return int64(len(i.content)) }
+
+// splitJSONLinePath returns the path of the JSON Lines file and the key of the line for the path of a line, or
+// path and an empty key for any other file.
+func splitJSONLinePath(path string) (string, string) {
+	i := strings.LastIndex(strings.ToLower(path), jsonLinesSuffix+jsonLineSeparator)
+	if i < 0 {
+		return path, ""
+	}
+	return path[:i+len(jsonLinesSuffix)], path[i+len(jsonLinesSuffix+jsonLineSeparator):]
+}
+
+// expandJSONLines replaces the JSON Lines files found on disk with one entry per non-empty line.
+func (fr *FileReader) expandJSONLines(filesOnDisk map[string]os.FileInfo) error {
+	for path := range filesOnDisk {
+		if !isJSONLinesFile(path) {
+			continue
+		}
+		delete(filesOnDisk, path)
+
+		// the lines are tracked under the modification time of their file rather than the one of a symlink
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for number, line := range bytes.Split(content, []byte("\n")) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+
+			key := jsonLineKey(line, number+1)
+			linePath := path + jsonLineSeparator + key
+			if _, exists := filesOnDisk[linePath]; exists {
+				fr.log.Warn("skipping line of JSON Lines file, its uid is used by a previous line", "file", path,
+					"line", number+1, "uid", key)
+				continue
+			}
+			filesOnDisk[linePath] = &jsonLineInfo{
+				FileInfo: fileInfo,
+				file:     path,
+				name:     filepath.Base(linePath),
+				content:  line,
+			}
+		}
+	}
+	return nil
+}
+
+// jsonLineKey returns the uid of the dashboard on the line, or its line number when it has no uid usable in a path.
+func jsonLineKey(line []byte, number int) string {
+	var dashboard struct {
+		UID string `json:"uid"`
+	}
+	if err := json.Unmarshal(line, &dashboard); err == nil && dashboard.UID != "" &&
+		!strings.ContainsAny(dashboard.UID, "/\\"+jsonLineSeparator) {
+		return dashboard.UID
+	}
+	return strconv.Itoa(number)
+}
@@ -0,0 +1,82 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestJSONLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "generated.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"title": "CPU", "uid": "cpu"}
+{"title": "Memory", "uid": "memory"}
+
+{"title": "Disk"}
+`), 0600))
+
+	t.Run("should split paths of lines", func(t *testing.T) {
+		file, key := splitJSONLinePath(path + "#cpu")
+		require.Equal(t, path, file)
+		require.Equal(t, "cpu", key)
+
+		file, key = splitJSONLinePath(path)
+		require.Equal(t, path, file)
+		require.Empty(t, key)
+	})
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": dir}}
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	var provisioned []*models.DashboardProvisioning
+	saved := map[string]string{}
+	fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+		Return(&models.Dashboard{}, nil).
+		Run(func(args mock.Arguments) {
+			dp := args.Get(2).(*models.DashboardProvisioning)
+			dp.DashboardId = int64(len(provisioned) + 1)
+			provisioned = append(provisioned, dp)
+			saved[dp.ExternalId] = args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Title
+		})
+
+	t.Run("should provision every line as a dashboard", func(t *testing.T) {
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.Equal(t, map[string]string{path + "#cpu": "CPU", path + "#memory": "Memory", path + "#4": "Disk"}, saved)
+	})
+
+	t.Run("should only update the changed lines and delete the removed ones", func(t *testing.T) {
+		// the dashboard without uid is kept on the same line, which identifies it
+		require.NoError(t, os.WriteFile(path, []byte(`{"title": "CPU usage", "uid": "cpu"}
+
+
+{"title": "Disk"}
+`), 0600))
+		previous := provisioned
+		saved = map[string]string{}
+
+		var memoryID int64
+		for _, dp := range previous {
+			if dp.ExternalId == path+"#memory" {
+				memoryID = dp.DashboardId
+			}
+		}
+		fakeService.On("GetProvisionedDashboardData", configName).Return(previous, nil).Once()
+		fakeService.On("DeleteProvisionedDashboard", mock.Anything, memoryID, int64(1)).Return(nil).Once()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.Equal(t, map[string]string{path + "#cpu": "CPU usage"}, saved)
+	})
+}
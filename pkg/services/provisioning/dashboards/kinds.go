@@ -0,0 +1,50 @@
+package dashboards
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// defaultDashboardKind is the kind of the dashboard files without a `kind` field.
+const defaultDashboardKind = "dashboard"
+
+// dashboardKind returns the kind of a dashboard file, read from its top level `kind` field and compared case
+// insensitively, so that directories mixing several kinds of files can be split between providers.
+func dashboardKind(data *simplejson.Json) string {
+	kind := strings.ToLower(strings.TrimSpace(data.Get("kind").MustString()))
+	if kind == "" {
+		return defaultDashboardKind
+	}
+	return kind
+}
+
+// kindsOption reads a list of kinds, lowercased so that they match the ones returned by dashboardKind.
+func kindsOption(options map[string]interface{}, key string) ([]string, error) {
+	kinds, _, err := stringListOption(options, key)
+	if err != nil {
+		return nil, err
+	}
+	for i, kind := range kinds {
+		kinds[i] = strings.ToLower(strings.TrimSpace(kind))
+	}
+	return kinds, nil
+}
+
+// isKindAllowed reports whether the files of the given kind are provisioned, which is the case when the kind is
+// listed in allowedKinds, if set, and isn't listed in deniedKinds.
+func (fr *FileReader) isKindAllowed(kind string) bool {
+	if len(fr.AllowedKinds) > 0 && !containsString(fr.AllowedKinds, kind) {
+		return false
+	}
+	return !containsString(fr.DeniedKinds, kind)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
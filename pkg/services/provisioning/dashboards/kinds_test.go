@@ -0,0 +1,54 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestDashboardKinds(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.json"), []byte(`{"title": "CPU", "uid": "cpu"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.json"), []byte(`{"title": "Report", "uid": "report", "kind": "Report"}`), 0600))
+
+	provision := func(t *testing.T, options map[string]interface{}) []string {
+		t.Helper()
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+
+		var saved []string
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{}, nil).
+			Run(func(args mock.Arguments) {
+				saved = append(saved, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Uid)
+			})
+
+		options["path"] = dir
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		require.NoError(t, reader.walkDisk(context.Background()))
+		return saved
+	}
+
+	t.Run("should provision every kind by default", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"cpu", "report"}, provision(t, map[string]interface{}{}))
+	})
+
+	t.Run("should only provision allowed kinds", func(t *testing.T) {
+		require.Equal(t, []string{"report"}, provision(t, map[string]interface{}{"allowedKinds": []interface{}{"report"}}))
+	})
+
+	t.Run("should not provision denied kinds", func(t *testing.T) {
+		require.Equal(t, []string{"cpu"}, provision(t, map[string]interface{}{"deniedKinds": []interface{}{"REPORT"}}))
+	})
+}
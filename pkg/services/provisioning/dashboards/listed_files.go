@@ -0,0 +1,54 @@
+package dashboards
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// filesOption reads the files option, the explicit list of dashboard files to provision instead of walking the path
+// of the provider.
+func filesOption(options map[string]interface{}) ([]string, error) {
+	files, _, err := stringListOption(options, "files")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if file == "" {
+			return nil, fmt.Errorf("option %q can't hold empty paths", "files")
+		}
+	}
+	return files, nil
+}
+
+// collectListedFiles adds the files listed in the files option to filesOnDisk, rather than every file found under
+// resolvedPath. Relative paths are resolved against resolvedPath, under which every file must be stored. A listed
+// file which doesn't exist is an error, unless IgnoreMissingListed is set, in which case it's logged and left out.
+func (fr *FileReader) collectListedFiles(resolvedPath string, filesOnDisk map[string]os.FileInfo) error {
+	for _, file := range fr.Files {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(resolvedPath, path)
+		}
+		relativePath, err := filepath.Rel(resolvedPath, path)
+		if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("%w: %s", ErrFileOutsidePath, file)
+		}
+
+		fileInfo, err := os.Lstat(path)
+		if os.IsNotExist(err) && fr.IgnoreMissingListed {
+			fr.log.Warn("listed dashboard file is missing", "file", path)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read listed dashboard file: %w", err)
+		}
+		if fileInfo.IsDir() {
+			return fmt.Errorf("listed dashboard file %s is a directory", path)
+		}
+
+		filesOnDisk[path] = fileInfo
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package dashboards
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestListedFiles(t *testing.T) {
+	absPath, err := filepath.Abs(defaultDashboards)
+	require.NoError(t, err)
+
+	newReader := func(t *testing.T, options map[string]interface{}) *FileReader {
+		options["path"] = defaultDashboards
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		return reader
+	}
+
+	t.Run("should only collect the listed files", func(t *testing.T) {
+		reader := newReader(t, map[string]interface{}{"files": []interface{}{"dashboard1.json"}})
+
+		files := map[string]os.FileInfo{}
+		require.NoError(t, reader.walkFiles(absPath, files))
+		require.Len(t, files, 1)
+		require.Contains(t, files, filepath.Join(absPath, "dashboard1.json"))
+	})
+
+	t.Run("should fail on a missing listed file", func(t *testing.T) {
+		reader := newReader(t, map[string]interface{}{"files": []interface{}{"dashboard1.json", "missing.json"}})
+
+		require.Error(t, reader.walkFiles(absPath, map[string]os.FileInfo{}))
+	})
+
+	t.Run("should skip missing listed files when told so", func(t *testing.T) {
+		reader := newReader(t, map[string]interface{}{
+			"files":               []interface{}{"dashboard1.json", "missing.json"},
+			"ignoreMissingListed": true,
+		})
+
+		files := map[string]os.FileInfo{}
+		require.NoError(t, reader.walkFiles(absPath, files))
+		require.Len(t, files, 1)
+	})
+
+	t.Run("should refuse files outside of the path", func(t *testing.T) {
+		reader := newReader(t, map[string]interface{}{"files": []interface{}{"../one-dashboard/dashboard1.json"}})
+
+		err := reader.walkFiles(absPath, map[string]os.FileInfo{})
+		require.True(t, errors.Is(err, ErrFileOutsidePath))
+	})
+}
@@ -0,0 +1,203 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// provisioningLockFile is the name of the advisory lock file, stored in the provisioned directory, which lets
+// Grafana instances sharing a directory take turns provisioning it.
+const provisioningLockFile = ".provisioning.lock"
+
+// defaultLockTTL is used when neither `lockTTLSeconds` nor the update interval is set.
+const defaultLockTTL = 30 * time.Second
+
+type provisioningLock struct {
+	Instance string `json:"instance"`
+	Expires  int64  `json:"expires"`
+}
+
+// newInstanceID returns an identifier for the running Grafana instance, readable enough to be useful when
+// looking at a lock file.
+func newInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), util.GenerateShortUID())
+}
+
+// acquireLock takes or renews the lock of the provisioned directory. It returns false along with the current
+// lock when the directory is locked by another instance. The lock file is created exclusively, so that only one
+// of the instances racing for a free directory gets it, and locks which expired are broken before taking them.
+func (fr *FileReader) acquireLock(resolvedPath string, now time.Time) (bool, provisioningLock, error) {
+	path := filepath.Join(resolvedPath, provisioningLockFile)
+	lock := provisioningLock{Instance: fr.instanceID, Expires: now.Add(fr.LockTTL).Unix()}
+
+	created, err := createLock(path, lock)
+	if err != nil {
+		return false, provisioningLock{}, err
+	}
+	if created {
+		return true, lock, nil
+	}
+
+	current, err := readLock(path)
+	if err != nil {
+		return false, provisioningLock{}, err
+	}
+	if current.Instance == fr.instanceID {
+		if err := renewLock(resolvedPath, path, lock); err != nil {
+			return false, provisioningLock{}, err
+		}
+		return true, lock, nil
+	}
+
+	expires, err := fr.lockExpiry(path, current)
+	if err != nil {
+		return false, provisioningLock{}, err
+	}
+	if now.Unix() < expires {
+		return false, current, nil
+	}
+
+	broken, err := breakLock(path, current, fr.instanceID)
+	if err != nil || !broken {
+		return false, current, err
+	}
+	created, err = createLock(path, lock)
+	if err != nil {
+		return false, provisioningLock{}, err
+	}
+	if !created {
+		// another instance broke the stale lock too and was faster to take it
+		current, err = readLock(path)
+		return false, current, err
+	}
+	return true, lock, nil
+}
+
+// lockExpiry returns when the lock read from path expires. A lock which could not be parsed may still be being
+// written by the instance which created it, so it only expires one TTL after it was last modified.
+func (fr *FileReader) lockExpiry(path string, lock provisioningLock) (int64, error) {
+	if lock.Instance != "" {
+		return lock.Expires, nil
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.ModTime().Add(fr.LockTTL).Unix(), nil
+}
+
+// createLock writes the lock file at path if it does not exist yet. It returns false when the file already exists.
+func createLock(path string, lock provisioningLock) (bool, error) {
+	raw, err := json.Marshal(lock)
+	if err != nil {
+		return false, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, err := file.Write(raw); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return false, err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(path)
+		return false, err
+	}
+	return true, nil
+}
+
+// renewLock replaces the lock file at path, which the instance already holds, with lock.
+func renewLock(resolvedPath string, path string, lock provisioningLock) error {
+	raw, err := json.Marshal(lock)
+	if err != nil {
+		return err
+	}
+
+	// write the lock next to its final location and rename it, so that other instances never read a partial file
+	tmp, err := ioutil.TempFile(resolvedPath, provisioningLockFile+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// breakLock removes the expired lock at path. The lock is first moved aside, so that a lock another instance took
+// in the meantime is put back rather than removed. It returns false when the lock was not the expired one.
+func breakLock(path string, expired provisioningLock, instanceID string) (bool, error) {
+	aside := path + ".stale-" + instanceID
+	if err := os.Rename(path, aside); err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer func() { _ = os.Remove(aside) }()
+
+	moved, err := readLock(aside)
+	if err != nil {
+		return false, err
+	}
+	if moved != expired {
+		// a fresh lock was moved aside, restore it unless yet another lock was created since
+		if err := os.Link(aside, path); err != nil && !os.IsExist(err) {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// readLock reads the lock file at path. A missing or unreadable lock is reported as an empty lock, so that it
+// can be taken over.
+func readLock(path string) (provisioningLock, error) {
+	var lock provisioningLock
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return lock, err
+	}
+
+	if err := json.Unmarshal(raw, &lock); err != nil {
+		return provisioningLock{}, nil
+	}
+	return lock, nil
+}
@@ -0,0 +1,82 @@
+package dashboards
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestProvisioningLock(t *testing.T) {
+	dir := t.TempDir()
+	newReader := func() *FileReader {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":           dir,
+			"lockFile":       true,
+			"lockTTLSeconds": 60,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		return reader
+	}
+	first, second := newReader(), newReader()
+	now := time.Now()
+
+	t.Run("should be acquired when nobody holds it", func(t *testing.T) {
+		acquired, _, err := first.acquireLock(dir, now)
+		require.NoError(t, err)
+		require.True(t, acquired)
+	})
+
+	t.Run("should be renewed by its holder", func(t *testing.T) {
+		acquired, _, err := first.acquireLock(dir, now.Add(30*time.Second))
+		require.NoError(t, err)
+		require.True(t, acquired)
+	})
+
+	t.Run("should not be acquired by other instances before it expires", func(t *testing.T) {
+		acquired, lock, err := second.acquireLock(dir, now.Add(60*time.Second))
+		require.NoError(t, err)
+		require.False(t, acquired)
+		require.Equal(t, first.instanceID, lock.Instance)
+	})
+
+	t.Run("should be taken over once expired", func(t *testing.T) {
+		acquired, _, err := second.acquireLock(dir, now.Add(91*time.Second))
+		require.NoError(t, err)
+		require.True(t, acquired)
+	})
+
+	t.Run("should be taken over by a single instance once expired", func(t *testing.T) {
+		third := newReader()
+		later := now.Add(200 * time.Second)
+		acquiredByFirst, _, err := first.acquireLock(dir, later)
+		require.NoError(t, err)
+		acquiredByThird, lock, err := third.acquireLock(dir, later)
+		require.NoError(t, err)
+		require.True(t, acquiredByFirst)
+		require.False(t, acquiredByThird)
+		require.Equal(t, first.instanceID, lock.Instance)
+	})
+
+	t.Run("should not break a lock which is still being written", func(t *testing.T) {
+		lockDir := t.TempDir()
+		path := filepath.Join(lockDir, provisioningLockFile)
+		require.NoError(t, ioutil.WriteFile(path, []byte("{"), 0600))
+
+		acquired, _, err := first.acquireLock(lockDir, time.Now())
+		require.NoError(t, err)
+		require.False(t, acquired)
+
+		acquired, _, err = first.acquireLock(lockDir, time.Now().Add(2*time.Minute))
+		require.NoError(t, err)
+		require.True(t, acquired)
+		_, err = os.Stat(path + ".stale-" + first.instanceID)
+		require.True(t, os.IsNotExist(err))
+	})
+}
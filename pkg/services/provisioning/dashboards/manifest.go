@@ -0,0 +1,155 @@
+package dashboards
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// readManifest parses a manifest in the format produced by `md5sum`, where each line holds the
+// checksum of a dashboard file, followed by two spaces, or by a space and a '*' for files read in binary mode,
+// and its path relative to the provisioning path. Paths may contain spaces.
+func readManifest(path string) (map[string]string, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	entries := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		checkSum, name, ok := parseManifestLine(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid manifest entry on line %d", lineNumber)
+		}
+		entries[filepath.FromSlash(name)] = checkSum
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// parseManifestLine splits a manifest line into the checksum and the path of a file.
+func parseManifestLine(line string) (string, string, bool) {
+	separator := strings.IndexByte(line, ' ')
+	if separator <= 0 || len(line) < separator+3 {
+		return "", "", false
+	}
+	if mode := line[separator+1]; mode != ' ' && mode != '*' {
+		return "", "", false
+	}
+	return line[:separator], line[separator+2:], true
+}
+
+// manifestChecksum is the checksum of a file listed in the manifest, along with the modification time and size the
+// file had when it was computed.
+type manifestChecksum struct {
+	modTime  time.Time
+	size     int64
+	checkSum string
+}
+
+// verifyManifest checks that the files found on disk are exactly the ones listed in the manifest and that their
+// content matches the expected checksums. The checksums are only computed again for files which changed since the
+// previous run.
+func (fr *FileReader) verifyManifest(resolvedPath string, filesFoundOnDisk map[string]os.FileInfo) error {
+	manifestPath := fr.Manifest
+	if !filepath.IsAbs(manifestPath) {
+		manifestPath = filepath.Join(resolvedPath, manifestPath)
+	}
+
+	entries, err := readManifest(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest %q: %w", manifestPath, err)
+	}
+
+	files := map[string]bool{}
+	for path := range filesFoundOnDisk {
+		// the lines of a JSON Lines file are covered by the checksum of the file
+		file, _ := splitJSONLinePath(path)
+		files[file] = true
+	}
+	for file := range files {
+		relativePath, err := filepath.Rel(resolvedPath, file)
+		if err != nil {
+			return err
+		}
+		if _, listed := entries[relativePath]; !listed {
+			return fmt.Errorf("file %q is not listed in manifest", relativePath)
+		}
+	}
+
+	checkSums := make(map[string]manifestChecksum, len(entries))
+	for relativePath, expected := range entries {
+		path := filepath.Join(resolvedPath, relativePath)
+		if !files[path] {
+			return fmt.Errorf("file %q listed in manifest is missing", relativePath)
+		}
+
+		checkSum, err := fr.manifestFileChecksum(path)
+		if err != nil {
+			return fmt.Errorf("failed to compute checksum of %q: %w", relativePath, err)
+		}
+		checkSums[path] = checkSum
+
+		if !strings.EqualFold(checkSum.checkSum, expected) {
+			return fmt.Errorf("checksum of %q does not match manifest", relativePath)
+		}
+	}
+	fr.manifestChecksums = checkSums
+
+	return nil
+}
+
+// manifestFileChecksum returns the checksum of a file listed in the manifest, reusing the one computed by the
+// previous run if the file didn't change since.
+func (fr *FileReader) manifestFileChecksum(path string) (manifestChecksum, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return manifestChecksum{}, err
+	}
+	if cached, ok := fr.manifestChecksums[path]; ok && cached.modTime.Equal(fileInfo.ModTime()) &&
+		cached.size == fileInfo.Size() {
+		return cached, nil
+	}
+
+	checkSum, err := fileCheckSum(path)
+	if err != nil {
+		return manifestChecksum{}, err
+	}
+	return manifestChecksum{modTime: fileInfo.ModTime(), size: fileInfo.Size(), checkSum: checkSum}, nil
+}
+
+func fileCheckSum(path string) (string, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return util.Md5Sum(file)
+}
@@ -0,0 +1,125 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestManifest(t *testing.T) {
+	const dashboardJSON = `{"title": "Manifest Dashboard"}`
+
+	setup := func(t *testing.T, manifest string) *FileReader {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboard.json"), []byte(dashboardJSON), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "MANIFEST"), []byte(manifest), 0600))
+
+		cfg := &config{
+			Name:    configName,
+			Type:    "file",
+			OrgID:   1,
+			Options: map[string]interface{}{"path": dir, "manifest": "MANIFEST"},
+		}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, &fakeDashboardStore{})
+		require.NoError(t, err)
+		return reader
+	}
+
+	checkSum, err := util.Md5SumString(dashboardJSON)
+	require.NoError(t, err)
+
+	t.Run("should provision when manifest matches", func(t *testing.T) {
+		reader := setup(t, checkSum+"  dashboard.json\n")
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Once()
+		reader.dashboardProvisioningService = fakeService
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+	})
+
+	t.Run("should skip the run when checksum does not match", func(t *testing.T) {
+		reader := setup(t, "00000000000000000000000000000000  dashboard.json\n")
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		reader.dashboardProvisioningService = fakeService
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+	})
+
+	t.Run("should skip the run when a listed file is missing", func(t *testing.T) {
+		reader := setup(t, checkSum+"  dashboard.json\n"+checkSum+"  missing.json\n")
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		reader.dashboardProvisioningService = fakeService
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+	})
+
+	t.Run("should skip the run when a file isn't listed", func(t *testing.T) {
+		reader := setup(t, checkSum+"  dashboard.json\n")
+		require.NoError(t, os.WriteFile(filepath.Join(reader.Path, "unlisted.json"), []byte(dashboardJSON), 0600))
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		reader.dashboardProvisioningService = fakeService
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+	})
+
+	t.Run("should only compute the checksums of changed files again", func(t *testing.T) {
+		reader := setup(t, checkSum+"  dashboard.json\n")
+		path := filepath.Join(reader.Path, "dashboard.json")
+		fileInfo, err := os.Stat(path)
+		require.NoError(t, err)
+		reader.manifestChecksums = map[string]manifestChecksum{
+			path: {modTime: fileInfo.ModTime(), size: fileInfo.Size(), checkSum: "cached"},
+		}
+
+		cached, err := reader.manifestFileChecksum(path)
+		require.NoError(t, err)
+		require.Equal(t, "cached", cached.checkSum)
+
+		reader.manifestChecksums[path] = manifestChecksum{checkSum: "stale"}
+		computed, err := reader.manifestFileChecksum(path)
+		require.NoError(t, err)
+		require.Equal(t, checkSum, computed.checkSum)
+	})
+}
+
+func TestReadManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "MANIFEST")
+	manifest := "# generated\n" +
+		"d41d8cd98f00b204e9800998ecf8427e  team a/cpu usage.json\n" +
+		"0cc175b9c0f1b6a831c399e269772661 *memory.json.gz\r\n"
+	require.NoError(t, os.WriteFile(path, []byte(manifest), 0600))
+
+	entries, err := readManifest(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		filepath.FromSlash("team a/cpu usage.json"): "d41d8cd98f00b204e9800998ecf8427e",
+		"memory.json.gz": "0cc175b9c0f1b6a831c399e269772661",
+	}, entries)
+
+	for _, line := range []string{"d41d8cd98f00b204e9800998ecf8427e", "d41d8cd98f00b204e9800998ecf8427e cpu.json", " cpu.json"} {
+		require.NoError(t, os.WriteFile(path, []byte(line+"\n"), 0600))
+		_, err := readManifest(path)
+		require.Error(t, err, line)
+	}
+}
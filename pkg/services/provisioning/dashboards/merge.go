@@ -0,0 +1,67 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+const (
+	mergeStrategyReplace = "replace"
+	mergeStrategyMerge   = "merge"
+)
+
+// mergeWithStored merges the dashboard read from a file onto the version stored in the database, so that the
+// properties missing from the file are kept. Dashboards which aren't stored yet are left untouched.
+func (fr *FileReader) mergeWithStored(ctx context.Context, dash *dashboards.SaveDashboardDTO, provisionedData *models.DashboardProvisioning) error {
+	query := &models.GetDashboardQuery{OrgId: dash.OrgId, Uid: dash.Dashboard.Uid}
+	if provisionedData != nil {
+		query = &models.GetDashboardQuery{OrgId: dash.OrgId, Id: provisionedData.DashboardId}
+	}
+	if query.Id == 0 && query.Uid == "" {
+		return nil
+	}
+
+	if err := fr.dashboardStore.GetDashboard(ctx, query); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get stored dashboard to merge with: %w", err)
+	}
+
+	stored, err := query.Result.Data.Map()
+	if err != nil {
+		return fmt.Errorf("failed to read stored dashboard to merge with: %w", err)
+	}
+	provisioned, err := dash.Dashboard.Data.Map()
+	if err != nil {
+		return err
+	}
+
+	merged := simplejson.NewFromAny(mergeJSON(stored, provisioned))
+	// the id is handled by the caller, the stored one must not leak into the saved dashboard
+	merged.Del("id")
+	dash.Dashboard.Data = merged
+	dash.Dashboard.Uid = merged.Get("uid").MustString()
+
+	return nil
+}
+
+// mergeJSON deep merges src onto dst. Objects are merged key by key, while arrays and scalars of src replace
+// the ones of dst.
+func mergeJSON(dst, src map[string]interface{}) map[string]interface{} {
+	for key, srcValue := range src {
+		srcObject, srcIsObject := srcValue.(map[string]interface{})
+		dstObject, dstIsObject := dst[key].(map[string]interface{})
+		if srcIsObject && dstIsObject {
+			dst[key] = mergeJSON(dstObject, srcObject)
+			continue
+		}
+		dst[key] = srcValue
+	}
+	return dst
+}
@@ -0,0 +1,88 @@
+package dashboards
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestMergeJSON(t *testing.T) {
+	dst := map[string]interface{}{
+		"title":       "Stored",
+		"description": "managed in the UI",
+		"tags":        []interface{}{"a", "b"},
+		"time":        map[string]interface{}{"from": "now-6h", "to": "now"},
+	}
+	src := map[string]interface{}{
+		"title": "Provisioned",
+		"tags":  []interface{}{"c"},
+		"time":  map[string]interface{}{"from": "now-1h"},
+	}
+
+	require.Equal(t, map[string]interface{}{
+		"title":       "Provisioned",
+		"description": "managed in the UI",
+		"tags":        []interface{}{"c"},
+		"time":        map[string]interface{}{"from": "now-1h", "to": "now"},
+	}, mergeJSON(dst, src))
+}
+
+func TestMergeWithStored(t *testing.T) {
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path":          oneDashboard,
+		"mergeStrategy": "merge",
+	}}
+
+	t.Run("should merge onto the stored dashboard", func(t *testing.T) {
+		stored := simplejson.NewFromAny(map[string]interface{}{
+			"id": 3, "uid": "stored", "title": "Stored", "description": "managed in the UI",
+		})
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, &fixedDashboardStore{dashboard: &models.Dashboard{Data: stored}})
+		require.NoError(t, err)
+
+		dash := &dashboards.SaveDashboardDTO{OrgId: 1, Dashboard: models.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{
+			"title": "Provisioned",
+		}))}
+		require.NoError(t, reader.mergeWithStored(context.Background(), dash, &models.DashboardProvisioning{DashboardId: 3}))
+
+		require.Equal(t, "stored", dash.Dashboard.Uid)
+		require.Equal(t, "Provisioned", dash.Dashboard.Data.Get("title").MustString())
+		require.Equal(t, "managed in the UI", dash.Dashboard.Data.Get("description").MustString())
+		_, hasID := dash.Dashboard.Data.CheckGet("id")
+		require.False(t, hasID)
+	})
+
+	t.Run("should keep new dashboards untouched", func(t *testing.T) {
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		data := simplejson.NewFromAny(map[string]interface{}{"uid": "new", "title": "Provisioned"})
+		dash := &dashboards.SaveDashboardDTO{OrgId: 1, Dashboard: models.NewDashboardFromJson(data)}
+		require.NoError(t, reader.mergeWithStored(context.Background(), dash, nil))
+		require.Same(t, data, dash.Dashboard.Data)
+	})
+
+	t.Run("should reject unknown strategies", func(t *testing.T) {
+		_, err := NewDashboardFileReader(&config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":          oneDashboard,
+			"mergeStrategy": "patch",
+		}}, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
+
+// fixedDashboardStore returns the same dashboard whatever the query.
+type fixedDashboardStore struct {
+	dashboard *models.Dashboard
+}
+
+func (sds *fixedDashboardStore) GetDashboard(_ context.Context, query *models.GetDashboardQuery) error {
+	query.Result = sds.dashboard
+	return nil
+}
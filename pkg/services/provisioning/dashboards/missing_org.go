@@ -0,0 +1,76 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+const (
+	onMissingOrgError  = "error"
+	onMissingOrgSkip   = "skip"
+	onMissingOrgCreate = "create"
+)
+
+// orgCreator is implemented by the org stores able to create the organizations referenced by providers.
+type orgCreator interface {
+	CreateOrg(ctx context.Context, cmd *models.CreateOrgCommand) error
+}
+
+// onMissingOrgOption reads the `onMissingOrg` option of a provider, which defaults to failing the provisioning.
+func onMissingOrgOption(options map[string]interface{}) (string, error) {
+	onMissingOrg, _ := options["onMissingOrg"].(string)
+	switch onMissingOrg {
+	case "":
+		return onMissingOrgError, nil
+	case onMissingOrgError, onMissingOrgSkip, onMissingOrgCreate:
+		return onMissingOrg, nil
+	default:
+		return "", fmt.Errorf("invalid 'onMissingOrg' option %q, expected one of %q, %q or %q", onMissingOrg,
+			onMissingOrgError, onMissingOrgSkip, onMissingOrgCreate)
+	}
+}
+
+// checkOrgExists checks that the organization referenced by a provider exists, handling missing organizations as
+// configured by its `onMissingOrg` option. It returns false when the provider has to be skipped.
+func (cr *configReader) checkOrgExists(ctx context.Context, dashboard *config, orgID int64, onMissingOrg string) (bool, error) {
+	err := utils.CheckOrgExists(ctx, cr.orgStore, orgID)
+	if !errors.Is(err, models.ErrOrgNotFound) {
+		return err == nil, err
+	}
+
+	switch onMissingOrg {
+	case onMissingOrgSkip:
+		cr.log.Warn("skipping dashboard provider referencing a missing organization", "provider", dashboard.Name, "orgId", orgID)
+		return false, nil
+	case onMissingOrgCreate:
+		if err := cr.createOrg(ctx, orgID); err != nil {
+			return false, err
+		}
+		cr.log.Info("created organization referenced by dashboard provider", "provider", dashboard.Name, "orgId", orgID)
+		return true, nil
+	default:
+		return false, err
+	}
+}
+
+// createOrg creates the organization with the given ID. Organization IDs are assigned by the database, so this only
+// succeeds when the ID is the next one to be assigned, as is the case when organizations are created in order.
+func (cr *configReader) createOrg(ctx context.Context, orgID int64) error {
+	creator, ok := cr.orgStore.(orgCreator)
+	if !ok {
+		return fmt.Errorf("can't create organization %d: %w", orgID, models.ErrOrgNotFound)
+	}
+
+	cmd := models.CreateOrgCommand{Name: fmt.Sprintf("Org. %d", orgID)}
+	if err := creator.CreateOrg(ctx, &cmd); err != nil {
+		return fmt.Errorf("failed to create organization %d: %w", orgID, err)
+	}
+	if cmd.Result.Id != orgID {
+		return fmt.Errorf("failed to create organization %d: it was created with ID %d", orgID, cmd.Result.Id)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// sequentialOrgStore is an org store assigning the IDs of the organizations it creates in sequence.
+type sequentialOrgStore struct {
+	orgs   map[int64]bool
+	nextID int64
+}
+
+func (s *sequentialOrgStore) GetOrgById(_ context.Context, query *models.GetOrgByIdQuery) error {
+	if !s.orgs[query.Id] {
+		return models.ErrOrgNotFound
+	}
+	query.Result = &models.Org{Id: query.Id}
+	return nil
+}
+
+func (s *sequentialOrgStore) CreateOrg(_ context.Context, cmd *models.CreateOrgCommand) error {
+	cmd.Result = models.Org{Id: s.nextID, Name: cmd.Name}
+	s.orgs[s.nextID] = true
+	s.nextID++
+	return nil
+}
+
+func TestOnMissingOrg(t *testing.T) {
+	readConfig := func(t *testing.T, store *sequentialOrgStore, onMissingOrg string) ([]*config, error) {
+		t.Helper()
+		dir := t.TempDir()
+		providers := "apiVersion: 1\nproviders:\n" +
+			"- name: main\n  orgId: 1\n  options:\n    path: /tmp\n" +
+			"- name: tenant\n  orgId: 2\n  options:\n    path: /tmp\n    onMissingOrg: " + onMissingOrg + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboards.yaml"), []byte(providers), 0600))
+
+		cr := configReader{path: dir, log: log.New("test-logger"), orgStore: store}
+		return cr.readConfig(context.Background())
+	}
+	names := func(configs []*config) []string {
+		var names []string
+		for _, cfg := range configs {
+			names = append(names, cfg.Name)
+		}
+		return names
+	}
+
+	t.Run("should fail by default", func(t *testing.T) {
+		_, err := readConfig(t, &sequentialOrgStore{orgs: map[int64]bool{1: true}, nextID: 2}, `""`)
+		require.ErrorIs(t, err, models.ErrOrgNotFound)
+	})
+
+	t.Run("should skip the provider", func(t *testing.T) {
+		configs, err := readConfig(t, &sequentialOrgStore{orgs: map[int64]bool{1: true}, nextID: 2}, onMissingOrgSkip)
+		require.NoError(t, err)
+		require.Equal(t, []string{"main"}, names(configs))
+	})
+
+	t.Run("should create the organization", func(t *testing.T) {
+		store := &sequentialOrgStore{orgs: map[int64]bool{1: true}, nextID: 2}
+		configs, err := readConfig(t, store, onMissingOrgCreate)
+		require.NoError(t, err)
+		require.Equal(t, []string{"main", "tenant"}, names(configs))
+		require.True(t, store.orgs[2])
+	})
+
+	t.Run("should fail when the organization is created with another ID", func(t *testing.T) {
+		_, err := readConfig(t, &sequentialOrgStore{orgs: map[int64]bool{1: true}, nextID: 3}, onMissingOrgCreate)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject invalid values", func(t *testing.T) {
+		_, err := readConfig(t, &sequentialOrgStore{orgs: map[int64]bool{1: true, 2: true}, nextID: 3}, "ignore")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,105 @@
+package dashboards
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// ValidateConfigDirectory checks the dashboard provisioning configs found in configDirectory, and the dashboard
+// files they point at, without requiring a database or a running Grafana instance. It returns every problem
+// found rather than stopping at the first one, so that it can be used as a pre-merge check.
+func ValidateConfigDirectory(configDirectory string) []error {
+	logger := log.New("provisioning.dashboard.validate")
+	cr := &configReader{path: configDirectory, log: logger}
+
+	files, err := ioutil.ReadDir(configDirectory)
+	if err != nil {
+		return []error{fmt.Errorf("can't read dashboard provisioning files from directory %q: %w", configDirectory, err)}
+	}
+
+	var problems []error
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		configs, err := cr.parseConfigs(file)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: could not parse provisioning config file: %w", file.Name(), err))
+			continue
+		}
+
+		for _, cfg := range configs {
+			for _, err := range validateConfig(cfg, logger) {
+				problems = append(problems, fmt.Errorf("%s: provider %q: %w", file.Name(), cfg.Name, err))
+			}
+		}
+	}
+
+	return problems
+}
+
+func validateConfig(cfg *config, logger log.Logger) []error {
+	if cfg.Name == "" {
+		return []error{fmt.Errorf("provider name is required")}
+	}
+
+	if cfg.Type != "" && cfg.Type != "file" {
+		return []error{fmt.Errorf("type %s is not supported", cfg.Type)}
+	}
+
+	reader, err := NewDashboardFileReader(cfg, logger.New("name", cfg.Name), nil, nil)
+	if err != nil {
+		return []error{err}
+	}
+
+	resolvedPath := reader.resolvedPath()
+	if _, err := os.Stat(resolvedPath); err != nil {
+		return []error{err}
+	}
+
+	filesFoundOnDisk := map[string]os.FileInfo{}
+	if err := reader.walkFiles(resolvedPath, filesFoundOnDisk); err != nil {
+		return []error{fmt.Errorf("failed to search for dashboards: %w", err)}
+	}
+
+	paths := make([]string, 0, len(filesFoundOnDisk))
+	for path := range filesFoundOnDisk {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var problems []error
+	for _, path := range paths {
+		if err := reader.validateDashboardFile(path, filesFoundOnDisk[path]); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+
+	return problems
+}
+
+// validateDashboardFile reads the dashboard file at path the same way it would be provisioned.
+func (fr *FileReader) validateDashboardFile(path string, fileInfo os.FileInfo) error {
+	resolvedFileInfo, err := fr.resolveSymlink(fileInfo, path)
+	if err != nil {
+		return err
+	}
+
+	jsonFile, err := fr.readDashboardFromFile(path, resolvedFileInfo, 0)
+	if err != nil {
+		return err
+	}
+
+	if jsonFile.deleted {
+		return nil
+	}
+
+	_, err = resolveFolderOverride(path, jsonFile)
+	return err
+}
@@ -0,0 +1,48 @@
+package dashboards
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigDirectory(t *testing.T) {
+	writeConfig := func(t *testing.T, content string) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboards.yaml"), []byte(content), 0600))
+		return dir
+	}
+
+	providerConfig := func(t *testing.T, path string) string {
+		absPath, err := filepath.Abs(path)
+		require.NoError(t, err)
+		return fmt.Sprintf("apiVersion: 1\nproviders:\n  - name: default\n    options:\n      path: %s\n", absPath)
+	}
+
+	t.Run("should not report problems for valid configs", func(t *testing.T) {
+		dir := writeConfig(t, providerConfig(t, oneDashboard))
+		require.Empty(t, ValidateConfigDirectory(dir))
+	})
+
+	t.Run("should report every broken dashboard file", func(t *testing.T) {
+		dir := writeConfig(t, providerConfig(t, brokenDashboards))
+		require.Len(t, ValidateConfigDirectory(dir), 2)
+	})
+
+	t.Run("should report configs which can't be parsed", func(t *testing.T) {
+		dir := writeConfig(t, "apiVersion: 1\nproviders: [")
+		require.Len(t, ValidateConfigDirectory(dir), 1)
+	})
+
+	t.Run("should report invalid provider options", func(t *testing.T) {
+		dir := writeConfig(t, "apiVersion: 1\nproviders:\n  - name: default\n    options:\n      path: 1\n")
+		require.Len(t, ValidateConfigDirectory(dir), 1)
+	})
+
+	t.Run("should report missing directory", func(t *testing.T) {
+		require.Len(t, ValidateConfigDirectory(filepath.Join(t.TempDir(), "missing")), 1)
+	})
+}
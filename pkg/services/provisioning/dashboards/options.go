@@ -0,0 +1,177 @@
+package dashboards
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// int64Option reads an integer option. Values can be given as YAML numbers or as strings, the latter
+// being common when they come from environment variable interpolation.
+func int64Option(options map[string]interface{}, key string) (int64, bool, error) {
+	raw, ok := options[key]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+
+	switch v := raw.(type) {
+	case int:
+		return int64(v), true, nil
+	case int64:
+		return v, true, nil
+	case float64:
+		return int64(v), true, nil
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("option %q is not a valid integer: %w", key, err)
+		}
+		return i, true, nil
+	default:
+		return 0, false, fmt.Errorf("option %q is not a valid integer", key)
+	}
+}
+
+// float64Option reads a decimal option, given either as a YAML number or as a string.
+func float64Option(options map[string]interface{}, key string) (float64, bool, error) {
+	raw, ok := options[key]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+
+	switch v := raw.(type) {
+	case int:
+		return float64(v), true, nil
+	case int64:
+		return float64(v), true, nil
+	case float64:
+		return v, true, nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("option %q is not a valid number: %w", key, err)
+		}
+		return f, true, nil
+	default:
+		return 0, false, fmt.Errorf("option %q is not a valid number", key)
+	}
+}
+
+// orgIDMappingOption reads the `orgIdMapping` option, which maps the name of a top level directory to the id
+// of the organization its dashboards are provisioned into.
+func orgIDMappingOption(options map[string]interface{}) (map[string]int64, error) {
+	raw, ok := options["orgIdMapping"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	entries, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("option %q must be a mapping of directory names to organization ids", "orgIdMapping")
+	}
+
+	mapping := make(map[string]int64, len(entries))
+	for dir := range entries {
+		orgID, _, err := int64Option(entries, dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid organization id for directory %q in %q: %w", dir, "orgIdMapping", err)
+		}
+		if orgID <= 0 {
+			return nil, fmt.Errorf("invalid organization id %d for directory %q in %q", orgID, dir, "orgIdMapping")
+		}
+		mapping[dir] = orgID
+	}
+
+	return mapping, nil
+}
+
+// orgIDsOption reads the `orgIds` option, the list of the organizations the dashboards are provisioned into.
+func orgIDsOption(options map[string]interface{}) ([]int64, error) {
+	raw, ok := options["orgIds"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("option %q must be a list of organization ids", "orgIds")
+	}
+
+	orgIDs := make([]int64, 0, len(items))
+	for i, item := range items {
+		orgID, _, err := int64Option(map[string]interface{}{"orgIds": item}, "orgIds")
+		if err != nil {
+			return nil, fmt.Errorf("invalid organization id at index %d of %q: %w", i, "orgIds", err)
+		}
+		if orgID <= 0 {
+			return nil, fmt.Errorf("invalid organization id %d at index %d of %q", orgID, i, "orgIds")
+		}
+		orgIDs = append(orgIDs, orgID)
+	}
+
+	return orgIDs, nil
+}
+
+// stringListOption reads an option holding a list of strings.
+func stringListOption(options map[string]interface{}, key string) ([]string, bool, error) {
+	raw, ok := options[key]
+	if !ok || raw == nil {
+		return nil, false, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf("option %q must be a list of strings", key)
+	}
+
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false, fmt.Errorf("option %q must be a list of strings", key)
+		}
+		list = append(list, s)
+	}
+
+	return list, true, nil
+}
+
+// folderRule provisions the dashboards whose path relative to the provider path matches pattern into folder.
+type folderRule struct {
+	pattern *regexp.Regexp
+	folder  string
+}
+
+// folderRulesOption reads the `folderRules` option, an ordered list of `pattern` and `folder` pairs.
+func folderRulesOption(options map[string]interface{}) ([]folderRule, error) {
+	raw, ok := options["folderRules"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("option %q must be a list of rules", "folderRules")
+	}
+
+	rules := make([]folderRule, 0, len(items))
+	for i, item := range items {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("rule %d of %q must have a pattern and a folder", i, "folderRules")
+		}
+		pattern, _ := entry["pattern"].(string)
+		folder, _ := entry["folder"].(string)
+		if pattern == "" || folder == "" {
+			return nil, fmt.Errorf("rule %d of %q must have a pattern and a folder", i, "folderRules")
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in rule %d of %q: %w", i, "folderRules", err)
+		}
+		rules = append(rules, folderRule{pattern: re, folder: folder})
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,46 @@
+package dashboards
+
+import (
+	"fmt"
+)
+
+// orgProviderName returns the name of the provider provisioning the dashboards of the provider with the given name
+// into a single organization of its `orgIds` option. Provisioned dashboards are tracked by provider name and file,
+// so every organization needs a provider of its own.
+func orgProviderName(name string, orgID int64) string {
+	return fmt.Sprintf("%s (org %d)", name, orgID)
+}
+
+// expandOrgIDs replaces every provider with an `orgIds` option by one provider per listed organization, each
+// provisioning the files of the provider into its organization, with folders created in that organization too.
+func expandOrgIDs(configs []*config) ([]*config, error) {
+	expanded := make([]*config, 0, len(configs))
+	for _, cfg := range configs {
+		orgIDs, err := orgIDsOption(cfg.Options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to provision dashboards with %q reader: %w", cfg.Name, err)
+		}
+		if len(orgIDs) == 0 {
+			expanded = append(expanded, cfg)
+			continue
+		}
+		if orgIDFromPath, _ := cfg.Options["orgIdFromPath"].(bool); orgIDFromPath {
+			return nil, fmt.Errorf("failed to provision dashboards with %q reader: options %q and %q can't be combined",
+				cfg.Name, "orgIds", "orgIdFromPath")
+		}
+
+		seen := map[int64]bool{}
+		for _, orgID := range orgIDs {
+			if seen[orgID] {
+				continue
+			}
+			seen[orgID] = true
+
+			orgCfg := *cfg
+			orgCfg.Name = orgProviderName(cfg.Name, orgID)
+			orgCfg.OrgID = orgID
+			expanded = append(expanded, &orgCfg)
+		}
+	}
+	return expanded, nil
+}
@@ -0,0 +1,57 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestOrgIDs(t *testing.T) {
+	readConfig := func(t *testing.T, options string) ([]*config, error) {
+		t.Helper()
+		dir := t.TempDir()
+		content := "apiVersion: 1\nproviders:\n- name: shared\n  folderUid: shared\n  options:\n    path: /dashboards\n" + options
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboards.yaml"), []byte(content), 0600))
+
+		cfgReader := configReader{path: dir, log: log.New("test-logger"), orgStore: fakeOrgStore{}}
+		return cfgReader.readConfig(context.Background())
+	}
+
+	t.Run("should create a provider per organization", func(t *testing.T) {
+		configs, err := readConfig(t, "    orgIds: [2, \"3\", 2]\n")
+		require.NoError(t, err)
+		require.Len(t, configs, 2)
+
+		require.Equal(t, "shared (org 2)", configs[0].Name)
+		require.Equal(t, int64(2), configs[0].OrgID)
+		require.Equal(t, "shared (org 3)", configs[1].Name)
+		require.Equal(t, int64(3), configs[1].OrgID)
+		require.Equal(t, "shared", configs[1].FolderUID)
+		require.Equal(t, "/dashboards", configs[1].Options["path"])
+	})
+
+	t.Run("should keep providers without organizations as they are", func(t *testing.T) {
+		configs, err := readConfig(t, "")
+		require.NoError(t, err)
+		require.Len(t, configs, 1)
+		require.Equal(t, "shared", configs[0].Name)
+	})
+
+	t.Run("should reject invalid organization ids", func(t *testing.T) {
+		_, err := readConfig(t, "    orgIds: [0]\n")
+		require.Error(t, err)
+
+		_, err = readConfig(t, "    orgIds: 2\n")
+		require.Error(t, err)
+	})
+
+	t.Run("should reject organizations from the path along with the list", func(t *testing.T) {
+		_, err := readConfig(t, "    orgIds: [2]\n    orgIdFromPath: true\n")
+		require.Error(t, err)
+	})
+}
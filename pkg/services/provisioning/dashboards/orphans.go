@@ -0,0 +1,68 @@
+package dashboards
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	orphanStrategyDelete = "delete"
+	orphanStrategyKeep   = "keep"
+	orphanStrategyAdopt  = "adopt"
+)
+
+func validateOrphanStrategy(strategy string) (string, error) {
+	switch strategy {
+	case "":
+		return orphanStrategyDelete, nil
+	case orphanStrategyDelete, orphanStrategyKeep, orphanStrategyAdopt:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("invalid orphan strategy %q, expected one of %q, %q or %q", strategy, orphanStrategyDelete,
+			orphanStrategyKeep, orphanStrategyAdopt)
+	}
+}
+
+// keepOrphanedDashboards unprovisions the dashboards of the readers which no longer exist, so that they are kept as
+// regular dashboards. With the adopt strategy, the dashboards whose file is stored under the path of a current
+// reader are left provisioned instead, as that reader takes them over when it saves them on its next run. The
+// caller must hold mutex.
+func (provider *Provisioner) keepOrphanedDashboards(ctx context.Context, currentReaders []string) {
+	orphans, err := provider.provisioner.GetOrphanedProvisionedDashboards(ctx, currentReaders)
+	if err != nil {
+		provider.log.Warn("Failed to get orphaned provisioned dashboards", "err", err)
+		return
+	}
+
+	for _, orphan := range orphans {
+		if provider.orphanStrategy == orphanStrategyAdopt {
+			if reader := provider.readerCovering(orphan.ExternalId); reader != nil {
+				provider.log.Info("Dashboard of a removed provider is adopted by another provider", "dashboardId", orphan.DashboardId,
+					"file", orphan.ExternalId, "previousProvider", orphan.Name, "provider", reader.Cfg.Name)
+				continue
+			}
+		}
+
+		if err := provider.provisioner.UnprovisionDashboard(ctx, orphan.DashboardId); err != nil {
+			provider.log.Warn("Failed to unprovision orphaned dashboard", "dashboardId", orphan.DashboardId, "err", err)
+			continue
+		}
+		provider.log.Info("Kept dashboard of a removed provider as a regular dashboard", "dashboardId", orphan.DashboardId,
+			"file", orphan.ExternalId, "previousProvider", orphan.Name)
+	}
+}
+
+// readerCovering returns the reader whose path holds the file at path, or nil if there is none. The caller must hold
+// mutex.
+func (provider *Provisioner) readerCovering(path string) *FileReader {
+	for _, reader := range provider.fileReaders {
+		relativePath, err := filepath.Rel(reader.resolvedPath(), path)
+		if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return reader
+	}
+	return nil
+}
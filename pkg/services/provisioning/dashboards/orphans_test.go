@@ -0,0 +1,71 @@
+package dashboards
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestCleanUpOrphanedDashboards(t *testing.T) {
+	dir := t.TempDir()
+	logger := log.New("test-logger")
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": dir}}
+	reader, err := NewDashboardFileReader(cfg, logger, nil, nil)
+	require.NoError(t, err)
+
+	orphans := []*models.DashboardProvisioning{
+		{DashboardId: 1, Name: "removed", ExternalId: filepath.Join(reader.resolvedPath(), "cpu.json")},
+		{DashboardId: 2, Name: "removed", ExternalId: filepath.Join(t.TempDir(), "memory.json")},
+	}
+
+	setup := func(strategy string) (*Provisioner, *dashboards.FakeDashboardProvisioning) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+
+		strategy, err := validateOrphanStrategy(strategy)
+		require.NoError(t, err)
+		return &Provisioner{
+			log:            logger,
+			fileReaders:    []*FileReader{reader},
+			provisioner:    fakeService,
+			orphanStrategy: strategy,
+		}, fakeService
+	}
+
+	t.Run("should delete orphans by default", func(t *testing.T) {
+		provisioner, fakeService := setup("")
+		fakeService.On("DeleteOrphanedProvisionedDashboards", mock.Anything,
+			&models.DeleteOrphanedProvisionedDashboardsCommand{ReaderNames: []string{configName}}).Return(nil).Once()
+
+		provisioner.CleanUpOrphanedDashboards(context.Background())
+	})
+
+	t.Run("should unprovision orphans when keeping them", func(t *testing.T) {
+		provisioner, fakeService := setup(orphanStrategyKeep)
+		fakeService.On("GetOrphanedProvisionedDashboards", mock.Anything, []string{configName}).Return(orphans, nil).Once()
+		fakeService.On("UnprovisionDashboard", mock.Anything, int64(1)).Return(nil).Once()
+		fakeService.On("UnprovisionDashboard", mock.Anything, int64(2)).Return(nil).Once()
+
+		provisioner.CleanUpOrphanedDashboards(context.Background())
+	})
+
+	t.Run("should leave orphans covered by another provider to it when adopting them", func(t *testing.T) {
+		provisioner, fakeService := setup(orphanStrategyAdopt)
+		fakeService.On("GetOrphanedProvisionedDashboards", mock.Anything, []string{configName}).Return(orphans, nil).Once()
+		fakeService.On("UnprovisionDashboard", mock.Anything, int64(2)).Return(nil).Once()
+
+		provisioner.CleanUpOrphanedDashboards(context.Background())
+	})
+
+	t.Run("should reject unknown strategies", func(t *testing.T) {
+		_, err := validateOrphanStrategy("archive")
+		require.Error(t, err)
+	})
+}
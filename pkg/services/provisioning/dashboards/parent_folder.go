@@ -0,0 +1,63 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// getOrCreateParentFolderID returns the id of the folder with the configured folder UID, which holds the folders
+// created from the file structure. The folder is created with the configured folder name when it doesn't exist.
+func (fr *FileReader) getOrCreateParentFolderID(ctx context.Context, cfg *config) (int64, error) {
+	if cfg.FolderUID == accesscontrol.GeneralFolderUID {
+		return 0, dashboards.ErrFolderInvalidUID
+	}
+
+	key := folderKey{orgID: cfg.OrgID, uid: cfg.FolderUID}
+	if folderID, ok := fr.folderIDs[key]; ok {
+		return folderID, nil
+	}
+
+	if err := fr.folderSlots.acquire(ctx); err != nil {
+		return 0, err
+	}
+	defer fr.folderSlots.release()
+
+	cmd := &models.GetDashboardQuery{Uid: cfg.FolderUID, OrgId: cfg.OrgID}
+	err := fr.dashboardStore.GetDashboard(ctx, cmd)
+	if err != nil && !errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return 0, err
+	}
+
+	if errors.Is(err, dashboards.ErrDashboardNotFound) {
+		if cfg.Folder == "" {
+			return 0, fmt.Errorf("%w: folder with uid %q doesn't exist", ErrFolderNameMissing, cfg.FolderUID)
+		}
+
+		dash := &dashboards.SaveDashboardDTO{}
+		dash.Dashboard = models.NewDashboardFolder(cfg.Folder)
+		dash.Dashboard.IsFolder = true
+		dash.Dashboard.SetUid(cfg.FolderUID)
+		dash.Overwrite = true
+		dash.OrgId = cfg.OrgID
+		dbDash, err := fr.dashboardProvisioningService.SaveFolderForProvisionedDashboards(ctx, dash)
+		if err != nil {
+			return 0, err
+		}
+
+		fr.createdFolders[dbDash.Id] = cfg.OrgID
+		fr.folderIDs[key] = dbDash.Id
+		return dbDash.Id, nil
+	}
+
+	if !cmd.Result.IsFolder {
+		return 0, ErrFolderConflict
+	}
+
+	fr.folderIDs[key] = cmd.Result.Id
+	return cmd.Result.Id, nil
+}
@@ -0,0 +1,69 @@
+package dashboards
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// peekDashboardKind returns the kind of a dashboard file without parsing it, when `kind` is the first key of its
+// top level object. It returns false when the kind can't be told that way, in which case the file has to be read.
+func (fr *FileReader) peekDashboardKind(path string, fileInfo os.FileInfo) (string, bool) {
+	if line, ok := fileInfo.(*jsonLineInfo); ok {
+		return peekJSONKind(bytes.NewReader(line.content))
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	file, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fr.log.Warn("Failed to close file", "path", path, "err", err)
+		}
+	}()
+
+	var content io.Reader = file
+	if strings.HasSuffix(strings.ToLower(path), gzipDashboardSuffix) {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return "", false
+		}
+		defer func() {
+			_ = gzipReader.Close()
+		}()
+		content = gzipReader
+	}
+	return peekJSONKind(content)
+}
+
+// peekJSONKind reads the first key of the JSON object in content and, if it is `kind`, its value, normalized like
+// dashboardKind does.
+func peekJSONKind(content io.Reader) (string, bool) {
+	dec := json.NewDecoder(content)
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return "", false
+	}
+	if key, err := dec.Token(); err != nil || key != "kind" {
+		return "", false
+	}
+	value, err := dec.Token()
+	if err != nil {
+		return "", false
+	}
+	kind, ok := value.(string)
+	if !ok {
+		return "", false
+	}
+
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if kind == "" {
+		return defaultDashboardKind, true
+	}
+	return kind, true
+}
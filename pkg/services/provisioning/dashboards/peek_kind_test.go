@@ -0,0 +1,77 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestPeekJSONKind(t *testing.T) {
+	tests := []struct {
+		content string
+		kind    string
+		ok      bool
+	}{
+		{content: `{"kind": "Report", "title": "Report"}`, kind: "report", ok: true},
+		{content: `{"kind": "", "title": "CPU"}`, kind: defaultDashboardKind, ok: true},
+		{content: `{"title": "CPU", "kind": "report"}`, ok: false},
+		{content: `{"kind": 1}`, ok: false},
+		{content: `[]`, ok: false},
+		{content: `not json`, ok: false},
+	}
+
+	for _, tt := range tests {
+		kind, ok := peekJSONKind(strings.NewReader(tt.content))
+		require.Equal(t, tt.ok, ok, tt.content)
+		require.Equal(t, tt.kind, kind, tt.content)
+	}
+}
+
+func TestPeekKind(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.json"), []byte(`{"title": "CPU", "uid": "cpu"}`), 0600))
+	// the report is cut short, so reading it fails
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.json"), []byte(`{"kind": "report", "title": `), 0600))
+
+	provision := func(t *testing.T, options map[string]interface{}) ([]string, error) {
+		t.Helper()
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+
+		var saved []string
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{}, nil).
+			Run(func(args mock.Arguments) {
+				saved = append(saved, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Uid)
+			})
+
+		options["path"] = dir
+		options["deniedKinds"] = []interface{}{"report"}
+		// reading the cut short report fails the run rather than being skipped, which tells whether it was read
+		options["skipInvalid"] = false
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		return saved, reader.walkDisk(context.Background())
+	}
+
+	t.Run("should skip files of denied kinds without reading them", func(t *testing.T) {
+		saved, err := provision(t, map[string]interface{}{"peekKind": true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"cpu"}, saved)
+	})
+
+	t.Run("should read every file without peeking", func(t *testing.T) {
+		_, err := provision(t, map[string]interface{}{})
+		require.ErrorIs(t, err, ErrInvalidDashboard)
+	})
+}
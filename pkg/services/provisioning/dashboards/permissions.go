@@ -0,0 +1,136 @@
+package dashboards
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// permissionsSidecarSuffix is the suffix of the file holding the permissions of the dashboard file with the same
+// base name, e.g. `cpu.permissions.json` for `cpu.json`.
+const permissionsSidecarSuffix = ".permissions.json"
+
+// resolvePermissions returns the permissions a dashboard file asks for, either through its permissions sidecar or
+// through a `permissions` field in the dashboard itself. The sidecar takes precedence, and its content is folded
+// into the checksum of jsonFile so that changing it triggers an update. An empty list means the default
+// permissions.
+func resolvePermissions(path string, jsonFile *dashboardJSONFile) ([]*models.DashboardACL, error) {
+	sidecar, raw, err := readSidecar(path, permissionsSidecarSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := jsonFile.dashboard.Dashboard.Data.Get("permissions")
+
+	if sidecar != nil {
+		checkSum, err := util.Md5SumString(jsonFile.checkSum + string(raw))
+		if err != nil {
+			return nil, err
+		}
+		jsonFile.checkSum = checkSum
+		permissions = sidecar
+	}
+
+	return parsePermissions(permissions)
+}
+
+// parsePermissions parses a list of permissions. Each item grants a `permission`, either as a name (`View`,
+// `Edit` or `Admin`) or as its value, to exactly one of a `userId`, a `teamId` or a `role`.
+func parsePermissions(permissions *simplejson.Json) ([]*models.DashboardACL, error) {
+	if permissions.Interface() == nil {
+		return nil, nil
+	}
+
+	list, err := permissions.Array()
+	if err != nil {
+		return nil, fmt.Errorf("permissions must be a list")
+	}
+
+	items := make([]*models.DashboardACL, 0, len(list))
+	for i := range list {
+		item, err := parsePermission(permissions.GetIndex(i))
+		if err != nil {
+			return nil, fmt.Errorf("invalid permission at index %d: %w", i, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func parsePermission(data *simplejson.Json) (*models.DashboardACL, error) {
+	item := &models.DashboardACL{}
+	targets := 0
+
+	if userID, ok := data.CheckGet("userId"); ok {
+		id, err := userID.Int64()
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("'userId' must be a positive number")
+		}
+		item.UserID = id
+		targets++
+	}
+
+	if teamID, ok := data.CheckGet("teamId"); ok {
+		id, err := teamID.Int64()
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("'teamId' must be a positive number")
+		}
+		item.TeamID = id
+		targets++
+	}
+
+	if role, ok := data.CheckGet("role"); ok {
+		roleType := models.RoleType(role.MustString())
+		if !roleType.IsValid() {
+			return nil, fmt.Errorf("invalid role %q", role.MustString())
+		}
+		item.Role = &roleType
+		targets++
+	}
+
+	if targets != 1 {
+		return nil, fmt.Errorf("exactly one of 'userId', 'teamId' or 'role' must be set")
+	}
+
+	permission, err := parsePermissionType(data.Get("permission"))
+	if err != nil {
+		return nil, err
+	}
+	item.Permission = permission
+
+	return item, nil
+}
+
+func parsePermissionType(data *simplejson.Json) (models.PermissionType, error) {
+	for _, permission := range []models.PermissionType{models.PERMISSION_VIEW, models.PERMISSION_EDIT, models.PERMISSION_ADMIN} {
+		if name, err := data.String(); err == nil && name == permission.String() {
+			return permission, nil
+		}
+		if value, err := data.Int64(); err == nil && value == int64(permission) {
+			return permission, nil
+		}
+	}
+
+	return 0, fmt.Errorf("'permission' must be one of %q, %q or %q", models.PERMISSION_VIEW.String(),
+		models.PERMISSION_EDIT.String(), models.PERMISSION_ADMIN.String())
+}
+
+// applyPermissions replaces the permissions of a saved dashboard. Failures are logged rather than returned
+// since the dashboard itself was provisioned.
+func (fr *FileReader) applyPermissions(ctx context.Context, path string, orgID, dashboardID int64, items []*models.DashboardACL) {
+	now := fr.Clock.Now()
+	for _, item := range items {
+		item.OrgID = orgID
+		item.DashboardID = dashboardID
+		item.Created = now
+		item.Updated = now
+	}
+
+	if err := fr.dashboardProvisioningService.UpdateProvisionedDashboardACL(ctx, dashboardID, items); err != nil {
+		fr.log.Error("failed to provision dashboard permissions", "file", path, "dashboardId", dashboardID, "error", err)
+	}
+}
@@ -0,0 +1,112 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestParsePermissions(t *testing.T) {
+	editor := models.ROLE_EDITOR
+
+	tests := []struct {
+		name        string
+		permissions string
+		expected    []*models.DashboardACL
+		expectedErr string
+	}{
+		{name: "no permissions", permissions: `null`},
+		{
+			name:        "user, team and role",
+			permissions: `[{"userId": 3, "permission": "Admin"}, {"teamId": 4, "permission": 2}, {"role": "Editor", "permission": "View"}]`,
+			expected: []*models.DashboardACL{
+				{UserID: 3, Permission: models.PERMISSION_ADMIN},
+				{TeamID: 4, Permission: models.PERMISSION_EDIT},
+				{Role: &editor, Permission: models.PERMISSION_VIEW},
+			},
+		},
+		{name: "not a list", permissions: `{"userId": 3}`, expectedErr: "permissions must be a list"},
+		{name: "no target", permissions: `[{"permission": "View"}]`, expectedErr: "exactly one of"},
+		{name: "several targets", permissions: `[{"userId": 1, "teamId": 2, "permission": "View"}]`, expectedErr: "exactly one of"},
+		{name: "invalid role", permissions: `[{"role": "Owner", "permission": "View"}]`, expectedErr: "invalid role"},
+		{name: "invalid permission", permissions: `[{"userId": 1, "permission": 3}]`, expectedErr: "'permission' must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := parsePermissions(simplejson.MustJson([]byte(tt.permissions)))
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, items)
+		})
+	}
+}
+
+func TestManagePermissions(t *testing.T) {
+	setup := func(t *testing.T, sidecar string) (*FileReader, *dashboards.FakeDashboardProvisioning) {
+		t.Helper()
+		dir := t.TempDir()
+		dashboard := `{"title": "CPU", "permissions": [{"role": "Viewer", "permission": "View"}]}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.json"), []byte(dashboard), 0600))
+		if sidecar != "" {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.permissions.json"), []byte(sidecar), 0600))
+		}
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":              dir,
+			"managePermissions": true,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{Id: 7}, nil).Once()
+		reader.dashboardProvisioningService = fakeService
+		return reader, fakeService
+	}
+
+	t.Run("should apply the permissions of the dashboard", func(t *testing.T) {
+		reader, fakeService := setup(t, "")
+		fakeService.On("UpdateProvisionedDashboardACL", mock.Anything, int64(7), mock.Anything).Return(nil).Once().
+			Run(func(args mock.Arguments) {
+				items := args.Get(2).([]*models.DashboardACL)
+				require.Len(t, items, 1)
+				require.Equal(t, models.ROLE_VIEWER, *items[0].Role)
+				require.Equal(t, int64(7), items[0].DashboardID)
+				require.Equal(t, int64(1), items[0].OrgID)
+			})
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		fakeService.AssertExpectations(t)
+	})
+
+	t.Run("should prefer the permissions sidecar", func(t *testing.T) {
+		reader, fakeService := setup(t, `[]`)
+		fakeService.On("UpdateProvisionedDashboardACL", mock.Anything, int64(7), []*models.DashboardACL{}).Return(nil).Once()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		fakeService.AssertExpectations(t)
+	})
+
+	t.Run("should skip dashboards with invalid permissions", func(t *testing.T) {
+		reader, fakeService := setup(t, `[{"permission": "View"}]`)
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		fakeService.AssertNotCalled(t, "SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
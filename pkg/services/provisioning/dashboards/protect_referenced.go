@@ -0,0 +1,134 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// dashboardURLPattern matches the URLs of dashboards, capturing their uid.
+var dashboardURLPattern = regexp.MustCompile(`(?:^|/)d/([a-zA-Z0-9_-]+)`)
+
+// referencedUIDs returns the uids of the dashboards a dashboard links to, through its dashboard links, the links
+// of its panels or any other URL in its content.
+func referencedUIDs(data *simplejson.Json) map[string]bool {
+	uids := map[string]bool{}
+	collectReferencedUIDs(data.Interface(), uids)
+	return uids
+}
+
+func collectReferencedUIDs(value interface{}, uids map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, item := range v {
+			collectReferencedUIDs(item, uids)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectReferencedUIDs(item, uids)
+		}
+	case string:
+		for _, match := range dashboardURLPattern.FindAllStringSubmatch(v, -1) {
+			uids[match[1]] = true
+		}
+	}
+}
+
+// referenceKey identifies a dashboard linked to by other dashboards, since uids are only unique within an
+// organization.
+type referenceKey struct {
+	orgID int64
+	uid   string
+}
+
+// dashboardReferences maps the dashboards about to be removed to the titles of the dashboards linking to them. Every
+// dashboard of their organizations is looked at, whether it's provisioned by this provider, another one, or not at
+// all, except for the dashboards about to be removed themselves.
+func (fr *FileReader) dashboardReferences(ctx context.Context, toRemove []*models.DashboardProvisioning) (map[referenceKey][]string, error) {
+	removed := map[int64]bool{}
+	orgs := map[int64]bool{}
+	for _, provisioningData := range toRemove {
+		removed[provisioningData.DashboardId] = true
+		orgID := fr.Cfg.OrgID
+		if fr.OrgIDFromPath {
+			orgID = fr.orgIDForPath(fr.resolvedPath(), provisioningData.ExternalId)
+		}
+		orgs[orgID] = true
+	}
+	orgIDs := make([]int64, 0, len(orgs))
+	for orgID := range orgs {
+		orgIDs = append(orgIDs, orgID)
+	}
+	sort.Slice(orgIDs, func(i, j int) bool { return orgIDs[i] < orgIDs[j] })
+
+	references := map[referenceKey][]string{}
+	for _, orgID := range orgIDs {
+		query := &models.GetDashboardsByOrgIdQuery{OrgId: orgID}
+		if err := fr.dashboardProvisioningService.GetDashboardsByOrgID(ctx, query); err != nil {
+			return nil, err
+		}
+		for _, dashboard := range query.Result {
+			if removed[dashboard.Id] || dashboard.Data == nil {
+				continue
+			}
+			for uid := range referencedUIDs(dashboard.Data) {
+				if uid != dashboard.Uid {
+					key := referenceKey{orgID: orgID, uid: uid}
+					references[key] = append(references[key], dashboard.Title)
+				}
+			}
+		}
+	}
+	for _, titles := range references {
+		sort.Strings(titles)
+	}
+	return references, nil
+}
+
+// getProvisionedDashboard returns the stored version of a provisioned dashboard.
+func (fr *FileReader) getProvisionedDashboard(ctx context.Context, provisioningData *models.DashboardProvisioning) (*models.Dashboard, error) {
+	orgID := fr.Cfg.OrgID
+	if fr.OrgIDFromPath {
+		orgID = fr.orgIDForPath(fr.resolvedPath(), provisioningData.ExternalId)
+	}
+	query := &models.GetDashboardQuery{OrgId: orgID, Id: provisioningData.DashboardId}
+	if err := fr.dashboardStore.GetDashboard(ctx, query); err != nil {
+		return nil, err
+	}
+	return query.Result, nil
+}
+
+// keepReferenced reports whether a provisioned dashboard must be kept rather than removed because other dashboards
+// of its organization link to it, unless its uid is listed in the allowReferencedDeletion option.
+func (fr *FileReader) keepReferenced(ctx context.Context, provisioningData *models.DashboardProvisioning,
+	references map[referenceKey][]string) bool {
+	dashboard, err := fr.getProvisionedDashboard(ctx, provisioningData)
+	if errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return false
+	}
+	if err != nil {
+		// keep the dashboard when in doubt, it will be checked again on the next run
+		fr.log.Error("failed to get dashboard to check whether it's referenced", "id", provisioningData.DashboardId,
+			"error", err)
+		return true
+	}
+
+	referencedBy := references[referenceKey{orgID: dashboard.OrgId, uid: dashboard.Uid}]
+	if len(referencedBy) == 0 {
+		return false
+	}
+	if containsString(fr.AllowReferencedDeletion, dashboard.Uid) {
+		fr.log.Info("removing dashboard referenced by other dashboards", "uid", dashboard.Uid, "file",
+			provisioningData.ExternalId, "referencedBy", strings.Join(referencedBy, ", "))
+		return false
+	}
+	fr.log.Warn("keeping dashboard referenced by other dashboards, list its uid in 'allowReferencedDeletion' to remove it",
+		"uid", dashboard.Uid, "file", provisioningData.ExternalId, "referencedBy", strings.Join(referencedBy, ", "))
+	return true
+}
@@ -0,0 +1,101 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestReferencedUIDs(t *testing.T) {
+	data := simplejson.NewFromAny(map[string]interface{}{
+		"links": []interface{}{
+			map[string]interface{}{"type": "link", "url": "/d/cpu/cpu-usage?orgId=1"},
+		},
+		"panels": []interface{}{
+			map[string]interface{}{"links": []interface{}{
+				map[string]interface{}{"url": "https://grafana.example.com/d/memory_1"},
+				map[string]interface{}{"url": "https://example.com/docs"},
+			}},
+		},
+	})
+
+	require.Equal(t, map[string]bool{"cpu": true, "memory_1": true}, referencedUIDs(data))
+}
+
+func TestProtectReferenced(t *testing.T) {
+	store := idDashboardStore{
+		1: {Id: 1, OrgId: 1, Uid: "overview", Title: "Overview", Data: simplejson.NewFromAny(map[string]interface{}{
+			"uid": "overview", "links": []interface{}{map[string]interface{}{"url": "/d/cpu"}},
+		})},
+		2: {Id: 2, OrgId: 1, Uid: "cpu", Title: "CPU", Data: simplejson.NewFromAny(map[string]interface{}{"uid": "cpu"})},
+		3: {Id: 3, OrgId: 1, Uid: "memory", Title: "Memory", Data: simplejson.NewFromAny(map[string]interface{}{"uid": "memory"})},
+	}
+	refs := map[string]*models.DashboardProvisioning{
+		"/dashboards/overview.json": {DashboardId: 1, Name: configName, ExternalId: "/dashboards/overview.json"},
+		"/dashboards/cpu.json":      {DashboardId: 2, Name: configName, ExternalId: "/dashboards/cpu.json"},
+		"/dashboards/memory.json":   {DashboardId: 3, Name: configName, ExternalId: "/dashboards/memory.json"},
+	}
+	// the cpu and memory dashboards were removed from disk
+	filesFoundOnDisk := map[string]os.FileInfo{"/dashboards/overview.json": nil}
+	orgDashboards := []*models.Dashboard{store[1], store[2], store[3]}
+
+	removeMissing := func(t *testing.T, options map[string]interface{}, listed []*models.Dashboard, deleted ...int64) {
+		t.Helper()
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+		fakeService.On("GetDashboardsByOrgID", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			query := args.Get(1).(*models.GetDashboardsByOrgIdQuery)
+			require.Equal(t, int64(1), query.OrgId)
+			require.False(t, query.ExcludeProvisioned)
+			query.Result = listed
+		}).Return(nil).Maybe()
+		for _, id := range deleted {
+			fakeService.On("DeleteProvisionedDashboard", mock.Anything, id, int64(1)).Return(nil).Once()
+		}
+
+		options["path"] = defaultDashboards
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, store)
+		require.NoError(t, err)
+		require.NoError(t, reader.handleMissingDashboardFiles(context.Background(), refs, filesFoundOnDisk))
+	}
+
+	t.Run("should delete referenced dashboards by default", func(t *testing.T) {
+		removeMissing(t, map[string]interface{}{}, orgDashboards, 2, 3)
+	})
+
+	t.Run("should keep referenced dashboards", func(t *testing.T) {
+		removeMissing(t, map[string]interface{}{"protectReferenced": true}, orgDashboards, 3)
+	})
+
+	t.Run("should keep dashboards referenced by dashboards the provider doesn't provision", func(t *testing.T) {
+		team := &models.Dashboard{Id: 4, OrgId: 1, Uid: "team", Title: "Team", Data: simplejson.NewFromAny(map[string]interface{}{
+			"uid": "team", "panels": []interface{}{map[string]interface{}{"links": []interface{}{
+				map[string]interface{}{"url": "/d/memory/memory"},
+			}}},
+		})}
+		removeMissing(t, map[string]interface{}{"protectReferenced": true}, append(orgDashboards, team))
+	})
+
+	t.Run("should ignore references between the removed dashboards", func(t *testing.T) {
+		cpu := &models.Dashboard{Id: 2, OrgId: 1, Uid: "cpu", Title: "CPU", Data: simplejson.NewFromAny(map[string]interface{}{
+			"uid": "cpu", "links": []interface{}{map[string]interface{}{"url": "/d/memory"}},
+		})}
+		removeMissing(t, map[string]interface{}{"protectReferenced": true}, []*models.Dashboard{cpu, store[3]}, 2, 3)
+	})
+
+	t.Run("should delete referenced dashboards when allowed", func(t *testing.T) {
+		removeMissing(t, map[string]interface{}{
+			"protectReferenced":       true,
+			"allowReferencedDeletion": []interface{}{"cpu"},
+		}, orgDashboards, 2, 3)
+	})
+}
@@ -0,0 +1,43 @@
+package dashboards
+
+// ProviderConfig is the effective configuration of a dashboard provider, once the defaults were applied to what
+// was read from its config file.
+type ProviderConfig struct {
+	Name                  string                 `json:"name"`
+	Type                  string                 `json:"type"`
+	OrgID                 int64                  `json:"orgId"`
+	Folder                string                 `json:"folder"`
+	FolderUID             string                 `json:"folderUid"`
+	Editable              bool                   `json:"editable"`
+	DisableDeletion       bool                   `json:"disableDeletion"`
+	UpdateIntervalSeconds int64                  `json:"updateIntervalSeconds"`
+	AllowUIUpdates        bool                   `json:"allowUiUpdates"`
+	Options               map[string]interface{} `json:"options"`
+	// File is the name of the config file the provider was read from.
+	File string `json:"file,omitempty"`
+	// ResolvedPath is the absolute path the provider reads its files from, with the symlinks resolved.
+	ResolvedPath string `json:"resolvedPath"`
+}
+
+// providerConfig returns the effective configuration of the provider.
+func (fr *FileReader) providerConfig() ProviderConfig {
+	options := make(map[string]interface{}, len(fr.Cfg.Options))
+	for key, value := range fr.Cfg.Options {
+		options[key] = value
+	}
+
+	return ProviderConfig{
+		Name:                  fr.Cfg.Name,
+		Type:                  fr.Cfg.Type,
+		OrgID:                 fr.Cfg.OrgID,
+		Folder:                fr.Cfg.Folder,
+		FolderUID:             fr.Cfg.FolderUID,
+		Editable:              fr.Cfg.Editable,
+		DisableDeletion:       fr.Cfg.DisableDeletion,
+		UpdateIntervalSeconds: fr.Cfg.UpdateIntervalSeconds,
+		AllowUIUpdates:        fr.Cfg.AllowUIUpdates,
+		Options:               options,
+		File:                  fr.Cfg.file,
+		ResolvedPath:          fr.resolvedPath(),
+	}
+}
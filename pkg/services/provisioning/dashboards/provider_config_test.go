@@ -0,0 +1,35 @@
+package dashboards
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestProviderConfig(t *testing.T) {
+	cfgReader := &configReader{path: "./testdata/test-configs/applied-defaults", log: log.New("test-logger"), orgStore: fakeOrgStore{}}
+	cfgs, err := cfgReader.readConfig(context.Background())
+	require.NoError(t, err)
+	require.Len(t, cfgs, 1)
+
+	reader, err := NewDashboardFileReader(cfgs[0], log.New("test-logger"), nil, nil)
+	require.NoError(t, err)
+
+	providerConfig := reader.providerConfig()
+	require.Equal(t, "applied-defaults", providerConfig.Name)
+	require.Equal(t, "file", providerConfig.Type)
+	require.Equal(t, int64(1), providerConfig.OrgID)
+	require.Equal(t, int64(10), providerConfig.UpdateIntervalSeconds)
+	require.Equal(t, "/var/lib/grafana/dashboards", providerConfig.Options["path"])
+	require.Equal(t, "dev-dashboards.yaml", filepath.Base(providerConfig.File))
+	require.Equal(t, reader.resolvedPath(), providerConfig.ResolvedPath)
+
+	t.Run("should not share the options of the provider", func(t *testing.T) {
+		providerConfig.Options["path"] = "/tmp"
+		require.Equal(t, "/var/lib/grafana/dashboards", reader.Cfg.Options["path"])
+	})
+}
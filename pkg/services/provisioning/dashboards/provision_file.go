@@ -0,0 +1,74 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrFileOutsidePath is returned when provisioning a single file which isn't stored under the path of the reader.
+var ErrFileOutsidePath = errors.New("dashboard file is not stored under the path of the provisioner")
+
+// ProvisionedFile describes the dashboard provisioned from a single file. UID and Title are empty when the file was
+// skipped, for instance because it's disabled or invalid.
+type ProvisionedFile struct {
+	Path     string
+	UID      string
+	Title    string
+	FolderID int64
+}
+
+// ProvisionFile reads, validates and saves the dashboard file at path, like a provisioning run does for every
+// file, without touching the dashboards of the other files. Relative paths are resolved against the path of the
+// reader, under which the file must be stored.
+func (fr *FileReader) ProvisionFile(ctx context.Context, path string) (ProvisionedFile, error) {
+	if fr.VerifyOnly {
+		return ProvisionedFile{Path: path}, fmt.Errorf("%w: %s", ErrVerifyOnly, fr.Cfg.Name)
+	}
+
+	fr.runMux.Lock()
+	defer fr.runMux.Unlock()
+
+	resolvedPath := fr.resolvedPath()
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(resolvedPath, path)
+	}
+	relativePath, err := filepath.Rel(resolvedPath, path)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return ProvisionedFile{Path: path}, fmt.Errorf("%w: %s", ErrFileOutsidePath, path)
+	}
+
+	fileInfo, err := os.Lstat(path)
+	if err != nil {
+		return ProvisionedFile{Path: path}, err
+	}
+	if err := fr.updateAuxiliaryChecksum(resolvedPath); err != nil {
+		return ProvisionedFile{Path: path}, fmt.Errorf("failed to read auxiliary files: %w", err)
+	}
+
+	provisionedDashboardRefs, err := getProvisionedDashboardsByPath(fr.dashboardProvisioningService, fr.Cfg.Name)
+	if err != nil {
+		return ProvisionedFile{Path: path}, err
+	}
+
+	var folderID int64
+	if fr.FoldersFromFilesStructure {
+		folderID, _, err = fr.getOrCreateFileStructureFolderID(ctx, resolvedPath, path)
+	} else {
+		folderID, err = fr.getOrCreateFolderID(ctx, fr.Cfg, fr.dashboardProvisioningService, fr.Cfg.Folder)
+	}
+	if err != nil && !errors.Is(err, ErrFolderNameMissing) {
+		return ProvisionedFile{Path: path}, err
+	}
+
+	provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, fileInfo, provisionedDashboardRefs, newUsageTracker())
+	return ProvisionedFile{
+		Path:     path,
+		UID:      provisioningMetadata.uid,
+		Title:    provisioningMetadata.identity.title,
+		FolderID: provisioningMetadata.identity.folderID,
+	}, err
+}
@@ -0,0 +1,49 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestProvisionFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.json"), []byte(`{"title": "CPU", "uid": "cpu"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "memory.json"), []byte(`{"title": "Memory", "uid": "memory"}`), 0600))
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": dir}}
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	t.Run("should only save the given file", func(t *testing.T) {
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{}, nil).Once().
+			Run(func(args mock.Arguments) {
+				require.Equal(t, "cpu", args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Uid)
+			})
+
+		provisioned, err := reader.ProvisionFile(context.Background(), "cpu.json")
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(reader.resolvedPath(), "cpu.json"), provisioned.Path)
+		require.Equal(t, "cpu", provisioned.UID)
+		require.Equal(t, "CPU", provisioned.Title)
+	})
+
+	t.Run("should reject files outside of the path", func(t *testing.T) {
+		_, err := reader.ProvisionFile(context.Background(), filepath.Join("..", "cpu.json"))
+		require.ErrorIs(t, err, ErrFileOutsidePath)
+	})
+}
@@ -0,0 +1,57 @@
+package dashboards
+
+import (
+	"os"
+	"time"
+)
+
+// quarantine tracks the dashboard files failing to load on consecutive runs, so that files failing quarantineAfter
+// times in a row are no longer retried until they change, identified by their modification time and size. It is
+// only accessed during runs.
+type quarantine struct {
+	entries map[string]*quarantineEntry
+}
+
+type quarantineEntry struct {
+	modTime  time.Time
+	size     int64
+	failures int64
+}
+
+func newQuarantine() *quarantine {
+	return &quarantine{entries: map[string]*quarantineEntry{}}
+}
+
+// isQuarantined reports whether the file at path failed at least limit times in a row and didn't change since.
+func (q *quarantine) isQuarantined(path string, fileInfo os.FileInfo, limit int64) bool {
+	entry, ok := q.entries[path]
+	if !ok || !entry.modTime.Equal(fileInfo.ModTime()) || entry.size != fileInfo.Size() {
+		return false
+	}
+	return limit > 0 && entry.failures >= limit
+}
+
+// fail records a failure of the file at path and returns the number of consecutive failures of its current version.
+func (q *quarantine) fail(path string, fileInfo os.FileInfo) int64 {
+	entry, ok := q.entries[path]
+	if !ok || !entry.modTime.Equal(fileInfo.ModTime()) || entry.size != fileInfo.Size() {
+		entry = &quarantineEntry{modTime: fileInfo.ModTime(), size: fileInfo.Size()}
+		q.entries[path] = entry
+	}
+	entry.failures++
+	return entry.failures
+}
+
+// succeed forgets the failures of the file at path.
+func (q *quarantine) succeed(path string) {
+	delete(q.entries, path)
+}
+
+// prune drops the entries of files which are no longer on disk.
+func (q *quarantine) prune(filesFoundOnDisk map[string]os.FileInfo) {
+	for path := range q.entries {
+		if _, exists := filesFoundOnDisk[path]; !exists {
+			delete(q.entries, path)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestQuarantineAfter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cpu.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"title": "CPU"`), 0600))
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+	fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil)
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path":            dir,
+		"quarantineAfter": 2,
+	}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	fileInfo, err := os.Stat(path)
+	require.NoError(t, err)
+
+	t.Run("should quarantine files failing on consecutive runs", func(t *testing.T) {
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.False(t, reader.quarantine.isQuarantined(path, fileInfo, reader.QuarantineAfter))
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.True(t, reader.quarantine.isQuarantined(path, fileInfo, reader.QuarantineAfter))
+
+		// quarantined files aren't read anymore
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.Equal(t, int64(2), reader.quarantine.entries[path].failures)
+	})
+
+	t.Run("should retry quarantined files once they change", func(t *testing.T) {
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{}, nil).Once()
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"title": "CPU"}`), 0600))
+		changed := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, changed, changed))
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.Empty(t, reader.quarantine.entries)
+	})
+}
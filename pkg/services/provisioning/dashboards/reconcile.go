@@ -0,0 +1,26 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// reconcileByUID returns the id of the stored dashboard with the uid of dash, or 0 if there is none. It lets a
+// dashboard whose provisioning record was lost, for instance after a database reset, be updated in place and linked
+// back to its file instead of being created again.
+func (fr *FileReader) reconcileByUID(ctx context.Context, dash *models.Dashboard) (int64, error) {
+	query := &models.GetDashboardQuery{Uid: dash.Uid, OrgId: dash.OrgId}
+	if err := fr.dashboardStore.GetDashboard(ctx, query); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if query.Result.IsFolder {
+		return 0, nil
+	}
+	return query.Result.Id, nil
+}
@@ -0,0 +1,79 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+type uidDashboardStore map[string]int64
+
+func (s uidDashboardStore) GetDashboard(_ context.Context, query *models.GetDashboardQuery) error {
+	id, ok := s[query.Uid]
+	if query.Uid == "" || !ok {
+		return dashboards.ErrDashboardNotFound
+	}
+	query.Result = &models.Dashboard{Id: id, Uid: query.Uid, OrgId: query.OrgId}
+	return nil
+}
+
+func TestReconcileByUID(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.json"), []byte(`{"title": "CPU", "uid": "cpu"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "memory.json"), []byte(`{"title": "Memory", "uid": "memory"}`), 0600))
+	store := uidDashboardStore{"cpu": 42}
+
+	setup := func(t *testing.T, reconcile bool) (*FileReader, *dashboards.FakeDashboardProvisioning) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":           dir,
+			"reconcileByUid": reconcile,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, store)
+		require.NoError(t, err)
+		return reader, fakeService
+	}
+
+	expectSave := func(fakeService *dashboards.FakeDashboardProvisioning, id int64) {
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{}, nil).Once().
+			Run(func(args mock.Arguments) {
+				require.Equal(t, id, args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Id)
+			})
+	}
+
+	t.Run("should reuse the id of the dashboard with the same uid", func(t *testing.T) {
+		reader, fakeService := setup(t, true)
+		expectSave(fakeService, 42)
+
+		_, err := reader.ProvisionFile(context.Background(), "cpu.json")
+		require.NoError(t, err)
+	})
+
+	t.Run("should create dashboards without a stored uid", func(t *testing.T) {
+		reader, fakeService := setup(t, true)
+		expectSave(fakeService, 0)
+
+		_, err := reader.ProvisionFile(context.Background(), "memory.json")
+		require.NoError(t, err)
+	})
+
+	t.Run("should only reconcile when enabled", func(t *testing.T) {
+		reader, fakeService := setup(t, false)
+		expectSave(fakeService, 0)
+
+		_, err := reader.ProvisionFile(context.Background(), "cpu.json")
+		require.NoError(t, err)
+	})
+}
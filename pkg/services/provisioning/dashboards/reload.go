@@ -0,0 +1,197 @@
+package dashboards
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// configPollInterval is how often the config directory is checked for changes.
+var configPollInterval = 10 * time.Second
+
+// readerPoller tracks the polling goroutine of a FileReader, so that it can be stopped when its config changes.
+type readerPoller struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// configDirectoryChecksum returns a checksum of the names and content of the config files in path.
+func configDirectoryChecksum(path string) (string, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return "", err
+	}
+
+	var content strings.Builder
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `path` comes from the Grafana configuration
+		data, err := ioutil.ReadFile(filepath.Join(path, file.Name()))
+		if err != nil {
+			return "", err
+		}
+		content.WriteString(file.Name())
+		content.Write(data)
+	}
+
+	return util.Md5SumString(content.String())
+}
+
+// sameConfig reports whether a and b define the same provider, regardless of the file they were read from.
+func sameConfig(a, b *config) bool {
+	x, y := *a, *b
+	x.file, y.file = "", ""
+	return reflect.DeepEqual(x, y)
+}
+
+// watchConfigs reloads the providers whenever the config files change, until ctx is done.
+func (provider *Provisioner) watchConfigs(ctx context.Context) {
+	ticker := provider.clock.Ticker(configPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			checkSum, err := configDirectoryChecksum(provider.cfgReader.path)
+			if err != nil {
+				provider.log.Debug("Failed to check dashboards config for changes", "path", provider.cfgReader.path, "error", err)
+				continue
+			}
+			if checkSum == provider.configChecksum {
+				continue
+			}
+
+			provider.configChecksum = checkSum
+			provider.reloadConfigs(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reloadConfigs reads the config files again and restarts the providers whose config changed. Providers whose
+// config is unchanged keep running, while the dashboards of removed providers are deleted.
+func (provider *Provisioner) reloadConfigs(ctx context.Context) {
+	configs, err := provider.cfgReader.readConfig(ctx)
+	if err != nil {
+		provider.log.Error("Failed to reload dashboards config", "error", err)
+		return
+	}
+
+	started, removed, err := provider.replaceReaders(configs)
+	if err != nil {
+		provider.log.Error("Failed to reload dashboards config", "error", err)
+		return
+	}
+
+	for _, reader := range started {
+		provider.startPolling(ctx, reader, true)
+	}
+	if removed {
+		provider.CleanUpOrphanedDashboards(ctx)
+	}
+}
+
+// replaceReaders swaps the readers of the provisioner for the ones defined by configs, reusing the readers whose
+// config is unchanged and stopping the others. It returns the readers to start, and whether any provider was
+// removed.
+func (provider *Provisioner) replaceReaders(configs []*config) ([]*FileReader, bool, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	current := make(map[string]*FileReader, len(provider.fileReaders))
+	for _, reader := range provider.fileReaders {
+		current[reader.Cfg.Name] = reader
+	}
+
+	readers := make([]*FileReader, 0, len(configs))
+	var started []*FileReader
+	for _, cfg := range configs {
+		if reader, ok := current[cfg.Name]; ok && sameConfig(reader.Cfg, cfg) {
+			readers = append(readers, reader)
+			delete(current, cfg.Name)
+			continue
+		}
+
+		created, err := getFileReaders([]*config{cfg}, provider.log, provider.provisioner, provider.dashboardStore)
+		if err != nil {
+			return nil, false, err
+		}
+		created[0].Transformers = provider.transformers
+		created[0].Secrets = provider.secrets
+		created[0].runSlots = provider.runSlots
+		readers = append(readers, created[0])
+		started = append(started, created[0])
+	}
+
+	removed := false
+	for name := range current {
+		if provider.isReplaced(name, started) {
+			// replaced readers are stopped by startPolling, before their replacement starts
+			continue
+		}
+		provider.log.Info("Stopping dashboard provider", "name", name)
+		removed = true
+		if poller, ok := provider.pollers[name]; ok {
+			poller.cancel()
+			delete(provider.pollers, name)
+		}
+	}
+	for _, reader := range started {
+		provider.log.Info("Starting dashboard provider", "name", reader.Cfg.Name)
+	}
+
+	provider.fileReaders = readers
+	provider.configs = configs
+	provider.duplicateValidator.setReaders(readers)
+
+	return started, removed, nil
+}
+
+func (provider *Provisioner) isReplaced(name string, started []*FileReader) bool {
+	for _, reader := range started {
+		if reader.Cfg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// startPolling starts a goroutine provisioning the dashboards of reader until ctx is done, starting with a run
+// unless initialRun is false or the first run is deferred. When the reader replaces another one, the goroutine
+// waits for the replaced reader to stop first.
+func (provider *Provisioner) startPolling(ctx context.Context, reader *FileReader, initialRun bool) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	var previous *readerPoller
+	if poller, ok := provider.pollers[reader.Cfg.Name]; ok {
+		poller.cancel()
+		previous = poller
+	}
+
+	readerCtx, cancel := context.WithCancel(ctx)
+	poller := &readerPoller{cancel: cancel, done: make(chan struct{})}
+	provider.pollers[reader.Cfg.Name] = poller
+
+	go func() {
+		defer close(poller.done)
+		if previous != nil {
+			<-previous.done
+		}
+		if initialRun && !reader.hasStartupDelay() && readerCtx.Err() == nil {
+			if err := reader.walkDisk(readerCtx); err != nil {
+				reader.log.Error("failed to search for dashboards", "error", err)
+			}
+		}
+		reader.pollChanges(readerCtx)
+	}()
+}
@@ -0,0 +1,66 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+type fakeOrgStore struct{}
+
+func (fakeOrgStore) GetOrgById(_ context.Context, query *models.GetOrgByIdQuery) error {
+	query.Result = &models.Org{Id: query.Id}
+	return nil
+}
+
+func TestReloadConfigs(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig := func(t *testing.T, providers string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboards.yaml"), []byte("apiVersion: 1\nproviders:\n"+providers), 0600))
+	}
+	provider := func(name, path string) string {
+		return "- name: " + name + "\n  type: file\n  options:\n    path: " + path + "\n"
+	}
+
+	writeConfig(t, provider("a", "/a")+provider("b", "/b")+provider("d", "/d"))
+	dashProvisioner, err := New(context.Background(), dir, t.TempDir(), nil, fakeOrgStore{}, nil, false, "", 0)
+	require.NoError(t, err)
+	p := dashProvisioner.(*Provisioner)
+	readerA := p.fileReaders[0]
+
+	checkSum, err := configDirectoryChecksum(dir)
+	require.NoError(t, err)
+	require.Equal(t, p.configChecksum, checkSum)
+
+	writeConfig(t, provider("a", "/a")+provider("b", "/other")+provider("c", "/c"))
+	changed, err := configDirectoryChecksum(dir)
+	require.NoError(t, err)
+	require.NotEqual(t, checkSum, changed)
+
+	configs, err := p.cfgReader.readConfig(context.Background())
+	require.NoError(t, err)
+	started, removed, err := p.replaceReaders(configs)
+	require.NoError(t, err)
+	require.True(t, removed)
+
+	require.Len(t, started, 2)
+	require.Equal(t, "b", started[0].Cfg.Name)
+	require.Equal(t, "/other", started[0].Path)
+	require.Equal(t, "c", started[1].Cfg.Name)
+
+	require.Len(t, p.fileReaders, 3)
+	require.Same(t, readerA, p.fileReaders[0])
+	require.Equal(t, "/other", p.GetProvisionerResolvedPath("b"))
+	require.Empty(t, p.GetProvisionerResolvedPath("d"))
+
+	started, removed, err = p.replaceReaders(configs)
+	require.NoError(t, err)
+	require.False(t, removed)
+	require.Empty(t, started)
+}
@@ -0,0 +1,79 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// detectRenamedFiles looks for dashboard files which were renamed or moved since the last run, that is missing
+// files whose content now appears under a new path. The provisioning data of such files is moved to their new
+// path in provisionedDashboardRefs, so that the dashboard is updated in place instead of being deleted and
+// created again with a new id.
+func (fr *FileReader) detectRenamedFiles(ctx context.Context, provisionedDashboardRefs map[string]*models.DashboardProvisioning,
+	filesFoundOnDisk map[string]os.FileInfo) {
+	var missing []string
+	for path := range provisionedDashboardRefs {
+		if _, existsOnDisk := filesFoundOnDisk[path]; !existsOnDisk {
+			missing = append(missing, path)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	var added []string
+	for path := range filesFoundOnDisk {
+		if _, provisioned := provisionedDashboardRefs[path]; !provisioned {
+			added = append(added, path)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	// match files in a stable order, in case several new files share the same content
+	sort.Strings(missing)
+	sort.Strings(added)
+
+	addedByCheckSum := map[string][]string{}
+	for _, path := range added {
+		fileInfo, err := fr.resolveSymlink(filesFoundOnDisk[path], path)
+		if err != nil {
+			continue
+		}
+		jsonFile, err := fr.readDashboardFromFile(path, fileInfo, 0)
+		if err != nil || jsonFile.deleted {
+			continue
+		}
+		addedByCheckSum[jsonFile.checkSum] = append(addedByCheckSum[jsonFile.checkSum], path)
+	}
+
+	for _, oldPath := range missing {
+		provisioningData := provisionedDashboardRefs[oldPath]
+		candidates := addedByCheckSum[provisioningData.CheckSum]
+		if len(candidates) == 0 {
+			continue
+		}
+		newPath := candidates[0]
+		addedByCheckSum[provisioningData.CheckSum] = candidates[1:]
+
+		fr.log.Info("detected renamed dashboard file", "from", oldPath, "to", newPath, "id", provisioningData.DashboardId)
+		renamed := *provisioningData
+		renamed.ExternalId = newPath
+		if fr.Cfg.AllowUIUpdates {
+			// dashboards which can be edited from the UI aren't saved since that would revert the edits, so only
+			// their provisioning record is moved. If that fails, the rename is detected again on the next run.
+			if err := fr.dashboardProvisioningService.SetProvisionedDashboardExternalID(ctx, provisioningData.Id, newPath); err != nil {
+				fr.log.Error("failed to store new path of renamed dashboard file", "from", oldPath, "to", newPath, "error", err)
+			}
+		} else {
+			// the checksum is cleared so that the dashboard is saved, which stores its new path
+			renamed.CheckSum = ""
+		}
+		provisionedDashboardRefs[newPath] = &renamed
+		delete(provisionedDashboardRefs, oldPath)
+	}
+}
@@ -0,0 +1,89 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	reportActionUnchanged = "unchanged"
+	reportActionError     = "error"
+)
+
+// runReport is written to reportPath after every run, for tools verifying a deployment.
+type runReport struct {
+	Provisioner string        `json:"provisioner"`
+	Started     time.Time     `json:"started"`
+	DurationMs  int64         `json:"durationMs"`
+	Error       string        `json:"error,omitempty"`
+	Files       []reportEntry `json:"files"`
+}
+
+// reportEntry describes what a run did with a single dashboard file.
+type reportEntry struct {
+	File     string `json:"file"`
+	UID      string `json:"uid,omitempty"`
+	CheckSum string `json:"checkSum,omitempty"`
+	Action   string `json:"action"`
+	Error    string `json:"error,omitempty"`
+}
+
+// report records what the current run did with the file at path, when reportPath is set.
+func (fr *FileReader) report(action, path, uid, checkSum string, err error) {
+	if fr.ReportPath == "" {
+		return
+	}
+	entry := reportEntry{File: path, UID: uid, CheckSum: checkSum, Action: action}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	fr.stats.report = append(fr.stats.report, entry)
+}
+
+// writeReport writes the report of the run which started at start to reportPath. The report is written next to
+// its final location and renamed, so that readers never see a partial report.
+func (fr *FileReader) writeReport(start time.Time, runErr error) error {
+	report := runReport{
+		Provisioner: fr.Cfg.Name,
+		Started:     start,
+		DurationMs:  fr.Clock.Since(start).Milliseconds(),
+		Files:       fr.stats.report,
+	}
+	if runErr != nil {
+		report.Error = runErr.Error()
+	}
+	if report.Files == nil {
+		report.Files = []reportEntry{}
+	}
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		return report.Files[i].File < report.Files[j].File
+	})
+
+	raw, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(fr.ReportPath), filepath.Base(fr.ReportPath)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), fr.ReportPath); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestReportPath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cpu.json"), []byte(`{"title": "CPU", "uid": "cpu"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"title": `), 0600))
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+	fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+	fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+		Return(&models.Dashboard{Uid: "cpu"}, nil).Once()
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path":       dir,
+		"reportPath": reportPath,
+	}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+	require.NoError(t, reader.walkDisk(context.Background()))
+
+	raw, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+	var report runReport
+	require.NoError(t, json.Unmarshal(raw, &report))
+
+	require.Equal(t, configName, report.Provisioner)
+	require.Empty(t, report.Error)
+	require.Len(t, report.Files, 2)
+
+	require.Equal(t, filepath.Join(reader.resolvedPath(), "broken.json"), report.Files[0].File)
+	require.Equal(t, reportActionError, report.Files[0].Action)
+	require.NotEmpty(t, report.Files[0].Error)
+
+	require.Equal(t, filepath.Join(reader.resolvedPath(), "cpu.json"), report.Files[1].File)
+	require.Equal(t, actionSave, report.Files[1].Action)
+	require.Equal(t, "cpu", report.Files[1].UID)
+	require.NotEmpty(t, report.Files[1].CheckSum)
+
+	entries, err := os.ReadDir(filepath.Dir(reportPath))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary report files should be renamed")
+}
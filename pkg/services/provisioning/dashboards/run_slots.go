@@ -0,0 +1,54 @@
+package dashboards
+
+import (
+	"context"
+)
+
+// runSlots bounds how many providers of a provisioner may run at once, so that the load on the database stays bounded
+// however many providers are configured. It also bounds the folder lookups of a provider, see folderSlots. A nil
+// runSlots doesn't limit anything.
+type runSlots chan struct{}
+
+func newRunSlots(limit int) runSlots {
+	if limit <= 0 {
+		return nil
+	}
+	return make(runSlots, limit)
+}
+
+// acquire waits for a free slot, or until ctx is done. Every successful call must be followed by a call to release.
+func (s runSlots) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s runSlots) release() {
+	if s == nil {
+		return
+	}
+	<-s
+}
+
+// acquireRunSlot waits for the provider to be allowed to run, logging when it has to wait for another provider.
+func (fr *FileReader) acquireRunSlot(ctx context.Context) error {
+	if fr.runSlots == nil {
+		return nil
+	}
+
+	select {
+	case fr.runSlots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	fr.log.Debug("waiting for other providers to finish their run", "maxConcurrentRuns", cap(fr.runSlots))
+	return fr.runSlots.acquire(ctx)
+}
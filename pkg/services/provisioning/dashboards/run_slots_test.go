@@ -0,0 +1,48 @@
+package dashboards
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSlots(t *testing.T) {
+	t.Run("should not limit anything without a limit", func(t *testing.T) {
+		slots := newRunSlots(0)
+		require.Nil(t, slots)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, slots.acquire(context.Background()))
+		}
+		slots.release()
+	})
+
+	t.Run("should wait for a free slot", func(t *testing.T) {
+		slots := newRunSlots(2)
+		require.NoError(t, slots.acquire(context.Background()))
+		require.NoError(t, slots.acquire(context.Background()))
+
+		acquired := make(chan error)
+		go func() {
+			acquired <- slots.acquire(context.Background())
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("acquired more slots than the limit")
+		default:
+		}
+
+		slots.release()
+		require.NoError(t, <-acquired)
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		slots := newRunSlots(1)
+		require.NoError(t, slots.acquire(context.Background()))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, slots.acquire(ctx), context.Canceled)
+	})
+}
@@ -0,0 +1,74 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrRunTimeout is returned when a run exceeds the RunTimeout of its provisioner.
+	ErrRunTimeout = errors.New("dashboard provisioning run timed out")
+	// ErrRunAbandoned is returned while a run which exceeded the RunTimeout of its provisioner is still going on.
+	ErrRunAbandoned = errors.New("dashboard provisioning run abandoned after timing out is still in progress")
+)
+
+// runWithTimeout performs a run in a goroutine of its own, abandoning it once it exceeds RunTimeout so that a
+// provisioner stuck on an unresponsive path, like a dead network mount, doesn't hold up its callers. The context
+// of an abandoned run is canceled, but file system calls can't be interrupted, so the following runs are skipped
+// until it returns. The run slot is held by the run itself rather than by the caller, so that an abandoned run still
+// counts against the concurrent runs of the provisioner until it returns.
+func (fr *FileReader) runWithTimeout(ctx context.Context) error {
+	fr.mux.Lock()
+	abandoned := fr.runAbandoned
+	fr.mux.Unlock()
+	if abandoned {
+		return ErrRunAbandoned
+	}
+
+	if err := fr.acquireRunSlot(ctx); err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		defer fr.runSlots.release()
+		defer cancel()
+		fr.runMux.Lock()
+		defer fr.runMux.Unlock()
+
+		err := fr.run(runCtx)
+		done <- err
+
+		fr.mux.Lock()
+		defer fr.mux.Unlock()
+		if fr.runAbandoned {
+			fr.runAbandoned = false
+			fr.log.Info("abandoned dashboard provisioning run finished", "error", err)
+		}
+	}()
+
+	timer := fr.Clock.Timer(fr.RunTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	cancel()
+	fr.mux.Lock()
+	defer fr.mux.Unlock()
+	// the run may have finished in the meantime
+	select {
+	case err := <-done:
+		return err
+	default:
+	}
+	fr.runAbandoned = true
+	err := fmt.Errorf("%w after %s", ErrRunTimeout, fr.RunTimeout)
+	fr.status.LastError = err.Error()
+	fr.status.ConsecutiveFailures++
+	return err
+}
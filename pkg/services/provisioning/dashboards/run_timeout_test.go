@@ -0,0 +1,65 @@
+package dashboards
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestRunTimeout(t *testing.T) {
+	t.Run("should reject negative timeouts", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":              defaultDashboards,
+			"runTimeoutSeconds": -1,
+		}}
+		_, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("should abandon runs exceeding the timeout", func(t *testing.T) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":              t.TempDir(),
+			"runTimeoutSeconds": 60,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, reader.RunTimeout)
+		reader.RunTimeout = 20 * time.Millisecond
+		reader.runSlots = newRunSlots(1)
+
+		// the first run hangs until released
+		release := make(chan struct{})
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once().
+			Run(func(mock.Arguments) { <-release })
+
+		err = reader.walkDisk(context.Background())
+		require.ErrorIs(t, err, ErrRunTimeout)
+		require.Equal(t, 1, reader.getStatus().ConsecutiveFailures)
+
+		// the following runs are skipped until the abandoned one returns
+		require.ErrorIs(t, reader.walkDisk(context.Background()), ErrRunAbandoned)
+
+		// the abandoned run keeps its slot
+		require.Len(t, reader.runSlots, 1)
+
+		close(release)
+		require.Eventually(t, func() bool {
+			reader.mux.RLock()
+			defer reader.mux.RUnlock()
+			return !reader.runAbandoned && len(reader.runSlots) == 0
+		}, time.Second, 10*time.Millisecond)
+
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.Equal(t, 0, reader.getStatus().ConsecutiveFailures)
+	})
+}
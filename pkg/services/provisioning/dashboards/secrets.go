@@ -0,0 +1,110 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// secretDirective is the key of the objects which are replaced with the decrypted value they hold.
+const secretDirective = "$secret"
+
+// ErrSecretDecryptionFailed is returned when a secret embedded in a dashboard file can't be decrypted, in which
+// case the file is skipped.
+var ErrSecretDecryptionFailed = errors.New("failed to decrypt dashboard secret")
+
+// ErrSecretsNotStored is returned when a dashboard file holds secrets but the provider doesn't allow storing their
+// decrypted value in the database, in which case the file is skipped.
+var ErrSecretsNotStored = errors.New("dashboard holds secrets, which are only stored decrypted in the database when 'storeDecryptedSecrets' is set")
+
+// SecretsDecrypter decrypts payloads encrypted by the secrets service of Grafana.
+type SecretsDecrypter interface {
+	Decrypt(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+// decryptSecrets replaces every `{"$secret": "payload"}` object in data with the decrypted payload, which is the
+// base64 encoding of a value encrypted by the secrets service. The checksum of the file isn't affected, as it's
+// computed over the file as stored. The decrypted values end up in the saved dashboard, so they're only decrypted
+// when StoreDecryptedSecrets is set.
+func (fr *FileReader) decryptSecrets(ctx context.Context, data *simplejson.Json) error {
+	if !hasSecrets(data.Interface()) {
+		return nil
+	}
+	if !fr.StoreDecryptedSecrets {
+		return ErrSecretsNotStored
+	}
+	_, err := fr.decryptSecretsIn(ctx, data.Interface())
+	return err
+}
+
+func (fr *FileReader) decryptSecretsIn(ctx context.Context, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if payload, ok := v[secretDirective]; ok && len(v) == 1 {
+			return fr.decryptSecret(ctx, payload)
+		}
+		for key, val := range v {
+			decrypted, err := fr.decryptSecretsIn(ctx, val)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = decrypted
+		}
+	case []interface{}:
+		for i, item := range v {
+			decrypted, err := fr.decryptSecretsIn(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = decrypted
+		}
+	}
+
+	return value, nil
+}
+
+func (fr *FileReader) decryptSecret(ctx context.Context, payload interface{}) (string, error) {
+	if fr.Secrets == nil {
+		return "", fmt.Errorf("%w: no secrets service is available", ErrSecretDecryptionFailed)
+	}
+
+	encoded, ok := payload.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %q must be a string", ErrSecretDecryptionFailed, secretDirective)
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSecretDecryptionFailed, err)
+	}
+
+	decrypted, err := fr.Secrets.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSecretDecryptionFailed, err)
+	}
+	return string(decrypted), nil
+}
+
+// hasSecrets reports whether value holds any `{"$secret": "payload"}` object.
+func hasSecrets(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v[secretDirective]; ok && len(v) == 1 {
+			return true
+		}
+		for _, val := range v {
+			if hasSecrets(val) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if hasSecrets(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// reversingDecrypter "decrypts" payloads by reversing them.
+type reversingDecrypter struct{}
+
+func (reversingDecrypter) Decrypt(_ context.Context, payload []byte) ([]byte, error) {
+	if strings.HasPrefix(string(payload), "!") {
+		return nil, errors.New("unknown data key")
+	}
+	decrypted := make([]byte, len(payload))
+	for i, b := range payload {
+		decrypted[len(payload)-1-i] = b
+	}
+	return decrypted, nil
+}
+
+func TestDecryptSecrets(t *testing.T) {
+	secret := func(value string) string {
+		return `{"$secret": "` + base64.StdEncoding.EncodeToString([]byte(value)) + `"}`
+	}
+	setup := func(t *testing.T, decrypter SecretsDecrypter) *FileReader {
+		t.Helper()
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": defaultDashboards}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		reader.Secrets = decrypter
+		reader.StoreDecryptedSecrets = true
+		return reader
+	}
+
+	t.Run("should replace secrets with their decrypted value", func(t *testing.T) {
+		data, err := simplejson.NewJson([]byte(`{"title": "CPU", "panels": [{"options": {"token": ` + secret("nekot") + `}}]}`))
+		require.NoError(t, err)
+
+		require.NoError(t, setup(t, reversingDecrypter{}).decryptSecrets(context.Background(), data))
+		require.Equal(t, "token", data.Get("panels").GetIndex(0).GetPath("options", "token").MustString())
+	})
+
+	t.Run("should fail without a secrets service", func(t *testing.T) {
+		data, err := simplejson.NewJson([]byte(`{"token": ` + secret("nekot") + `}`))
+		require.NoError(t, err)
+
+		err = setup(t, nil).decryptSecrets(context.Background(), data)
+		require.ErrorIs(t, err, ErrSecretDecryptionFailed)
+	})
+
+	t.Run("should fail on secrets which can't be decrypted", func(t *testing.T) {
+		reader := setup(t, reversingDecrypter{})
+		for _, content := range []string{`{"$secret": "not base64"}`, `{"$secret": 1}`, secret("!nekot")} {
+			data, err := simplejson.NewJson([]byte(`{"token": ` + content + `}`))
+			require.NoError(t, err)
+
+			err = reader.decryptSecrets(context.Background(), data)
+			require.ErrorIs(t, err, ErrSecretDecryptionFailed, content)
+		}
+	})
+
+	t.Run("should not decrypt secrets unless they may be stored decrypted", func(t *testing.T) {
+		data, err := simplejson.NewJson([]byte(`{"token": ` + secret("nekot") + `}`))
+		require.NoError(t, err)
+
+		reader := setup(t, reversingDecrypter{})
+		reader.StoreDecryptedSecrets = false
+		err = reader.decryptSecrets(context.Background(), data)
+		require.ErrorIs(t, err, ErrSecretsNotStored)
+		require.True(t, hasSecrets(data.Interface()))
+	})
+
+	t.Run("should leave dashboards without secrets unchanged", func(t *testing.T) {
+		data, err := simplejson.NewJson([]byte(`{"title": "CPU", "tags": ["a"]}`))
+		require.NoError(t, err)
+
+		require.NoError(t, setup(t, nil).decryptSecrets(context.Background(), data))
+		require.Equal(t, "CPU", data.Get("title").MustString())
+	})
+}
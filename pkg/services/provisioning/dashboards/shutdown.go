@@ -0,0 +1,69 @@
+package dashboards
+
+import (
+	"context"
+	"time"
+)
+
+// defaultShutdownGracePeriod is how long the database calls in flight may take to finish once polling is
+// cancelled, unless configured otherwise.
+const defaultShutdownGracePeriod = 5 * time.Second
+
+// detachedContext carries the values of its parent without its cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (c detachedContext) Done() <-chan struct{}             { return nil }
+func (c detachedContext) Err() error                        { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
+type shutdownKey struct{}
+
+// drainContext returns the context of the database calls of a run. Once ctx is cancelled, the calls in flight
+// are given ShutdownGracePeriod to finish rather than being abandoned, while shuttingDown reports that no new
+// work should be started. The returned function must be called once the run is over.
+func (fr *FileReader) drainContext(ctx context.Context) (context.Context, func()) {
+	if fr.ShutdownGracePeriod <= 0 {
+		return ctx, func() {}
+	}
+
+	drainCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+	drainCtx = context.WithValue(drainCtx, shutdownKey{}, ctx.Done())
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+			return
+		}
+
+		timer := fr.Clock.Timer(fr.ShutdownGracePeriod)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			fr.log.Warn("shutdown grace period elapsed, abandoning the dashboards being saved", "gracePeriod", fr.ShutdownGracePeriod)
+			cancel()
+		case <-stop:
+		}
+	}()
+
+	return drainCtx, func() {
+		close(stop)
+		cancel()
+	}
+}
+
+// shuttingDown reports whether the run using ctx should stop starting new work.
+func shuttingDown(ctx context.Context) bool {
+	if done, ok := ctx.Value(shutdownKey{}).(<-chan struct{}); ok {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}
+	return ctx.Err() != nil
+}
@@ -0,0 +1,88 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestShutdownGracePeriod(t *testing.T) {
+	t.Run("should give the calls in flight the grace period to finish", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": defaultDashboards}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, defaultShutdownGracePeriod, reader.ShutdownGracePeriod)
+		mockClock := clock.NewMock()
+		reader.Clock = mockClock
+
+		ctx, cancel := context.WithCancel(context.Background())
+		drainCtx, stop := reader.drainContext(ctx)
+		defer stop()
+		require.False(t, shuttingDown(drainCtx))
+
+		cancel()
+		require.True(t, shuttingDown(drainCtx))
+		require.NoError(t, drainCtx.Err())
+
+		// the timer is created by the draining goroutine, so the clock is advanced until it has fired
+		require.Eventually(t, func() bool {
+			mockClock.Add(time.Second)
+			return drainCtx.Err() != nil
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should finish the dashboard being saved and skip the others", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"title": "A", "uid": "a"}`), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"title": "B", "uid": "b"}`), 0600))
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.Dashboard{}, nil).Once().
+			Run(func(args mock.Arguments) {
+				// Grafana shuts down while the first dashboard is being saved
+				cancel()
+				require.NoError(t, args.Get(0).(context.Context).Err())
+			})
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": dir}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		err = reader.walkDisk(ctx)
+		require.ErrorIs(t, err, errShuttingDown)
+	})
+
+	t.Run("should abandon the calls in flight without a grace period", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":                       defaultDashboards,
+			"shutdownGracePeriodSeconds": 0,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		drainCtx, stop := reader.drainContext(ctx)
+		defer stop()
+
+		cancel()
+		require.True(t, shuttingDown(drainCtx))
+		require.Error(t, drainCtx.Err())
+	})
+}
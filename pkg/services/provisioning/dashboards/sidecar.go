@@ -0,0 +1,75 @@
+package dashboards
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// metaSidecarSuffix is the suffix of the file holding provisioning metadata for the dashboard file
+// with the same base name, e.g. `cpu.meta.json` for `cpu.json`.
+const metaSidecarSuffix = ".meta.json"
+
+func sidecarPath(path, suffix string) string {
+	// the lines of a JSON Lines file share the sidecars of the file
+	path, _ = splitJSONLinePath(path)
+	if strings.HasSuffix(path, gzipDashboardSuffix) {
+		return strings.TrimSuffix(path, gzipDashboardSuffix) + suffix
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + suffix
+}
+
+func isSidecar(name string) bool {
+	return strings.HasSuffix(name, metaSidecarSuffix) || strings.HasSuffix(name, permissionsSidecarSuffix)
+}
+
+// readSidecar returns the parsed content of the sidecar of path along with its raw bytes,
+// or nil if there is no such sidecar.
+func readSidecar(path, suffix string) (*simplejson.Json, []byte, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	raw, err := ioutil.ReadFile(sidecarPath(path, suffix))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	data, err := simplejson.NewJson(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, raw, nil
+}
+
+// resolveFolderOverride returns the name of the folder a dashboard file asks to be provisioned into, either
+// through its meta sidecar or through a `folder` field in the dashboard itself. The sidecar takes precedence.
+// As the sidecar is not part of the dashboard file, its content is folded into the checksum of jsonFile so that
+// changing it triggers an update.
+func resolveFolderOverride(path string, jsonFile *dashboardJSONFile) (string, error) {
+	meta, raw, err := readSidecar(path, metaSidecarSuffix)
+	if err != nil {
+		return "", err
+	}
+
+	if meta != nil {
+		checkSum, err := util.Md5SumString(jsonFile.checkSum + string(raw))
+		if err != nil {
+			return "", err
+		}
+		jsonFile.checkSum = checkSum
+
+		if folder := meta.Get("folder").MustString(); folder != "" {
+			return folder, nil
+		}
+	}
+
+	return jsonFile.dashboard.Dashboard.Data.Get("folder").MustString(), nil
+}
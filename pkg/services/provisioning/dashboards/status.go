@@ -0,0 +1,137 @@
+package dashboards
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultUnhealthyAfterPolls is the number of consecutive runs the path of a provisioner can be unreadable
+// before it's reported as unhealthy.
+const defaultUnhealthyAfterPolls = 3
+
+// ProvisionerStatus describes the outcome of the last run of a dashboard provisioner.
+type ProvisionerStatus struct {
+	Name                string    `json:"name"`
+	LastRun             time.Time `json:"lastRun"`
+	LastError           string    `json:"lastError,omitempty"`
+	DurationMs          int64     `json:"durationMs"`
+	Files               int       `json:"files"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	UnreadablePolls     int       `json:"unreadablePolls"`
+	Healthy             bool      `json:"healthy"`
+	// Saved, Unchanged and Deleted count what the last run did with the dashboards.
+	Saved     int `json:"saved"`
+	Unchanged int `json:"unchanged"`
+	Deleted   int `json:"deleted"`
+	// PollsSinceLastChange is the number of consecutive runs which didn't save or delete any dashboard. It stays
+	// at zero for a provisioner which keeps updating dashboards, which usually points at a nondeterministic uid
+	// or checksum.
+	PollsSinceLastChange int `json:"pollsSinceLastChange"`
+	// Canary is set while the provisioner is restricted to the files matching its canaryPattern, which are
+	// listed in CanaryFiles.
+	Canary      bool     `json:"canary,omitempty"`
+	CanaryFiles []string `json:"canaryFiles,omitempty"`
+	// Drift is what the last run of a verify only provisioner found to differ between the files and the database.
+	Drift *DriftReport `json:"drift,omitempty"`
+	// Generation is the number of successful runs which synced the dashboards, see WaitForGeneration. Runs skipped
+	// because of an unhealthy database, a lock held by another instance or a manifest mismatch, as well as verify
+	// only runs, don't count.
+	Generation int64 `json:"generation"`
+}
+
+func (fr *FileReader) recordRun(start time.Time, err error) {
+	files := fr.getUsageTracker().files
+
+	fr.mux.Lock()
+	defer fr.mux.Unlock()
+
+	fr.status.LastRun = start
+	fr.status.DurationMs = fr.Clock.Since(start).Milliseconds()
+	fr.status.Files = files
+	fr.status.Saved = fr.stats.saved
+	fr.status.Unchanged = fr.stats.unchanged
+	fr.status.Deleted = fr.stats.deleted
+	if fr.pathUnreadable {
+		fr.status.UnreadablePolls++
+	} else {
+		fr.status.UnreadablePolls = 0
+	}
+	if fr.stats.saved > 0 || fr.stats.deleted > 0 {
+		fr.status.PollsSinceLastChange = 0
+	} else {
+		fr.status.PollsSinceLastChange++
+	}
+	if err != nil {
+		fr.status.LastError = err.Error()
+		fr.status.ConsecutiveFailures++
+		return
+	}
+
+	fr.status.LastError = ""
+	fr.status.ConsecutiveFailures = 0
+	if fr.synced {
+		fr.advanceGeneration()
+	}
+}
+
+func (fr *FileReader) getStatus() ProvisionerStatus {
+	fr.mux.RLock()
+	defer fr.mux.RUnlock()
+
+	status := fr.status
+	status.Healthy = fr.checkHealth() == nil
+	return status
+}
+
+// checkHealth returns an error when the path of the provisioner has been unreadable for more than
+// UnhealthyAfterPolls consecutive runs, so that a missing or unmounted path doesn't go unnoticed. Shorter
+// outages are tolerated. The caller must hold mux.
+func (fr *FileReader) checkHealth() error {
+	if fr.status.UnreadablePolls > fr.UnhealthyAfterPolls {
+		return fmt.Errorf("path %q has been unreadable for %d consecutive runs: %s", fr.Path, fr.status.UnreadablePolls,
+			fr.status.LastError)
+	}
+	return nil
+}
+
+const (
+	summaryLogLevelInfo  = "info"
+	summaryLogLevelDebug = "debug"
+)
+
+// runStats counts what a single run did, for the summary logged at its end.
+type runStats struct {
+	files     int
+	saved     int
+	unchanged int
+	deleted   int
+	// report lists what the run did with every file when reportPath is set.
+	report []reportEntry
+}
+
+func (s *runStats) count(action string) {
+	switch action {
+	case actionSave:
+		s.saved++
+	case actionDelete, actionUnprovision, actionTrash:
+		s.deleted++
+	}
+}
+
+// logRunSummary logs what the run did, so that an idle provisioner can be told apart from a stuck one. Runs
+// which didn't change anything are logged at debug level when configured so.
+func (fr *FileReader) logRunSummary(start time.Time) {
+	args := []interface{}{
+		"files", fr.stats.files,
+		"saved", fr.stats.saved,
+		"unchanged", fr.stats.unchanged,
+		"deleted", fr.stats.deleted,
+		"durationMs", fr.Clock.Since(start).Milliseconds(),
+	}
+
+	if fr.stats.saved == 0 && fr.stats.deleted == 0 && fr.SummaryLogLevel == summaryLogLevelDebug {
+		fr.log.Debug("provisioning run finished", args...)
+		return
+	}
+	fr.log.Info("provisioning run finished", args...)
+}
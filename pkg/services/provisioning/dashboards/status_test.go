@@ -0,0 +1,109 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestProvisionerStatus(t *testing.T) {
+	cfg := &config{
+		Name:    configName,
+		Type:    "file",
+		OrgID:   1,
+		Options: map[string]interface{}{"path": defaultDashboards},
+	}
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	t.Run("should record a successful run", func(t *testing.T) {
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&models.Dashboard{}, nil).Times(2)
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+
+		status := reader.getStatus()
+		require.Equal(t, configName, status.Name)
+		require.False(t, status.LastRun.IsZero())
+		require.Empty(t, status.LastError)
+		require.Equal(t, 2, status.Files)
+		require.Equal(t, 0, status.ConsecutiveFailures)
+		require.Equal(t, 0, status.PollsSinceLastChange)
+		require.Equal(t, runStats{files: 2, saved: 2}, reader.stats)
+	})
+
+	t.Run("should count consecutive failures", func(t *testing.T) {
+		fakeService.On("GetProvisionedDashboardData", configName).Return(nil, dashboards.ErrDashboardNotFound).Twice()
+
+		require.Error(t, reader.walkDisk(context.Background()))
+		require.Error(t, reader.walkDisk(context.Background()))
+
+		status := reader.getStatus()
+		require.NotEmpty(t, status.LastError)
+		require.Equal(t, 2, status.ConsecutiveFailures)
+		require.Equal(t, 2, status.PollsSinceLastChange)
+	})
+}
+
+func TestProvisionerHealth(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config{
+		Name:    configName,
+		Type:    "file",
+		OrgID:   1,
+		Options: map[string]interface{}{"path": dir, "unhealthyAfterPolls": 2},
+	}
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+	require.True(t, reader.getStatus().Healthy)
+
+	require.NoError(t, os.Remove(dir))
+	for i := 0; i < 2; i++ {
+		require.Error(t, reader.walkDisk(context.Background()))
+		require.True(t, reader.getStatus().Healthy, "unhealthy after %d runs", i+1)
+	}
+
+	require.Error(t, reader.walkDisk(context.Background()))
+	status := reader.getStatus()
+	require.False(t, status.Healthy)
+	require.Equal(t, 3, status.UnreadablePolls)
+
+	require.NoError(t, os.Mkdir(dir, 0750))
+	fakeService.On("GetProvisionedDashboardData", configName).Return(nil, nil).Once()
+	require.NoError(t, reader.walkDisk(context.Background()))
+	status = reader.getStatus()
+	require.True(t, status.Healthy)
+	require.Equal(t, 0, status.UnreadablePolls)
+}
+
+func TestSummaryLogLevel(t *testing.T) {
+	cfg := &config{
+		Name:    configName,
+		Type:    "file",
+		OrgID:   1,
+		Options: map[string]interface{}{"path": defaultDashboards},
+	}
+
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, summaryLogLevelInfo, reader.SummaryLogLevel)
+
+	cfg.Options["summaryLogLevel"] = "trace"
+	_, err = NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.Error(t, err)
+}
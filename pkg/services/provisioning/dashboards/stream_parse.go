@@ -0,0 +1,56 @@
+package dashboards
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// streamDashboardContent decodes the content of a dashboard file as it is read and hashes the bytes as they are
+// consumed, so that the raw content is never held in memory next to the parsed dashboard. The checksum is the same
+// as the one computed by parseDashboardContent over the whole content.
+func (fr *FileReader) streamDashboardContent(content io.Reader) (*simplejson.Json, string, error) {
+	hash := md5.New()
+	// read one byte more than allowed so that files exceeding the limit can be detected
+	counter := &countingReader{reader: io.LimitReader(content, fr.MaxFileSizeBytes+1)}
+	tee := io.TeeReader(counter, hash)
+
+	data, decodeErr := simplejson.NewFromReader(tee)
+	// the decoder stops after the first value, the rest of the content still counts towards the size and the checksum
+	if _, err := io.Copy(ioutil.Discard, tee); err != nil {
+		return nil, "", err
+	}
+	if counter.count > fr.MaxFileSizeBytes {
+		return nil, "", fmt.Errorf("%w: exceeds %d bytes", ErrFileTooLarge, fr.MaxFileSizeBytes)
+	}
+	if decodeErr != nil {
+		return nil, "", decodeErr
+	}
+
+	if fr.CanonicalChecksum {
+		canonical, err := data.Encode()
+		if err != nil {
+			return nil, "", err
+		}
+		hash.Reset()
+		_, _ = hash.Write(canonical)
+	}
+
+	return data, hex.EncodeToString(hash.Sum(nil)), nil
+}
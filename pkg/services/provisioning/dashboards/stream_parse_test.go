@@ -0,0 +1,66 @@
+package dashboards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestStreamParse(t *testing.T) {
+	newReader := func(t *testing.T, options map[string]interface{}) *FileReader {
+		options["path"] = defaultDashboards
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		return reader
+	}
+	writeDashboard := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "dashboard.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	content := "{\n  \"title\": \"CPU\",\n  \"uid\": \"cpu\",\n  \"panels\": [{\"id\": 1}]\n}\n"
+
+	t.Run("should parse and checksum the file like reading it at once", func(t *testing.T) {
+		path := writeDashboard(t, content)
+
+		data, checkSum, err := newReader(t, map[string]interface{}{"streamParse": true}).parseDashboardFile(path)
+		require.NoError(t, err)
+		expectedData, expectedCheckSum, err := newReader(t, map[string]interface{}{}).parseDashboardFile(path)
+		require.NoError(t, err)
+
+		require.Equal(t, expectedCheckSum, checkSum)
+		require.Equal(t, expectedData, data)
+	})
+
+	t.Run("should support canonical checksums", func(t *testing.T) {
+		path := writeDashboard(t, content)
+
+		_, checkSum, err := newReader(t, map[string]interface{}{"streamParse": true, "canonicalChecksum": true}).parseDashboardFile(path)
+		require.NoError(t, err)
+		_, expectedCheckSum, err := newReader(t, map[string]interface{}{"canonicalChecksum": true}).parseDashboardFile(path)
+		require.NoError(t, err)
+
+		require.Equal(t, expectedCheckSum, checkSum)
+	})
+
+	t.Run("should reject files exceeding the size limit", func(t *testing.T) {
+		path := writeDashboard(t, content)
+
+		reader := newReader(t, map[string]interface{}{"streamParse": true, "maxFileSizeBytes": int64(16)})
+		_, _, err := reader.parseDashboardFile(path)
+		require.ErrorIs(t, err, ErrFileTooLarge)
+	})
+
+	t.Run("should fail on invalid JSON", func(t *testing.T) {
+		path := writeDashboard(t, `{"title": `)
+
+		_, _, err := newReader(t, map[string]interface{}{"streamParse": true}).parseDashboardFile(path)
+		require.Error(t, err)
+	})
+}
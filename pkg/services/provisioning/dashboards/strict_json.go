@@ -0,0 +1,72 @@
+package dashboards
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrStrictJSON is returned for the dashboard files which are valid JSON to the lenient parser but not to the
+// strictJson option, such as files with duplicate keys or trailing data.
+var ErrStrictJSON = errors.New("dashboard file isn't strict JSON")
+
+// checkStrictJSON checks that content is a single JSON value without duplicate keys, which the lenient parser
+// accepts by keeping the last value of a duplicate key and ignoring whatever follows the first value.
+func checkStrictJSON(content []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	if err := checkStrictJSONValue(dec, ""); err != nil {
+		return err
+	}
+	offset := dec.InputOffset()
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: trailing data at offset %d", ErrStrictJSON, offset)
+	}
+	return nil
+}
+
+// checkStrictJSONValue reads the next value from dec, checking the objects it holds for duplicate keys. path is the
+// path of the value in the document, used in the errors.
+func checkStrictJSONValue(dec *json.Decoder, path string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		keys := map[string]bool{}
+		for dec.More() {
+			offset := dec.InputOffset()
+			token, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			if keys[key] {
+				return fmt.Errorf("%w: duplicate key %q at offset %d", ErrStrictJSON, keyPath, offset)
+			}
+			keys[key] = true
+			if err := checkStrictJSONValue(dec, keyPath); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := checkStrictJSONValue(dec, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+
+	// the closing delimiter
+	_, err = dec.Token()
+	return err
+}
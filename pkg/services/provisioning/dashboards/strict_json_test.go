@@ -0,0 +1,46 @@
+package dashboards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestStrictJSON(t *testing.T) {
+	newReader := func(t *testing.T, strict bool) *FileReader {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":       defaultDashboards,
+			"strictJson": strict,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		return reader
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{name: "valid", content: `{"title": "CPU", "panels": [{"id": 1}, {"id": 2}]}` + "\n", valid: true},
+		{name: "duplicate key", content: `{"title": "CPU", "title": "Memory"}`},
+		{name: "nested duplicate key", content: `{"title": "CPU", "panels": [{"id": 1, "id": 2}]}`},
+		{name: "trailing data", content: `{"title": "CPU"} {"title": "Memory"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := newReader(t, false).parseDashboardContent([]byte(tt.content))
+			require.NoError(t, err, "lenient parsing should accept the content")
+
+			_, _, err = newReader(t, true).parseDashboardContent([]byte(tt.content))
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrStrictJSON)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package dashboards
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultMaxSymlinkDepth is the number of symlinks a path may go through unless the maxSymlinkDepth option is set,
+// matching the limit of Linux.
+const defaultMaxSymlinkDepth = 40
+
+// ErrSymlinkDepthExceeded is returned when resolving a path goes through more symlinks than maxSymlinkDepth allows.
+var ErrSymlinkDepthExceeded = errors.New("too many levels of symbolic links")
+
+// evalSymlinks works like filepath.EvalSymlinks, but fails with ErrSymlinkDepthExceeded as soon as more than
+// maxDepth symlinks were followed, so that long or looping chains in untrusted mounts are reported clearly.
+func evalSymlinks(path string, maxDepth int) (string, error) {
+	volume := filepath.VolumeName(path)
+	pending := splitPath(path[len(volume):])
+	resolved := volume
+	if filepath.IsAbs(path) {
+		resolved = volume + string(filepath.Separator)
+	}
+
+	links := 0
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			// like filepath.EvalSymlinks, the links are resolved before going up
+			if resolved == "" || filepath.Base(resolved) == ".." {
+				resolved = filepath.Join(resolved, "..")
+			} else {
+				resolved = filepath.Dir(resolved)
+			}
+			continue
+		}
+
+		next := filepath.Join(resolved, name)
+		fileInfo, err := os.Lstat(next)
+		if err != nil {
+			return "", err
+		}
+		if fileInfo.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxDepth {
+			return "", fmt.Errorf("%w: %s goes through more than %d symlinks", ErrSymlinkDepthExceeded, path, maxDepth)
+		}
+		target, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			targetVolume := filepath.VolumeName(target)
+			resolved = targetVolume + string(filepath.Separator)
+			target = target[len(targetVolume):]
+		}
+		pending = append(splitPath(target), pending...)
+	}
+
+	if resolved == "" {
+		return ".", nil
+	}
+	return filepath.Clean(resolved), nil
+}
+
+// splitPath splits path into its elements, whatever the separator.
+func splitPath(path string) []string {
+	return strings.Split(filepath.ToSlash(path), "/")
+}
@@ -0,0 +1,58 @@
+package dashboards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestEvalSymlinks(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	file := filepath.Join(dir, "dashboard.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"title": "CPU"}`), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0750))
+
+	// c -> b -> a -> dashboard.json, with a relative link through a directory
+	require.NoError(t, os.Symlink(file, filepath.Join(dir, "a")))
+	require.NoError(t, os.Symlink(filepath.Join("..", "a"), filepath.Join(dir, "nested", "b")))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "nested", "b"), filepath.Join(dir, "c")))
+
+	t.Run("should resolve chains within the limit", func(t *testing.T) {
+		resolved, err := evalSymlinks(filepath.Join(dir, "c"), 3)
+		require.NoError(t, err)
+		require.Equal(t, file, resolved)
+
+		expected, err := filepath.EvalSymlinks(filepath.Join(dir, "nested", "..", "c"))
+		require.NoError(t, err)
+		resolved, err = evalSymlinks(filepath.Join(dir, "nested", "..", "c"), 3)
+		require.NoError(t, err)
+		require.Equal(t, expected, resolved)
+	})
+
+	t.Run("should fail on chains longer than the limit", func(t *testing.T) {
+		_, err := evalSymlinks(filepath.Join(dir, "c"), 2)
+		require.ErrorIs(t, err, ErrSymlinkDepthExceeded)
+	})
+
+	t.Run("should fail on loops", func(t *testing.T) {
+		require.NoError(t, os.Symlink(filepath.Join(dir, "loop-b"), filepath.Join(dir, "loop-a")))
+		require.NoError(t, os.Symlink(filepath.Join(dir, "loop-a"), filepath.Join(dir, "loop-b")))
+
+		_, err := evalSymlinks(filepath.Join(dir, "loop-a"), defaultMaxSymlinkDepth)
+		require.ErrorIs(t, err, ErrSymlinkDepthExceeded)
+	})
+
+	t.Run("should reject invalid limits", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":            dir,
+			"maxSymlinkDepth": 0,
+		}}
+		_, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
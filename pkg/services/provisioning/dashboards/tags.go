@@ -0,0 +1,47 @@
+package dashboards
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// tagsOption reads the `tags` option, the tags added to every dashboard of the provider.
+func tagsOption(options map[string]interface{}) ([]string, error) {
+	tags, _, err := stringListOption(options, "tags")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return nil, fmt.Errorf("option %q can't contain empty tags", "tags")
+		}
+	}
+	return tags, nil
+}
+
+// injectTags adds the tags of the provider to the tags of the dashboard, keeping the tags defined by the file. The
+// tags of the provider are folded into the checksum, so that adding or removing one updates every dashboard, which
+// drops the removed tags as the dashboards are saved from the content of their files.
+func (fr *FileReader) injectTags(data *simplejson.Json, checkSum string) (string, error) {
+	if len(fr.Tags) == 0 {
+		return checkSum, nil
+	}
+
+	fileTags := data.Get("tags").MustStringArray()
+	tags := make([]interface{}, 0, len(fileTags)+len(fr.Tags))
+	for _, tag := range fileTags {
+		tags = append(tags, tag)
+	}
+	for _, tag := range fr.Tags {
+		if !containsString(fileTags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	data.Set("tags", tags)
+
+	return util.Md5SumString(checkSum + "\n" + strings.Join(fr.Tags, "\n"))
+}
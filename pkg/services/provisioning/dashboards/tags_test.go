@@ -0,0 +1,59 @@
+package dashboards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestProviderTags(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cpu.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"title": "CPU", "uid": "cpu", "tags": ["team-a"]}`), 0600))
+	fileInfo, err := os.Stat(path)
+	require.NoError(t, err)
+
+	read := func(t *testing.T, tags ...interface{}) ([]string, string) {
+		t.Helper()
+		options := map[string]interface{}{"path": dir}
+		if len(tags) > 0 {
+			options["tags"] = tags
+		}
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+
+		jsonFile, err := reader.readDashboardFromFile(path, fileInfo, 0)
+		require.NoError(t, err)
+		return jsonFile.dashboard.Dashboard.Data.Get("tags").MustStringArray(), jsonFile.checkSum
+	}
+
+	fileTags, fileCheckSum := read(t)
+	require.Equal(t, []string{"team-a"}, fileTags)
+
+	t.Run("should add the tags of the provider to the tags of the file", func(t *testing.T) {
+		tags, checkSum := read(t, "provisioned", "team-a", "prod")
+		require.Equal(t, []string{"team-a", "provisioned", "prod"}, tags)
+		require.NotEqual(t, fileCheckSum, checkSum)
+	})
+
+	t.Run("should update the dashboards when the tags of the provider change", func(t *testing.T) {
+		_, before := read(t, "provisioned", "prod")
+		tags, after := read(t, "provisioned")
+		require.Equal(t, []string{"team-a", "provisioned"}, tags)
+		require.NotEqual(t, before, after)
+	})
+
+	t.Run("should reject empty tags", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path": dir,
+			"tags": []interface{}{"provisioned", " "},
+		}}
+		_, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,167 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// defaultTrashFolder is the folder soft deleted dashboards are moved to unless the trashFolder option is set.
+const defaultTrashFolder = "Trash"
+
+// trashedTagPrefix prefixes the tag recording when a dashboard was moved to the trash folder, in unix seconds. The
+// time is stored with the dashboard rather than in memory, so that the trash is still swept after a restart.
+const trashedTagPrefix = "trashed-at:"
+
+// setTrashedTag replaces the trashed tag of the dashboard, if any, with one recording the given time.
+func setTrashedTag(dash *models.Dashboard, at time.Time) {
+	if dash.Data == nil {
+		dash.Data = simplejson.New()
+	}
+	tags := []interface{}{}
+	for _, tag := range dash.Data.Get("tags").MustStringArray() {
+		if !strings.HasPrefix(tag, trashedTagPrefix) {
+			tags = append(tags, tag)
+		}
+	}
+	dash.Data.Set("tags", append(tags, trashedTagPrefix+strconv.FormatInt(at.Unix(), 10)))
+}
+
+// trashedAt returns when the dashboard was moved to the trash folder according to its trashed tag.
+func trashedAt(dash *models.Dashboard) (time.Time, bool) {
+	if dash.Data == nil {
+		return time.Time{}, false
+	}
+	for _, tag := range dash.Data.Get("tags").MustStringArray() {
+		if !strings.HasPrefix(tag, trashedTagPrefix) {
+			continue
+		}
+		seconds, err := strconv.ParseInt(strings.TrimPrefix(tag, trashedTagPrefix), 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(seconds, 0), true
+	}
+	return time.Time{}, false
+}
+
+// trashProvisionedDashboard moves the dashboard of a file which no longer provisions it to the trash folder, tags it
+// with the time and unprovisions it, so that it can be recovered by moving it out of the trash folder until it's
+// deleted by sweepTrash.
+func (fr *FileReader) trashProvisionedDashboard(ctx context.Context, provisioningData *models.DashboardProvisioning, reason string) {
+	dashboardID := provisioningData.DashboardId
+	orgID := fr.Cfg.OrgID
+	if fr.OrgIDFromPath {
+		orgID = fr.orgIDForPath(fr.resolvedPath(), provisioningData.ExternalId)
+	}
+
+	query := &models.GetDashboardQuery{Id: dashboardID, OrgId: orgID}
+	if err := fr.dashboardStore.GetDashboard(ctx, query); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			// there is nothing left to recover, only the provisioning data
+			fr.removeProvisionedDashboard(ctx, provisioningData, reason)
+			return
+		}
+		fr.log.Error("failed to get dashboard to move it to the trash", "id", dashboardID, "error", err)
+		return
+	}
+
+	trashID, err := fr.getOrCreateOverrideFolderID(ctx, fr.TrashFolder, orgID)
+	if err != nil {
+		fr.log.Error("failed to provision trash folder", "folder", fr.TrashFolder, "error", err)
+		return
+	}
+
+	if err := fr.waitForWrite(ctx); err != nil {
+		fr.log.Error("failed to move dashboard to the trash", "id", dashboardID, "error", err)
+		return
+	}
+
+	fr.log.Debug("moving provisioned dashboard to the trash", "id", dashboardID, "folder", fr.TrashFolder,
+		"reason", reason)
+	start := fr.Clock.Now()
+	dash := query.Result
+	dash.FolderId = trashID
+	setTrashedTag(dash, start)
+	dto := &dashboards.SaveDashboardDTO{OrgId: orgID, UpdatedAt: start, Overwrite: true, Dashboard: dash}
+	if _, err := fr.dashboardProvisioningService.SaveProvisionedDashboard(ctx, dto, provisioningData); err != nil {
+		fr.log.Error("failed to move dashboard to the trash", "id", dashboardID, "error", err)
+		return
+	}
+	if err := fr.dashboardProvisioningService.UnprovisionDashboard(ctx, dashboardID); err != nil {
+		fr.log.Error("failed to unprovision trashed dashboard", "id", dashboardID, "error", err)
+		return
+	}
+
+	fr.logAction(actionTrash, provisioningData.ExternalId, dash.Uid, trashID, provisioningData.CheckSum, start)
+}
+
+// sweepTrash deletes the dashboards which stayed in the trash folder for longer than TrashRetention, as recorded by
+// their trashed tag. Dashboards moved out of the trash folder in the meantime are considered recovered and left alone.
+func (fr *FileReader) sweepTrash(ctx context.Context) {
+	for _, orgID := range fr.trashOrgIDs() {
+		query := &models.GetDashboardsByOrgIdQuery{OrgId: orgID, ExcludeProvisioned: true}
+		if err := fr.dashboardProvisioningService.GetDashboardsByOrgID(ctx, query); err != nil {
+			fr.log.Error("failed to list dashboards to sweep the trash", "orgId", orgID, "error", err)
+			continue
+		}
+
+		var trashID int64
+		for _, dash := range query.Result {
+			at, ok := trashedAt(dash)
+			if !ok || fr.Clock.Since(at) < fr.TrashRetention {
+				continue
+			}
+
+			// the trash folder is only looked up once there is something to sweep, so that it isn't created for nothing
+			if trashID == 0 {
+				id, err := fr.getOrCreateOverrideFolderID(ctx, fr.TrashFolder, orgID)
+				if err != nil {
+					fr.log.Error("failed to provision trash folder", "folder", fr.TrashFolder, "error", err)
+					break
+				}
+				trashID = id
+			}
+			if dash.FolderId != trashID {
+				fr.log.Debug("keeping dashboard recovered from the trash", "id", dash.Id, "uid", dash.Uid)
+				continue
+			}
+
+			if err := fr.waitForWrite(ctx); err != nil {
+				fr.log.Error("failed to delete trashed dashboard", "id", dash.Id, "error", err)
+				return
+			}
+			start := fr.Clock.Now()
+			if err := fr.dashboardProvisioningService.DeleteProvisionedDashboard(ctx, dash.Id, orgID); err != nil {
+				fr.log.Error("failed to delete trashed dashboard", "id", dash.Id, "error", err)
+				continue
+			}
+			fr.logAction(actionDelete, "", dash.Uid, trashID, "", start)
+		}
+	}
+}
+
+// trashOrgIDs returns the organizations the provider moves dashboards to the trash of.
+func (fr *FileReader) trashOrgIDs() []int64 {
+	orgIDs := []int64{fr.Cfg.OrgID}
+	if !fr.OrgIDFromPath {
+		return orgIDs
+	}
+
+	seen := map[int64]bool{fr.Cfg.OrgID: true}
+	for _, orgID := range fr.OrgIDMapping {
+		if !seen[orgID] {
+			seen[orgID] = true
+			orgIDs = append(orgIDs, orgID)
+		}
+	}
+	sort.Slice(orgIDs[1:], func(i, j int) bool { return orgIDs[i+1] < orgIDs[j+1] })
+	return orgIDs
+}
@@ -0,0 +1,117 @@
+package dashboards
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestSoftDelete(t *testing.T) {
+	newReader := func(t *testing.T, store idDashboardStore) (*FileReader, *dashboards.FakeDashboardProvisioning, *clock.Mock) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":                  defaultDashboards,
+			"softDelete":            true,
+			"trashRetentionSeconds": 3600,
+		}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, store)
+		require.NoError(t, err)
+		mockClock := clock.NewMock()
+		reader.Clock = mockClock
+		return reader, fakeService, mockClock
+	}
+	provisioningData := &models.DashboardProvisioning{DashboardId: 2, Name: configName, ExternalId: "/dashboards/cpu.json"}
+
+	trash := func(t *testing.T, reader *FileReader, fakeService *dashboards.FakeDashboardProvisioning) {
+		fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 10}, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, provisioningData).Return(&models.Dashboard{Id: 2}, nil).Once()
+		fakeService.On("UnprovisionDashboard", mock.Anything, int64(2)).Return(nil).Once()
+		reader.deleteProvisionedDashboard(context.Background(), provisioningData, "missing on disk")
+	}
+	listed := func(store idDashboardStore) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			query := args.Get(1).(*models.GetDashboardsByOrgIdQuery)
+			query.Result = []*models.Dashboard{store[2]}
+		}
+	}
+
+	t.Run("should move the dashboard to the trash and delete it after the retention", func(t *testing.T) {
+		store := idDashboardStore{2: {Id: 2, OrgId: 1, Uid: "cpu", Title: "CPU", Data: simplejson.New()}}
+		reader, fakeService, mockClock := newReader(t, store)
+		trash(t, reader, fakeService)
+		require.Equal(t, int64(10), store[2].FolderId)
+		require.Equal(t, []string{"trashed-at:0"}, store[2].Data.Get("tags").MustStringArray())
+
+		fakeService.On("GetDashboardsByOrgID", mock.Anything, mock.Anything).Run(listed(store)).Return(nil)
+		reader.sweepTrash(context.Background())
+		fakeService.AssertNotCalled(t, "DeleteProvisionedDashboard", mock.Anything, int64(2), int64(1))
+
+		fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(2), int64(1)).Return(nil).Once()
+		mockClock.Add(time.Hour)
+		reader.sweepTrash(context.Background())
+	})
+
+	t.Run("should delete the dashboard after the retention when the provisioner restarted", func(t *testing.T) {
+		store := idDashboardStore{2: {Id: 2, OrgId: 1, Uid: "cpu", Title: "CPU", Data: simplejson.New()}}
+		reader, fakeService, _ := newReader(t, store)
+		trash(t, reader, fakeService)
+
+		restarted, fakeService, mockClock := newReader(t, store)
+		mockClock.Add(time.Hour)
+		fakeService.On("GetDashboardsByOrgID", mock.Anything, mock.Anything).Run(listed(store)).Return(nil)
+		fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 10}, nil).Once()
+		fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(2), int64(1)).Return(nil).Once()
+		restarted.sweepTrash(context.Background())
+	})
+
+	t.Run("should keep dashboards recovered from the trash", func(t *testing.T) {
+		store := idDashboardStore{2: {Id: 2, OrgId: 1, Uid: "cpu", Title: "CPU", Data: simplejson.New()}}
+		reader, fakeService, mockClock := newReader(t, store)
+		trash(t, reader, fakeService)
+		store[2].FolderId = 0
+
+		fakeService.On("GetDashboardsByOrgID", mock.Anything, mock.Anything).Run(listed(store)).Return(nil)
+		mockClock.Add(time.Hour)
+		reader.sweepTrash(context.Background())
+		fakeService.AssertNotCalled(t, "DeleteProvisionedDashboard", mock.Anything, int64(2), int64(1))
+	})
+
+	t.Run("should move dashboards of files marked as deleted or disabled to the trash", func(t *testing.T) {
+		for _, reason := range []string{"marked as deleted", "disabled"} {
+			store := idDashboardStore{2: {Id: 2, OrgId: 1, Uid: "cpu", Title: "CPU", Data: simplejson.New()}}
+			reader, fakeService, _ := newReader(t, store)
+			fakeService.On("SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything).Return(&models.Dashboard{Id: 10}, nil).Once()
+			fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, provisioningData).Return(&models.Dashboard{Id: 2}, nil).Once()
+			fakeService.On("UnprovisionDashboard", mock.Anything, int64(2)).Return(nil).Once()
+
+			reader.deleteProvisionedDashboard(context.Background(), provisioningData, reason)
+			require.Equal(t, int64(10), store[2].FolderId, reason)
+			fakeService.AssertNotCalled(t, "DeleteProvisionedDashboard", mock.Anything, int64(2), int64(1))
+		}
+	})
+}
+
+func TestTrashedTag(t *testing.T) {
+	dash := &models.Dashboard{Data: simplejson.NewFromAny(map[string]interface{}{
+		"tags": []interface{}{"cpu", "trashed-at:1"},
+	})}
+	setTrashedTag(dash, time.Unix(120, 0))
+	require.Equal(t, []string{"cpu", "trashed-at:120"}, dash.Data.Get("tags").MustStringArray())
+
+	at, ok := trashedAt(dash)
+	require.True(t, ok)
+	require.Equal(t, time.Unix(120, 0), at)
+
+	_, ok = trashedAt(&models.Dashboard{Data: simplejson.New()})
+	require.False(t, ok)
+}
@@ -21,6 +21,13 @@ type config struct {
 	DisableDeletion       bool
 	UpdateIntervalSeconds int64
 	AllowUIUpdates        bool
+
+	// file is the name of the config file the provider was read from.
+	file string
+	// dir is the directory of that config file, against which a relative path of the provider is resolved.
+	dir string
+	// dataPath is the data directory of Grafana, under which the archive of the provider is extracted.
+	dataPath string
 }
 
 type configV0 struct {
@@ -36,10 +43,6 @@ type configV0 struct {
 	AllowUIUpdates        bool                   `json:"allowUiUpdates" yaml:"allowUiUpdates"`
 }
 
-type configVersion struct {
-	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
-}
-
 type configV1 struct {
 	Providers []*configs `json:"providers" yaml:"providers"`
 }
@@ -0,0 +1,38 @@
+package dashboards
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// uidPrefixOption reads the `uidPrefix` option, which namespaces the uids of the dashboards of the provider.
+func uidPrefixOption(options map[string]interface{}) (string, error) {
+	prefix, _ := options["uidPrefix"].(string)
+	if prefix != "" && !util.IsValidShortUID(prefix) {
+		return "", fmt.Errorf("invalid 'uidPrefix' option %q, expected letters, digits, '-' or '_'", prefix)
+	}
+	return prefix, nil
+}
+
+// prefixUID prepends the uid prefix of the provider to the uid of the dashboard. Uids already starting with the
+// prefix are prefixed as well, since leaving them alone would make `a` and `team-a` collide with the prefix `team-`.
+// Dashboards without uid are left alone. The prefix is folded into the checksum, so that changing it updates every
+// dashboard.
+func (fr *FileReader) prefixUID(data *simplejson.Json, checkSum string) (string, error) {
+	if fr.UIDPrefix == "" {
+		return checkSum, nil
+	}
+
+	uid := data.Get("uid").MustString()
+	if uid != "" {
+		uid = fr.UIDPrefix + uid
+		if util.IsShortUIDTooLong(uid) {
+			return "", fmt.Errorf("%w: uid %q exceeds 40 characters once prefixed", ErrInvalidDashboard, uid)
+		}
+		data.Set("uid", uid)
+	}
+
+	return util.Md5SumString(checkSum + "\nuidPrefix:" + fr.UIDPrefix)
+}
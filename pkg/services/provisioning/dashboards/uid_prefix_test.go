@@ -0,0 +1,83 @@
+package dashboards
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestUIDPrefix(t *testing.T) {
+	newReader := func(t *testing.T, prefix string) (*FileReader, error) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":      defaultDashboards,
+			"uidPrefix": prefix,
+		}}
+		return NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	}
+	dashboard := func(uid string) *simplejson.Json {
+		return simplejson.NewFromAny(map[string]interface{}{"title": "CPU", "uid": uid})
+	}
+
+	t.Run("should reject invalid prefixes", func(t *testing.T) {
+		_, err := newReader(t, "team a/")
+		require.Error(t, err)
+	})
+
+	t.Run("should prefix the uid and the checksum", func(t *testing.T) {
+		reader, err := newReader(t, "team-a-")
+		require.NoError(t, err)
+
+		data := dashboard("cpu")
+		checkSum, err := reader.prefixUID(data, "checksum")
+		require.NoError(t, err)
+		require.Equal(t, "team-a-cpu", data.Get("uid").MustString())
+		require.NotEqual(t, "checksum", checkSum)
+	})
+
+	t.Run("should prefix uids already starting with the prefix", func(t *testing.T) {
+		reader, err := newReader(t, "team-")
+		require.NoError(t, err)
+
+		short, prefixed := dashboard("a"), dashboard("team-a")
+		_, err = reader.prefixUID(short, "checksum")
+		require.NoError(t, err)
+		_, err = reader.prefixUID(prefixed, "checksum")
+		require.NoError(t, err)
+		require.Equal(t, "team-a", short.Get("uid").MustString())
+		require.Equal(t, "team-team-a", prefixed.Get("uid").MustString())
+	})
+
+	t.Run("should leave dashboards without uid alone", func(t *testing.T) {
+		reader, err := newReader(t, "team-a-")
+		require.NoError(t, err)
+
+		data := simplejson.NewFromAny(map[string]interface{}{"title": "CPU"})
+		_, err = reader.prefixUID(data, "checksum")
+		require.NoError(t, err)
+		_, ok := data.CheckGet("uid")
+		require.False(t, ok)
+	})
+
+	t.Run("should reject uids too long once prefixed", func(t *testing.T) {
+		reader, err := newReader(t, "team-a-")
+		require.NoError(t, err)
+
+		_, err = reader.prefixUID(dashboard(strings.Repeat("a", 40)), "checksum")
+		require.ErrorIs(t, err, ErrInvalidDashboard)
+	})
+
+	t.Run("should leave the uid and the checksum alone without prefix", func(t *testing.T) {
+		reader, err := newReader(t, "")
+		require.NoError(t, err)
+
+		data := dashboard("cpu")
+		checkSum, err := reader.prefixUID(data, "checksum")
+		require.NoError(t, err)
+		require.Equal(t, "cpu", data.Get("uid").MustString())
+		require.Equal(t, "checksum", checkSum)
+	})
+}
@@ -0,0 +1,61 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// UnprovisionAll removes every dashboard provisioned by the provider with the given name, deleting them if
+// deleteDashboards is set and only unprovisioning them otherwise, so that they are kept as regular dashboards. It
+// returns the number of dashboards removed, and works for providers which are no longer configured too. A provider
+// which is still configured provisions its dashboards again on its next run, so it should be removed from the
+// config files first.
+func (provider *Provisioner) UnprovisionAll(ctx context.Context, name string, deleteDashboards bool) (int, error) {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+
+	// a configured provider must not save dashboards while they are being removed
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name == name {
+			reader.runMux.Lock()
+			defer reader.runMux.Unlock()
+		}
+	}
+
+	provisioned, err := provider.provisioner.GetProvisionedDashboardData(name)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var failures []string
+	for _, data := range provisioned {
+		if deleteDashboards {
+			// the org is left out since the dashboards of a provider may span several orgs, the id is unique anyway
+			err = provider.provisioner.DeleteProvisionedDashboard(ctx, data.DashboardId, 0)
+			if errors.Is(err, dashboards.ErrDashboardNotFound) {
+				err = nil
+			}
+		} else {
+			err = provider.provisioner.UnprovisionDashboard(ctx, data.DashboardId)
+		}
+		if err != nil {
+			provider.log.Warn("Failed to remove provisioned dashboard", "provisioner", name, "dashboardId", data.DashboardId,
+				"file", data.ExternalId, "err", err)
+			failures = append(failures, fmt.Sprintf("dashboard %d: %v", data.DashboardId, err))
+			continue
+		}
+		count++
+	}
+
+	provider.log.Info("Removed provisioned dashboards", "provisioner", name, "count", count, "deleted", deleteDashboards,
+		"failed", len(failures))
+	if len(failures) > 0 {
+		return count, fmt.Errorf("failed to remove %d dashboards: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return count, nil
+}
@@ -0,0 +1,58 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestUnprovisionAll(t *testing.T) {
+	provisioned := []*models.DashboardProvisioning{
+		{DashboardId: 1, Name: configName, ExternalId: "/dashboards/cpu.json"},
+		{DashboardId: 2, Name: configName, ExternalId: "/dashboards/memory.json"},
+	}
+
+	setup := func() (*Provisioner, *dashboards.FakeDashboardProvisioning) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+		fakeService.On("GetProvisionedDashboardData", configName).Return(provisioned, nil).Once()
+		return &Provisioner{log: log.New("test-logger"), provisioner: fakeService}, fakeService
+	}
+
+	t.Run("should unprovision every dashboard of the provider", func(t *testing.T) {
+		provisioner, fakeService := setup()
+		fakeService.On("UnprovisionDashboard", mock.Anything, int64(1)).Return(nil).Once()
+		fakeService.On("UnprovisionDashboard", mock.Anything, int64(2)).Return(nil).Once()
+
+		count, err := provisioner.UnprovisionAll(context.Background(), configName, false)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("should delete every dashboard of the provider", func(t *testing.T) {
+		provisioner, fakeService := setup()
+		fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(1), int64(0)).Return(nil).Once()
+		fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(2), int64(0)).Return(nil).Once()
+
+		count, err := provisioner.UnprovisionAll(context.Background(), configName, true)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("should carry on and report failures", func(t *testing.T) {
+		provisioner, fakeService := setup()
+		fakeService.On("UnprovisionDashboard", mock.Anything, int64(1)).Return(errors.New("database is locked")).Once()
+		fakeService.On("UnprovisionDashboard", mock.Anything, int64(2)).Return(nil).Once()
+
+		count, err := provisioner.UnprovisionAll(context.Background(), configName, false)
+		require.Error(t, err)
+		require.Equal(t, 1, count)
+	})
+}
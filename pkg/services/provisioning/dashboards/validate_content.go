@@ -0,0 +1,65 @@
+package dashboards
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// defaultValidatedFile is the file the validated content is read as when no path is given.
+const defaultValidatedFile = "dashboard.json"
+
+// DashboardValidation is the outcome of validating the content of a dashboard file against a provider.
+type DashboardValidation struct {
+	Valid bool `json:"valid"`
+	// Dashboard is the dashboard as it would be saved, once the includes, transformers and tags of the provider
+	// were applied.
+	Dashboard   *simplejson.Json `json:"dashboard,omitempty"`
+	CheckSum    string           `json:"checkSum,omitempty"`
+	Deleted     bool             `json:"deleted,omitempty"`
+	Disabled    bool             `json:"disabled,omitempty"`
+	Unsupported bool             `json:"unsupported,omitempty"`
+	Errors      []string         `json:"errors,omitempty"`
+}
+
+// validateDashboardContent reads content the same way the file at path, relative to the path of the provider, would
+// be provisioned, without saving anything. Includes are still read from disk, relative to path.
+func (fr *FileReader) validateDashboardContent(path string, content []byte) (DashboardValidation, error) {
+	if path == "" {
+		path = defaultValidatedFile
+	}
+	cleanPath := filepath.Clean(path)
+	if filepath.IsAbs(cleanPath) || cleanPath == ".." || strings.HasPrefix(cleanPath, ".."+string(filepath.Separator)) {
+		return DashboardValidation{}, fmt.Errorf("%w: %s", ErrFileOutsidePath, path)
+	}
+	path = filepath.Join(fr.resolvedPath(), cleanPath)
+
+	invalid := func(err error) (DashboardValidation, error) {
+		return DashboardValidation{Errors: []string{err.Error()}}, nil
+	}
+
+	data, checkSum, err := fr.parseDashboardContent(content)
+	if err != nil {
+		return invalid(err)
+	}
+	jsonFile, err := fr.dashboardFromContent(path, data, checkSum, fr.Clock.Now(), 0)
+	if err != nil {
+		return invalid(err)
+	}
+	if jsonFile.deleted {
+		return DashboardValidation{Valid: true, CheckSum: jsonFile.checkSum, Deleted: true}, nil
+	}
+	if _, err := resolveFolderOverride(path, jsonFile); err != nil {
+		return invalid(err)
+	}
+
+	return DashboardValidation{
+		Valid:       true,
+		Dashboard:   jsonFile.dashboard.Dashboard.Data,
+		CheckSum:    jsonFile.checkSum,
+		Disabled:    jsonFile.disabled,
+		Unsupported: jsonFile.unsupported,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package dashboards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestValidateDashboardContent(t *testing.T) {
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path": t.TempDir(),
+		"tags": []interface{}{"provisioned"},
+	}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.NoError(t, err)
+
+	t.Run("should return the dashboard as it would be saved", func(t *testing.T) {
+		validation, err := reader.validateDashboardContent("", []byte(`{"title": "CPU", "uid": "cpu"}`))
+		require.NoError(t, err)
+		require.True(t, validation.Valid)
+		require.Empty(t, validation.Errors)
+		require.NotEmpty(t, validation.CheckSum)
+		require.Equal(t, "CPU", validation.Dashboard.Get("title").MustString())
+		require.Equal(t, []string{"provisioned"}, validation.Dashboard.Get("tags").MustStringArray())
+	})
+
+	t.Run("should report invalid dashboards", func(t *testing.T) {
+		for _, content := range []string{`{"title": "CPU"`, `{"uid": "cpu"}`, `{"title": "CPU", "panels": {}}`} {
+			validation, err := reader.validateDashboardContent("team/cpu.json", []byte(content))
+			require.NoError(t, err)
+			require.False(t, validation.Valid, content)
+			require.Len(t, validation.Errors, 1, content)
+			require.Nil(t, validation.Dashboard, content)
+		}
+	})
+
+	t.Run("should report delete markers", func(t *testing.T) {
+		validation, err := reader.validateDashboardContent("", []byte(`{"__deleted": true}`))
+		require.NoError(t, err)
+		require.True(t, validation.Valid)
+		require.True(t, validation.Deleted)
+	})
+
+	t.Run("should reject paths outside of the path of the provider", func(t *testing.T) {
+		_, err := reader.validateDashboardContent("../cpu.json", []byte(`{"title": "CPU"}`))
+		require.ErrorIs(t, err, ErrFileOutsidePath)
+	})
+}
@@ -2,6 +2,7 @@ package dashboards
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -50,10 +51,18 @@ func (d *duplicateEntries) InvolvedReaders() map[string]struct{} {
 type duplicateValidator struct {
 	logger  log.Logger
 	readers []*FileReader
+	mutex   *sync.Mutex
 }
 
 func newDuplicateValidator(logger log.Logger, readers []*FileReader) duplicateValidator {
-	return duplicateValidator{logger: logger, readers: readers}
+	return duplicateValidator{logger: logger, readers: readers, mutex: &sync.Mutex{}}
+}
+
+// setReaders replaces the readers to validate, when the providers are reloaded.
+func (c *duplicateValidator) setReaders(readers []*FileReader) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.readers = readers
 }
 
 func (c *duplicateValidator) getDuplicates() map[int64]duplicateEntries {
@@ -142,6 +151,9 @@ func (c *duplicateValidator) Run(ctx context.Context) {
 }
 
 func (c *duplicateValidator) validate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	duplicates := c.getDuplicates()
 
 	c.logWarnings(duplicates)
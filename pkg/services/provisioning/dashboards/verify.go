@@ -0,0 +1,91 @@
+package dashboards
+
+import (
+	"errors"
+	"os"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// ErrVerifyOnly is returned when asking a provisioner which only verifies its dashboards to save one.
+var ErrVerifyOnly = errors.New("provisioner only verifies its dashboards")
+
+// DriftReport lists the files whose dashboard doesn't match what is stored in the database, as found by the last run
+// of a verify only provisioner.
+type DriftReport struct {
+	// Added lists the files whose dashboard isn't provisioned.
+	Added []string `json:"added"`
+	// Changed lists the files whose content differs from the provisioned dashboard.
+	Changed []string `json:"changed"`
+	// Removed lists the files of provisioned dashboards which are gone, deleted or disabled.
+	Removed []string `json:"removed"`
+}
+
+// Drifted reports whether the database doesn't match the files.
+func (r *DriftReport) Drifted() bool {
+	return len(r.Added) > 0 || len(r.Changed) > 0 || len(r.Removed) > 0
+}
+
+// detectDrift compares the files found on disk with the dashboards provisioned from them, without writing anything.
+// Invalid files are logged and left out, like a regular run skips them.
+func (fr *FileReader) detectDrift(provisionedDashboardRefs map[string]*models.DashboardProvisioning,
+	filesFoundOnDisk map[string]os.FileInfo) *DriftReport {
+	report := &DriftReport{Added: []string{}, Changed: []string{}, Removed: []string{}}
+
+	for path := range provisionedDashboardRefs {
+		if _, existsOnDisk := filesFoundOnDisk[path]; !existsOnDisk {
+			report.Removed = append(report.Removed, path)
+		}
+	}
+
+	for _, path := range fr.sortedPaths(filesFoundOnDisk) {
+		fileInfo, err := fr.resolveSymlink(filesFoundOnDisk[path], path)
+		if err != nil {
+			fr.log.Warn("failed to verify dashboard file", "file", path, "error", err)
+			continue
+		}
+		jsonFile, err := fr.readDashboardFromFile(path, fileInfo, 0)
+		if err != nil {
+			fr.log.Warn("failed to verify dashboard file", "file", path, "error", err)
+			continue
+		}
+		if !fr.isKindAllowed(jsonFile.kind) || jsonFile.unsupported {
+			continue
+		}
+
+		provisionedData, alreadyProvisioned := provisionedDashboardRefs[path]
+		switch {
+		case jsonFile.deleted || jsonFile.disabled:
+			if alreadyProvisioned {
+				report.Removed = append(report.Removed, path)
+			}
+		case !alreadyProvisioned:
+			report.Added = append(report.Added, path)
+		case jsonFile.checkSum != provisionedData.CheckSum:
+			report.Changed = append(report.Changed, path)
+		}
+	}
+
+	sort.Strings(report.Removed)
+	return report
+}
+
+// recordDrift publishes the outcome of a verify only run through the status, the metrics and the log.
+func (fr *FileReader) recordDrift(report *DriftReport) {
+	fr.mux.Lock()
+	fr.status.Drift = report
+	fr.mux.Unlock()
+
+	metrics.MProvisioningDashboardDrift.WithLabelValues(fr.Cfg.Name, "added").Set(float64(len(report.Added)))
+	metrics.MProvisioningDashboardDrift.WithLabelValues(fr.Cfg.Name, "changed").Set(float64(len(report.Changed)))
+	metrics.MProvisioningDashboardDrift.WithLabelValues(fr.Cfg.Name, "removed").Set(float64(len(report.Removed)))
+
+	if !report.Drifted() {
+		fr.log.Debug("provisioned dashboards match their files")
+		return
+	}
+	fr.log.Warn("provisioned dashboards differ from their files", "added", report.Added, "changed", report.Changed,
+		"removed", report.Removed)
+}
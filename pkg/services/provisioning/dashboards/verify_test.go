@@ -0,0 +1,63 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestVerifyOnly(t *testing.T) {
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+
+	absPath, err := filepath.Abs(defaultDashboards)
+	require.NoError(t, err)
+	changed := filepath.Join(absPath, "dashboard1.json")
+	removed := filepath.Join(absPath, "gone.json")
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+		"path":       defaultDashboards,
+		"verifyOnly": true,
+	}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	t.Run("should report the drift without writing", func(t *testing.T) {
+		// any save or delete would fail the test, as the mock doesn't expect them
+		fakeService.On("GetProvisionedDashboardData", configName).Return([]*models.DashboardProvisioning{
+			{DashboardId: 1, Name: configName, ExternalId: changed, CheckSum: "stale"},
+			{DashboardId: 2, Name: configName, ExternalId: removed},
+		}, nil).Once()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+
+		drift := reader.getStatus().Drift
+		require.NotNil(t, drift)
+		require.True(t, drift.Drifted())
+		require.Equal(t, []string{filepath.Join(absPath, "dashboard2.json")}, drift.Added)
+		require.Equal(t, []string{changed}, drift.Changed)
+		require.Equal(t, []string{removed}, drift.Removed)
+	})
+
+	t.Run("should refuse to provision a single file", func(t *testing.T) {
+		_, err := reader.ProvisionFile(context.Background(), "dashboard1.json")
+		require.True(t, errors.Is(err, ErrVerifyOnly))
+	})
+
+	t.Run("can't be used with interval rules", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":          defaultDashboards,
+			"verifyOnly":    true,
+			"intervalRules": []interface{}{map[string]interface{}{"pattern": "*.json", "updateIntervalSeconds": 1}},
+		}}
+		_, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+package dashboards
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// supportsGrafanaVersion reports whether the running Grafana version lies within the `minGrafanaVersion` and
+// `maxGrafanaVersion` fields of a dashboard, both inclusive and both optional. Every version is supported when the
+// running version is unknown, as with development builds.
+func (fr *FileReader) supportsGrafanaVersion(data *simplejson.Json) (bool, error) {
+	current, err := version.NewVersion(fr.grafanaVersion)
+	if err != nil {
+		return true, nil
+	}
+
+	for _, bound := range []struct {
+		key       string
+		satisfied func(v *version.Version) bool
+	}{
+		{key: "minGrafanaVersion", satisfied: func(v *version.Version) bool { return !current.LessThan(v) }},
+		{key: "maxGrafanaVersion", satisfied: func(v *version.Version) bool { return !current.GreaterThan(v) }},
+	} {
+		raw, ok := data.CheckGet(bound.key)
+		if !ok || raw.Interface() == nil {
+			continue
+		}
+		s, err := raw.String()
+		if err != nil {
+			return false, fmt.Errorf("%w: '%s' must be a string", ErrInvalidDashboard, bound.key)
+		}
+		v, err := version.NewVersion(s)
+		if err != nil {
+			return false, fmt.Errorf("%w: invalid '%s' %q: %v", ErrInvalidDashboard, bound.key, s, err)
+		}
+		if !bound.satisfied(v) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
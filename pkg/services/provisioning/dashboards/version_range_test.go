@@ -0,0 +1,58 @@
+package dashboards
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestSupportsGrafanaVersion(t *testing.T) {
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{"path": defaultDashboards}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+	require.NoError(t, err)
+	reader.grafanaVersion = "8.5.2"
+
+	tests := []struct {
+		name      string
+		data      string
+		supported bool
+	}{
+		{name: "without range", data: `{"title": "CPU"}`, supported: true},
+		{name: "within range", data: `{"minGrafanaVersion": "8.0.0", "maxGrafanaVersion": "8.5.2"}`, supported: true},
+		{name: "at lower bound", data: `{"minGrafanaVersion": "8.5.2"}`, supported: true},
+		{name: "below range", data: `{"minGrafanaVersion": "9.0.0"}`, supported: false},
+		{name: "above range", data: `{"maxGrafanaVersion": "8.4"}`, supported: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := simplejson.NewJson([]byte(tt.data))
+			require.NoError(t, err)
+
+			supported, err := reader.supportsGrafanaVersion(data)
+			require.NoError(t, err)
+			require.Equal(t, tt.supported, supported)
+		})
+	}
+
+	t.Run("should reject invalid versions", func(t *testing.T) {
+		data, err := simplejson.NewJson([]byte(`{"minGrafanaVersion": "latest"}`))
+		require.NoError(t, err)
+
+		_, err = reader.supportsGrafanaVersion(data)
+		require.True(t, errors.Is(err, ErrInvalidDashboard))
+	})
+
+	t.Run("should support every version of development builds", func(t *testing.T) {
+		reader.grafanaVersion = ""
+		data, err := simplejson.NewJson([]byte(`{"minGrafanaVersion": "100.0.0"}`))
+		require.NoError(t, err)
+
+		supported, err := reader.supportsGrafanaVersion(data)
+		require.NoError(t, err)
+		require.True(t, supported)
+	})
+}
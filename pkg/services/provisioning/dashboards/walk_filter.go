@@ -0,0 +1,78 @@
+package dashboards
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// walkFilter decides which directories are descended into and which files are picked up while walking the path
+// of a provider.
+type walkFilter struct {
+	fileExtensions []string
+	// caseInsensitiveExtensions matches the file extensions regardless of case, so that `.JSON` files are picked up.
+	caseInsensitiveExtensions bool
+	// skipHiddenDirs skips the directories whose name starts with a dot, unless they match includeDirs.
+	skipHiddenDirs bool
+	includeDirs    []string
+	excludeDirs    []string
+}
+
+func (fr *FileReader) walkFilter() walkFilter {
+	return walkFilter{
+		fileExtensions:            fr.FileExtensions,
+		caseInsensitiveExtensions: fr.CaseInsensitiveExtensions,
+		skipHiddenDirs:            fr.SkipHiddenDirs,
+		includeDirs:               fr.IncludeDirs,
+		excludeDirs:               fr.ExcludeDirs,
+	}
+}
+
+// skipDir reports whether the directory called name, and everything below it, is left out of the walk.
+func (f walkFilter) skipDir(name string) bool {
+	if matchesAnyName(f.excludeDirs, name) {
+		return true
+	}
+	return f.skipHiddenDirs && strings.HasPrefix(name, ".") && !matchesAnyName(f.includeDirs, name)
+}
+
+func (f walkFilter) hasFileExtension(name string) bool {
+	if !f.caseInsensitiveExtensions {
+		return hasFileExtension(name, f.fileExtensions)
+	}
+
+	name = strings.ToLower(name)
+	for _, extension := range f.fileExtensions {
+		if strings.HasSuffix(name, strings.ToLower(extension)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// dirPatternsOption reads a list of directory names, which may contain glob metacharacters.
+func dirPatternsOption(options map[string]interface{}, key string) ([]string, error) {
+	patterns, _, err := stringListOption(options, key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, "/") {
+			return nil, fmt.Errorf("option %q must list directory names, got %q", key, pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in option %q: %w", pattern, key, err)
+		}
+	}
+	return patterns, nil
+}
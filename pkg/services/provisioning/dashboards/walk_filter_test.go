@@ -0,0 +1,70 @@
+package dashboards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestWalkFilter(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"cpu.json", ".shared/memory.json", ".git/config.json", "archive/old.json", "export/disk.JSON"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0750))
+		require.NoError(t, os.WriteFile(p, []byte(`{"title": "Test"}`), 0600))
+	}
+
+	walk := func(t *testing.T, options map[string]interface{}) []string {
+		t.Helper()
+		options["path"] = dir
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: options}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+
+		files := map[string]os.FileInfo{}
+		require.NoError(t, reader.walkFiles(dir, files))
+		var names []string
+		for p := range files {
+			rel, err := filepath.Rel(dir, p)
+			require.NoError(t, err)
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return names
+	}
+
+	t.Run("should skip hidden directories by default", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"cpu.json", "archive/old.json"}, walk(t, map[string]interface{}{}))
+	})
+
+	t.Run("should walk hidden directories when skipHiddenDirs is disabled", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"cpu.json", ".shared/memory.json", ".git/config.json", "archive/old.json"},
+			walk(t, map[string]interface{}{"skipHiddenDirs": false}))
+	})
+
+	t.Run("should walk the included hidden directories", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"cpu.json", ".shared/memory.json", "archive/old.json"},
+			walk(t, map[string]interface{}{"includeDirs": []interface{}{".shared"}}))
+	})
+
+	t.Run("should skip the excluded directories", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"cpu.json"}, walk(t, map[string]interface{}{"excludeDirs": []interface{}{"arch*"}}))
+	})
+
+	t.Run("should match extensions regardless of case", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"cpu.json", "archive/old.json", "export/disk.JSON"},
+			walk(t, map[string]interface{}{"caseInsensitiveExtensions": true}))
+	})
+
+	t.Run("should reject directory paths", func(t *testing.T) {
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]interface{}{
+			"path":        dir,
+			"includeDirs": []interface{}{"team/.shared"},
+		}}
+		_, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
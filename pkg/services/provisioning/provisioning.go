@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -47,6 +48,7 @@ func ProvideService(
 	pluginSettings pluginsettings.Service,
 	searchService searchV2.SearchService,
 	quotaService quota.Service,
+	secretsService secrets.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -67,6 +69,7 @@ func ProvideService(
 		pluginsSettings:              pluginSettings,
 		searchService:                searchService,
 		quotaService:                 quotaService,
+		secretsService:               secretsService,
 		log:                          log.New("provisioning"),
 	}
 	return s, nil
@@ -79,9 +82,18 @@ type ProvisioningService interface {
 	ProvisionPlugins(ctx context.Context) error
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
+	ReprovisionDashboards(ctx context.Context) error
 	ProvisionAlertRules(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	GetDashboardProvisionersStatus() []dashboards.ProvisionerStatus
+	GetDashboardProvidersConfig() []dashboards.ProviderConfig
+	ValidateDashboard(name string, path string, content []byte) (dashboards.DashboardValidation, error)
+	PromoteDashboardProvisionerCanary(name string) error
+	UnprovisionAllDashboards(ctx context.Context, name string, deleteDashboards bool) (int, error)
+	ExportDashboards(ctx context.Context, name string, orgID int64, dir string) (int, error)
+	RunDashboardProvisioners(ctx context.Context, name string) ([]dashboards.ProvisionerStatus, error)
+	RegisterDashboardTransformers(transformers ...dashboards.DashboardTransformer)
 }
 
 // Add a public constructor for overriding service to be able to instantiate OSS as fallback
@@ -137,6 +149,8 @@ type ProvisioningServiceImpl struct {
 	pluginsSettings              pluginsettings.Service
 	searchService                searchV2.SearchService
 	quotaService                 quota.Service
+	secretsService               secrets.Service
+	dashboardTransformers        []dashboards.DashboardTransformer
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -188,8 +202,9 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 			// Polling was canceled.
 			continue
 		case <-ctx.Done():
-			// Root server context was cancelled so cancel polling and leave.
+			// Root server context was cancelled so cancel polling and leave once the dashboards being saved are.
 			ps.cancelPolling()
+			ps.dashboardProvisioner.Wait()
 			return ctx.Err()
 		}
 	}
@@ -226,15 +241,36 @@ func (ps *ProvisioningServiceImpl) ProvisionNotifications(ctx context.Context) e
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) error {
+	return ps.provisionDashboards(ctx, false)
+}
+
+// ReprovisionDashboards reloads the dashboard provisioners like ProvisionDashboards, saving every dashboard again
+// on their first run regardless of their checksum.
+func (ps *ProvisioningServiceImpl) ReprovisionDashboards(ctx context.Context) error {
+	return ps.provisionDashboards(ctx, true)
+}
+
+func (ps *ProvisioningServiceImpl) provisionDashboards(ctx context.Context, force bool) error {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
-	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.dashboardProvisioningService, ps.SQLStore, ps.dashboardService)
+	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.Cfg.DataPath, ps.dashboardProvisioningService, ps.SQLStore, ps.dashboardService,
+		ps.Cfg.ProvisioningStrictConfig, ps.Cfg.ProvisioningOrphanStrategy, ps.Cfg.ProvisioningMaxConcurrentRuns)
 	if err != nil {
 		return fmt.Errorf("%v: %w", "Failed to create provisioner", err)
 	}
+	if ps.secretsService != nil {
+		dashProvisioner.SetSecretsDecrypter(ps.secretsService)
+	}
+	if force {
+		dashProvisioner.ForceReprovision()
+	}
 
 	ps.mutex.Lock()
 	defer ps.mutex.Unlock()
 
+	if len(ps.dashboardTransformers) > 0 {
+		dashProvisioner.SetTransformers(ps.dashboardTransformers...)
+	}
+
 	ps.cancelPolling()
 	dashProvisioner.CleanUpOrphanedDashboards(ctx)
 
@@ -278,6 +314,53 @@ func (ps *ProvisioningServiceImpl) GetAllowUIUpdatesFromConfig(name string) bool
 	return ps.dashboardProvisioner.GetAllowUIUpdatesFromConfig(name)
 }
 
+func (ps *ProvisioningServiceImpl) GetDashboardProvisionersStatus() []dashboards.ProvisionerStatus {
+	return ps.dashboardProvisioner.GetProvisionersStatus()
+}
+
+func (ps *ProvisioningServiceImpl) GetDashboardProvidersConfig() []dashboards.ProviderConfig {
+	return ps.dashboardProvisioner.GetProvidersConfig()
+}
+
+// ValidateDashboard reads content the same way the dashboard provisioner with the given name would provision it,
+// without saving anything.
+func (ps *ProvisioningServiceImpl) ValidateDashboard(name string, path string, content []byte) (dashboards.DashboardValidation, error) {
+	return ps.dashboardProvisioner.ValidateDashboard(name, path, content)
+}
+
+// PromoteDashboardProvisionerCanary switches the dashboard provisioner with the given name from its canary to its
+// whole path.
+func (ps *ProvisioningServiceImpl) PromoteDashboardProvisionerCanary(name string) error {
+	return ps.dashboardProvisioner.PromoteCanary(name)
+}
+
+// UnprovisionAllDashboards removes every dashboard provisioned by the dashboard provisioner with the given name,
+// deleting them if deleteDashboards is set and only unprovisioning them otherwise. It returns the number of dashboards
+// removed.
+func (ps *ProvisioningServiceImpl) UnprovisionAllDashboards(ctx context.Context, name string, deleteDashboards bool) (int, error) {
+	return ps.dashboardProvisioner.UnprovisionAll(ctx, name, deleteDashboards)
+}
+
+// ExportDashboards writes the dashboards of the organization orgID which aren't provisioned as dashboard files into
+// dir, laid out for the dashboard provisioner with the given name. It returns the number of dashboards written.
+func (ps *ProvisioningServiceImpl) ExportDashboards(ctx context.Context, name string, orgID int64, dir string) (int, error) {
+	return ps.dashboardProvisioner.Export(ctx, name, orgID, dir)
+}
+
+// RunDashboardProvisioners runs the dashboard provisioner with the given name right away, or every dashboard
+// provisioner when name is empty, and returns their status once done.
+func (ps *ProvisioningServiceImpl) RunDashboardProvisioners(ctx context.Context, name string) ([]dashboards.ProvisionerStatus, error) {
+	return ps.dashboardProvisioner.RunProviders(ctx, name)
+}
+
+// RegisterDashboardTransformers adds transformers applied to every provisioned dashboard before it is saved. They
+// should be registered before the service runs, later registrations only apply once dashboards are provisioned again.
+func (ps *ProvisioningServiceImpl) RegisterDashboardTransformers(transformers ...dashboards.DashboardTransformer) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	ps.dashboardTransformers = append(ps.dashboardTransformers, transformers...)
+}
+
 func (ps *ProvisioningServiceImpl) cancelPolling() {
 	if ps.pollingCtxCancel != nil {
 		ps.log.Debug("Stop polling for dashboard changes")
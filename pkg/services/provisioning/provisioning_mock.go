@@ -1,6 +1,10 @@
 package provisioning
 
-import "context"
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+)
 
 type Calls struct {
 	RunInitProvisioners                 []interface{}
@@ -8,9 +12,18 @@ type Calls struct {
 	ProvisionPlugins                    []interface{}
 	ProvisionNotifications              []interface{}
 	ProvisionDashboards                 []interface{}
+	ReprovisionDashboards               []interface{}
 	ProvisionAlertRules                 []interface{}
 	GetDashboardProvisionerResolvedPath []interface{}
 	GetAllowUIUpdatesFromConfig         []interface{}
+	GetDashboardProvisionersStatus      []interface{}
+	GetDashboardProvidersConfig         []interface{}
+	ValidateDashboard                   []interface{}
+	PromoteDashboardProvisionerCanary   []interface{}
+	UnprovisionAllDashboards            []interface{}
+	ExportDashboards                    []interface{}
+	RunDashboardProvisioners            []interface{}
+	RegisterDashboardTransformers       []interface{}
 	Run                                 []interface{}
 }
 
@@ -21,8 +34,17 @@ type ProvisioningServiceMock struct {
 	ProvisionPluginsFunc                    func() error
 	ProvisionNotificationsFunc              func() error
 	ProvisionDashboardsFunc                 func() error
+	ReprovisionDashboardsFunc               func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
+	GetDashboardProvisionersStatusFunc      func() []dashboards.ProvisionerStatus
+	GetDashboardProvidersConfigFunc         func() []dashboards.ProviderConfig
+	ValidateDashboardFunc                   func(name string, path string, content []byte) (dashboards.DashboardValidation, error)
+	PromoteDashboardProvisionerCanaryFunc   func(name string) error
+	UnprovisionAllDashboardsFunc            func(ctx context.Context, name string, deleteDashboards bool) (int, error)
+	ExportDashboardsFunc                    func(ctx context.Context, name string, orgID int64, dir string) (int, error)
+	RunDashboardProvisionersFunc            func(ctx context.Context, name string) ([]dashboards.ProvisionerStatus, error)
+	RegisterDashboardTransformersFunc       func(transformers ...dashboards.DashboardTransformer)
 	RunFunc                                 func(ctx context.Context) error
 }
 
@@ -72,6 +94,14 @@ func (mock *ProvisioningServiceMock) ProvisionDashboards(ctx context.Context) er
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ReprovisionDashboards(ctx context.Context) error {
+	mock.Calls.ReprovisionDashboards = append(mock.Calls.ReprovisionDashboards, nil)
+	if mock.ReprovisionDashboardsFunc != nil {
+		return mock.ReprovisionDashboardsFunc()
+	}
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) ProvisionAlertRules(ctx context.Context) error {
 	mock.Calls.ProvisionAlertRules = append(mock.Calls.ProvisionAlertRules, nil)
 	return nil
@@ -93,6 +123,69 @@ func (mock *ProvisioningServiceMock) GetAllowUIUpdatesFromConfig(name string) bo
 	return false
 }
 
+func (mock *ProvisioningServiceMock) GetDashboardProvisionersStatus() []dashboards.ProvisionerStatus {
+	mock.Calls.GetDashboardProvisionersStatus = append(mock.Calls.GetDashboardProvisionersStatus, nil)
+	if mock.GetDashboardProvisionersStatusFunc != nil {
+		return mock.GetDashboardProvisionersStatusFunc()
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) GetDashboardProvidersConfig() []dashboards.ProviderConfig {
+	mock.Calls.GetDashboardProvidersConfig = append(mock.Calls.GetDashboardProvidersConfig, nil)
+	if mock.GetDashboardProvidersConfigFunc != nil {
+		return mock.GetDashboardProvidersConfigFunc()
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ValidateDashboard(name string, path string, content []byte) (dashboards.DashboardValidation, error) {
+	mock.Calls.ValidateDashboard = append(mock.Calls.ValidateDashboard, name)
+	if mock.ValidateDashboardFunc != nil {
+		return mock.ValidateDashboardFunc(name, path, content)
+	}
+	return dashboards.DashboardValidation{}, nil
+}
+
+func (mock *ProvisioningServiceMock) PromoteDashboardProvisionerCanary(name string) error {
+	mock.Calls.PromoteDashboardProvisionerCanary = append(mock.Calls.PromoteDashboardProvisionerCanary, name)
+	if mock.PromoteDashboardProvisionerCanaryFunc != nil {
+		return mock.PromoteDashboardProvisionerCanaryFunc(name)
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) UnprovisionAllDashboards(ctx context.Context, name string, deleteDashboards bool) (int, error) {
+	mock.Calls.UnprovisionAllDashboards = append(mock.Calls.UnprovisionAllDashboards, name)
+	if mock.UnprovisionAllDashboardsFunc != nil {
+		return mock.UnprovisionAllDashboardsFunc(ctx, name, deleteDashboards)
+	}
+	return 0, nil
+}
+
+func (mock *ProvisioningServiceMock) ExportDashboards(ctx context.Context, name string, orgID int64, dir string) (int, error) {
+	mock.Calls.ExportDashboards = append(mock.Calls.ExportDashboards, name)
+	if mock.ExportDashboardsFunc != nil {
+		return mock.ExportDashboardsFunc(ctx, name, orgID, dir)
+	}
+	return 0, nil
+}
+
+func (mock *ProvisioningServiceMock) RunDashboardProvisioners(ctx context.Context, name string) ([]dashboards.ProvisionerStatus, error) {
+	mock.Calls.RunDashboardProvisioners = append(mock.Calls.RunDashboardProvisioners, name)
+	if mock.RunDashboardProvisionersFunc != nil {
+		return mock.RunDashboardProvisionersFunc(ctx, name)
+	}
+	return nil, nil
+}
+
+func (mock *ProvisioningServiceMock) RegisterDashboardTransformers(transformers ...dashboards.DashboardTransformer) {
+	mock.Calls.RegisterDashboardTransformers = append(mock.Calls.RegisterDashboardTransformers, transformers)
+	if mock.RegisterDashboardTransformersFunc != nil {
+		mock.RegisterDashboardTransformersFunc(transformers...)
+	}
+}
+
 func (mock *ProvisioningServiceMock) Run(ctx context.Context) error {
 	mock.Calls.Run = append(mock.Calls.Run, nil)
 	if mock.RunFunc != nil {
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	dashboardstore "github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
@@ -63,6 +64,16 @@ func TestProvisioningServiceImpl(t *testing.T) {
 		// Cancelling the root context and stopping the service
 		serviceTest.cancel()
 	})
+
+	t.Run("Registered dashboard transformers are set on the provisioner", func(t *testing.T) {
+		serviceTest := setup()
+		serviceTest.service.RegisterDashboardTransformers(func(data *simplejson.Json) error { return nil })
+
+		err := serviceTest.service.ProvisionDashboards(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(serviceTest.mock.Calls.SetTransformers), "SetTransformers should have been called")
+		assert.Len(t, serviceTest.mock.Calls.SetTransformers[0], 1)
+	})
 }
 
 type serviceTestStruct struct {
@@ -93,7 +104,7 @@ func setup() *serviceTestStruct {
 	}
 
 	serviceTest.service = newProvisioningServiceImpl(
-		func(context.Context, string, dashboardstore.DashboardProvisioningService, utils.OrgStore, utils.DashboardStore) (dashboards.DashboardProvisioner, error) {
+		func(context.Context, string, string, dashboardstore.DashboardProvisioningService, utils.OrgStore, utils.DashboardStore, bool, string, int) (dashboards.DashboardProvisioner, error) {
 			return serviceTest.mock, nil
 		},
 		nil,
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -290,6 +291,10 @@ func interpolateValue(val string) (string, string, error) {
 	parts := strings.Split(val, "$$")
 	interpolated := make([]string, len(parts))
 	for i, v := range parts {
+		// the expanders would look up `NAME:-default` as the name of a variable, so defaults are resolved first
+		v = envDefaultPattern.ReplaceAllStringFunc(v, func(match string) string {
+			return expandEnvWithDefault(match[2 : len(match)-1])
+		})
 		expanded, err := setting.ExpandVar(v)
 		if err != nil {
 			return val, val, fmt.Errorf("failed to interpolate value '%s': %w", val, err)
@@ -300,6 +305,24 @@ func interpolateValue(val string) (string, string, error) {
 	return strings.Join(interpolated, "$"), val, nil
 }
 
+// envDefaultPattern matches the `${NAME:-default}` syntax, which falls back to default when the environment
+// variable is unset or empty.
+var envDefaultPattern = regexp.MustCompile(`\$\{[a-zA-Z_][a-zA-Z0-9_]*:-[^}]*\}`)
+
+// expandEnvWithDefault returns the value of the environment variable name, or the default given as `NAME:-default`
+// when the variable is unset or empty.
+func expandEnvWithDefault(name string) string {
+	i := strings.Index(name, ":-")
+	if i < 0 {
+		return os.Getenv(name)
+	}
+
+	if value := os.Getenv(name[:i]); value != "" {
+		return value
+	}
+	return name[i+2:]
+}
+
 type interpolated struct {
 	value string
 	raw   string
@@ -91,6 +91,20 @@ func TestValues(t *testing.T) {
 				require.Equal(t, d.Val.Raw, "$EMPTYSTRING")
 			})
 
+			t.Run("Should use the default of unset or empty env var", func(t *testing.T) {
+				unmarshalingTest(t, `val: ${UNSET_STRING:-fallback}`, d)
+				require.Equal(t, d.Val.Value(), "fallback")
+				require.Equal(t, d.Val.Raw, "${UNSET_STRING:-fallback}")
+
+				unmarshalingTest(t, `val: ${EMPTYSTRING:-fallback}`, d)
+				require.Equal(t, d.Val.Value(), "fallback")
+			})
+
+			t.Run("Should ignore the default of set env var", func(t *testing.T) {
+				unmarshalingTest(t, `val: ${STRING:-fallback}`, d)
+				require.Equal(t, d.Val.Value(), "test")
+			})
+
 			t.Run("$$ should be a literal $", func(t *testing.T) {
 				unmarshalingTest(t, `val: $$`, d)
 				require.Equal(t, d.Val.Value(), "$")
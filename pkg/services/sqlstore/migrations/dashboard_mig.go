@@ -234,4 +234,8 @@ func addDashboardMigration(mg *Migrator) {
 	mg.AddMigration("Add isPublic for dashboard", NewAddColumnMigration(dashboardV2, &Column{
 		Name: "is_public", Type: DB_Bool, Nullable: false, Default: "0",
 	}))
+
+	mg.AddMigration("Add last_error column", NewAddColumnMigration(dashboardExtrasTableV2, &Column{
+		Name: "last_error", Type: DB_Text, Nullable: true,
+	}))
 }
@@ -278,7 +278,11 @@ type Cfg struct {
 	MetricsGrafanaEnvironmentInfo    map[string]string
 
 	// Dashboards
-	DefaultHomeDashboardPath string
+	DefaultHomeDashboardPath      string
+	ProvisioningStrictConfig      bool
+	ProvisioningOrphanStrategy    string
+	ProvisioningMaxConcurrentRuns int
+	ProvisioningWebhookSecret     string
 
 	// Auth
 	LoginCookieName              string
@@ -926,6 +930,10 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	cfg.ProvisioningStrictConfig = dashboards.Key("provisioning_strict_config").MustBool(false)
+	cfg.ProvisioningOrphanStrategy = valueAsString(dashboards, "provisioning_orphan_strategy", "delete")
+	cfg.ProvisioningMaxConcurrentRuns = dashboards.Key("provisioning_max_concurrent_runs").MustInt(0)
+	cfg.ProvisioningWebhookSecret = dashboards.Key("provisioning_webhook_secret").MustString("")
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
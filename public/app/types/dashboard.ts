@@ -26,6 +26,11 @@ export interface DashboardMeta {
   submenuEnabled?: boolean;
   provisioned?: boolean;
   provisionedExternalId?: string;
+  provisionedBy?: string;
+  provisionedCheckSum?: string;
+  provisionedUpdated?: string;
+  provisionedLastRun?: string;
+  provisionedLastError?: string;
   isStarred?: boolean;
   showSettings?: boolean;
   expires?: string;